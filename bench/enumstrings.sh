@@ -0,0 +1,45 @@
+#!/bin/sh
+# Measures the getEnumString implementations against each other: generated
+# header size, compile time and object size of a translation unit calling
+# getEnumString for every enum, with switch statements vs lookup tables.
+# The Go toolchain has to be able to build the generator from this tree.
+#
+# usage: bench/enumstrings.sh <spec_file> [compiler flags]
+#
+# CXX selects the compiler (c++ by default), vulkan/vulkan.h matching the spec
+# has to be on the include path.
+set -e
+
+spec=$1
+shift
+cxx=${CXX:-c++}
+dir=$(mktemp -d)
+trap 'rm -rf "$dir"' EXIT
+
+(cd "$(dirname "$0")/.." && go build -o "$dir/vulkangen" .)
+
+printf '%-8s %12s %10s %12s\n' mode header_bytes compile_s object_bytes
+# switch everywhere, the default threshold, tables everywhere
+for mode in switch:0 default:64 table:1; do
+	threshold=${mode#*:}
+	mode=${mode%:*}
+	# platform and provisional enums are guarded, leave them out
+	"$dir/vulkangen" -skip-broken -platforms=none -provisional=false \
+		-enum-string-table $threshold -o "$dir/vk.hpp" "$spec" 2>/dev/null
+	{
+		echo '#include "vk.hpp"'
+		echo 'const char *volatile sink;'
+		echo 'void use(int v) {'
+		# getEnumString is declared constexpr, or inline VKGEN_CONSTEXPR14
+		# where it needs C++14
+		sed -n 's/^[A-Za-z0-9_ ]*const char \*getEnumString(\([A-Za-z0-9_]*\) e)$/	sink = vk::getEnumString(static_cast<vk::\1>(v));/p' "$dir/vk.hpp" | sort -u
+		echo '}'
+	} > "$dir/tu.cpp"
+	start=$(date +%s.%N)
+	"$cxx" -std=c++11 -O2 -c "$@" -I"$dir" -o "$dir/tu.o" "$dir/tu.cpp"
+	end=$(date +%s.%N)
+	printf '%-8s %12d %10.2f %12d\n' $mode \
+		"$(wc -c < "$dir/vk.hpp")" \
+		"$(awk "BEGIN { print $end - $start }")" \
+		"$(size -B "$dir/tu.o" | awk 'NR == 2 { print $4 }')"
+done
@@ -0,0 +1,18 @@
+package cppgen
+
+import "fmt"
+
+// Declaration returns the declaration of the member in an aggregate struct,
+// see Options.AggregateStructs. Members are zeroed by a default member
+// initializer, like the wrappers zero their struct, but bit-fields, which
+// can't have one before C++20.
+func (m *StructMember) Declaration() string {
+	at := &m.AnalyzedType
+	switch {
+	case at.BitWidth > 0:
+		return at.Declare(at.Type, m.Accessor(""))
+	case at.IsArray:
+		return at.Declare(convertVkName(at.Type), m.Accessor("")) + " = {}"
+	}
+	return fmt.Sprintf("%s %s = {}", m.Type, m.Accessor(""))
+}
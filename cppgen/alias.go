@@ -0,0 +1,183 @@
+package cppgen
+
+import "github.com/nsf/vulkangen/registry"
+
+// Alias is another name of a type, command or enum value, most commonly the
+// extension name of something promoted to core (or the other way around, the
+// registry decides which one is the alias). Target is the generated name of
+// the aliased entity.
+type Alias struct {
+	Protect Protect
+	Name    string
+	Target  string
+}
+
+// resolveAliases adds the aliases of generated types and commands. Aliases
+// of types share the converter of the aliased type, so that struct members
+// and parameters using either name are converted the same way. Aliases of
+// entities which are not generated are dropped.
+func (ctx *Context) resolveAliases(reg *registry.Registry, protectMap map[string]Protect) {
+	// aliases of aliases are resolved to the final target
+	typeAliases := map[string]string{}
+	for _, t := range reg.Types.Type {
+		if t.Alias != "" && !t.External {
+			typeAliases[t.Name] = t.Alias
+		}
+	}
+	for _, t := range reg.Types.Type {
+		if t.Alias == "" || t.External {
+			continue
+		}
+		target := resolveAlias(typeAliases, t.Name)
+		conv, ok := ctx.converters[target]
+		if !ok {
+			ctx.diags.diagnose("skipped", t.Name, "alias %s of unknown type %s", t.Name, target)
+			continue
+		}
+		ctx.converters[t.Name] = conv
+		ctx.TypeAliases = append(ctx.TypeAliases, Alias{
+			Protect: aliasProtect(protectMap, t.Name, target),
+			Name:    convertVkName(t.Name),
+			Target:  convertVkName(target),
+		})
+	}
+
+	commands := map[string]bool{}
+	for _, c := range ctx.Commands {
+		commands[c.VkName] = true
+	}
+	commandAliases := map[string]string{}
+	for _, c := range reg.Commands.Command {
+		if c.Alias != "" {
+			commandAliases[c.Name] = c.Alias
+		}
+	}
+	for _, c := range reg.Commands.Command {
+		if c.Alias == "" {
+			continue
+		}
+		target := resolveAlias(commandAliases, c.Name)
+		if !commands[target] {
+			ctx.diags.diagnose("skipped", c.Name, "alias %s of unknown command %s", c.Name, target)
+			continue
+		}
+		ctx.CommandAliases = append(ctx.CommandAliases, Alias{
+			Protect: aliasProtect(protectMap, c.Name, target),
+			Name:    ctx.naming.Function(convertCommandName(c.Name)),
+			Target:  ctx.naming.Function(convertCommandName(target)),
+		})
+	}
+}
+
+// resolveEnumAliases adds the aliases of enum values to their enums, aliases
+// with the same generated name as a value are left out.
+func (ctx *Context) resolveEnumAliases(reg *registry.Registry, opts *Options, enumMap map[string]*Enum, expandMap map[string]string) {
+	addValueAlias := func(enumName, name, target string, protect Protect) {
+		e, ok := enumMap[enumName]
+		if !ok {
+			return
+		}
+		v := e.Value(target)
+		if v == nil {
+			return
+		}
+		cppName := ctx.names.enumValueName(ctx.naming, expandMap[enumName], enumName, name)
+		// most aliases differ from the target only by the tag suffix,
+		// which enum value names don't have
+		if e.hasName(cppName) {
+			return
+		}
+		if protect.Begin == "" {
+			protect = v.Protect
+		}
+		e.Aliases = append(e.Aliases, Alias{Protect: protect, Name: cppName, Target: v.Name})
+	}
+	for _, xe := range reg.Enums {
+		if xe.External {
+			continue
+		}
+		for _, v := range xe.Values {
+			if v.Alias != "" {
+				addValueAlias(xe.Name, v.Name, v.Alias, Protect{})
+			}
+		}
+	}
+	addRequireAliases := func(req *registry.Require, protect Protect) {
+		for _, re := range req.Enums {
+			if re.Extends != "" && re.Alias != "" {
+				addValueAlias(re.Extends, re.Name, re.Alias, protect)
+			}
+		}
+	}
+	for i := range reg.Features {
+		addRequireAliases(&reg.Features[i].Require, Protect{})
+	}
+	for i := range reg.Extensions.Extension {
+		e := &reg.Extensions.Extension[i]
+		if opts.extensionExcluded(e) == "" {
+			addRequireAliases(&e.Require, extensionProtect(e))
+		}
+	}
+}
+
+func resolveAlias(aliases map[string]string, name string) string {
+	for i := 0; i < len(aliases); i++ {
+		target, ok := aliases[name]
+		if !ok {
+			break
+		}
+		name = target
+	}
+	return name
+}
+
+// aliasProtect returns the guard of the alias, aliases required by no guarded
+// extension get the guard of the target.
+func aliasProtect(protectMap map[string]Protect, name, target string) Protect {
+	if p, ok := protectMap[name]; ok {
+		return p
+	}
+	return protectMap[target]
+}
+
+// affixedTypeAliases returns aliases of the generated types named with
+// prefix and suffix around the name, see Options.TypePrefix. Names taken by
+// another type are left out.
+func (ctx *Context) affixedTypeAliases(prefix, suffix string) []Alias {
+	var types []Alias
+	add := func(protect Protect, name string) {
+		types = append(types, Alias{Protect: protect, Name: prefix + name + suffix, Target: name})
+	}
+	for _, e := range ctx.Enums {
+		add(e.Protect, e.Name)
+	}
+	for _, b := range ctx.BitMasks {
+		if b.Enum != nil {
+			add(b.Protect, b.Enum.Name)
+		}
+		add(b.Protect, b.Name)
+	}
+	for _, h := range ctx.Handles {
+		add(h.Protect, h.Name)
+	}
+	for _, s := range ctx.Structs {
+		add(s.Protect, s.Name)
+	}
+	for _, a := range ctx.TypeAliases {
+		add(a.Protect, a.Name)
+	}
+
+	taken := map[string]bool{}
+	for _, t := range types {
+		taken[t.Target] = true
+	}
+	var aliases []Alias
+	for _, t := range types {
+		if taken[t.Name] {
+			ctx.diags.diagnose("skipped", t.Name, "alias %s of %s clashes with a generated type", t.Name, t.Target)
+			continue
+		}
+		aliases = append(aliases, t)
+	}
+	return aliases
+}
@@ -0,0 +1,83 @@
+package cppgen
+
+import "github.com/nsf/vulkangen/registry"
+
+// generatedAPI is the API the header is generated for. vk.xml also
+// describes Vulkan SC, whose features, enumerants, members and variants of
+// types and commands carry an api attribute leaving out "vulkan".
+const generatedAPI = "vulkan"
+
+// selectAPI removes the entities of other APIs than generatedAPI from reg,
+// so that nothing walking the registry afterwards sees them. Extensions
+// aren't removed, those not supported by the API are excluded by the
+// options, see extensionUnavailable.
+func selectAPI(reg *registry.Registry) {
+	types := reg.Types.Type[:0]
+	for _, t := range reg.Types.Type {
+		if registry.HasAPI(t.API, generatedAPI) {
+			t.Members = selectMembers(t.Members)
+			types = append(types, t)
+		}
+	}
+	reg.Types.Type = types
+	for i := range reg.Enums {
+		e := &reg.Enums[i]
+		values := e.Values[:0]
+		for _, v := range e.Values {
+			if registry.HasAPI(v.API, generatedAPI) {
+				values = append(values, v)
+			}
+		}
+		e.Values = values
+	}
+	commands := reg.Commands.Command[:0]
+	for _, c := range reg.Commands.Command {
+		if registry.HasAPI(c.API, generatedAPI) {
+			c.Params = selectMembers(c.Params)
+			commands = append(commands, c)
+		}
+	}
+	reg.Commands.Command = commands
+	features := reg.Features[:0]
+	for _, f := range reg.Features {
+		if registry.HasAPI(f.API, generatedAPI) {
+			selectRequire(&f.Require)
+			features = append(features, f)
+		}
+	}
+	reg.Features = features
+	for i := range reg.Extensions.Extension {
+		selectRequire(&reg.Extensions.Extension[i].Require)
+	}
+}
+
+func selectMembers(members []registry.TypeName) []registry.TypeName {
+	selected := members[:0]
+	for _, m := range members {
+		if registry.HasAPI(m.API, generatedAPI) {
+			selected = append(selected, m)
+		}
+	}
+	return selected
+}
+
+func selectRequire(r *registry.Require) {
+	names := func(list []registry.RequireName) []registry.RequireName {
+		selected := list[:0]
+		for _, n := range list {
+			if registry.HasAPI(n.API, generatedAPI) {
+				selected = append(selected, n)
+			}
+		}
+		return selected
+	}
+	r.Types = names(r.Types)
+	r.Commands = names(r.Commands)
+	enums := r.Enums[:0]
+	for _, e := range r.Enums {
+		if registry.HasAPI(e.API, generatedAPI) {
+			enums = append(enums, e)
+		}
+	}
+	r.Enums = enums
+}
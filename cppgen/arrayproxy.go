@@ -0,0 +1,146 @@
+package cppgen
+
+import (
+	"strings"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// ArrayOverload describes the overload of a command taking an ArrayProxy in
+// place of each count and pointer pair of input arrays, vkQueueSubmit's
+// submitCount and pSubmits. The overload calls the command with Arguments.
+// Arrays sharing a count must be of the same size, which is asserted.
+type ArrayOverload struct {
+	Command    string
+	RetType    string
+	Parameters []ArrayParameter
+	Arguments  []string
+	Asserts    []string
+	Noexcept   string
+}
+
+type ArrayParameter struct {
+	Type string
+	Name string
+}
+
+// newArrayOverload returns the ArrayProxy overload of the command, or nil if
+// none of its parameters is an input array with a uint32_t count. A count
+// also giving the length of an output array is kept as is.
+func (ctx *Context) newArrayOverload(cmd *Command, c *registry.Command) *ArrayOverload {
+	counts := map[string][]int{} // count name -> arrays
+	for i, p := range c.Params {
+		if p.Len != "" {
+			counts[p.Len] = append(counts[p.Len], i)
+		}
+	}
+	collapsed := map[string]bool{}
+	for i, p := range c.Params {
+		arrays := counts[p.Name]
+		if len(arrays) == 0 || p.Type != "uint32_t" || !cmd.Parameters[i].AnalyzedType.IsBlank {
+			continue
+		}
+		ok := true
+		for _, j := range arrays {
+			at := &cmd.Parameters[j].AnalyzedType
+			if j < i || !at.IsConst || at.Suffix() != "*" || at.IsArray || at.Type == "void" {
+				ok = false
+			}
+		}
+		collapsed[p.Name] = ok
+	}
+
+	// the first array of a count gives its size
+	sizes := map[string]string{}
+	for i, p := range c.Params {
+		if _, ok := sizes[p.Len]; !ok && collapsed[p.Len] {
+			sizes[p.Len] = arrayProxyName(cmd.Parameters[i].Name) + ".size()"
+		}
+	}
+	if len(sizes) == 0 {
+		return nil
+	}
+
+	o := &ArrayOverload{Command: cmd.Name, RetType: cmd.RetType, Noexcept: cmd.Noexcept()}
+	for i, p := range c.Params {
+		name := cmd.Parameters[i].Name
+		switch {
+		case collapsed[p.Name]:
+			o.Arguments = append(o.Arguments, sizes[p.Name])
+		case collapsed[p.Len]:
+			proxy := arrayProxyName(name)
+			o.Parameters = append(o.Parameters, ArrayParameter{
+				Type: "ArrayProxy<" + convertVkName(p.Type) + ">",
+				Name: proxy,
+			})
+			o.Arguments = append(o.Arguments, proxy+".data()")
+			if size := proxy + ".size()"; size != sizes[p.Len] {
+				o.Asserts = append(o.Asserts, size+" == "+sizes[p.Len])
+			}
+		default:
+			o.Parameters = append(o.Parameters, ArrayParameter{Type: cmd.Parameters[i].Type, Name: name})
+			o.Arguments = append(o.Arguments, name)
+		}
+	}
+	return o
+}
+
+// arrayProxyName names the ArrayProxy of an array parameter, pSubmits ->
+// submits
+func arrayProxyName(name string) string {
+	if len(name) > 1 && name[0] == 'p' && strings.ToUpper(name[1:2]) == name[1:2] {
+		return strings.ToLower(name[1:2]) + name[2:]
+	}
+	return name
+}
+
+// SpanSetter is the setter of a struct member pointing to an input array
+// which takes a std::span and assigns the count member too,
+// BufferCreateInfo::queueFamilyIndices for pQueueFamilyIndices and
+// queueFamilyIndexCount. Arrays sharing a count each set it. Type is the
+// element type of the span, const uint32_t.
+type SpanSetter struct {
+	Name  string
+	Type  string
+	Count string
+}
+
+// resolveSpanSetters gives the setters of C++20 structs a SpanSetter for each
+// const pointer member whose len is a uint32_t member, unless its name
+// clashes with an accessor. Arrays of strings (ppEnabledExtensionNames) take
+// a span of C strings.
+func (ctx *Context) resolveSpanSetters() {
+	for i := range ctx.Structs {
+		s := &ctx.Structs[i]
+		if s.ReadOnly || s.Aggregate {
+			continue
+		}
+		accessors := map[string]bool{}
+		counts := map[string]bool{}
+		for _, m := range s.Members {
+			accessors[m.Accessor("")] = true
+			if m.AnalyzedType.IsBlank && m.AnalyzedType.Type == "uint32_t" {
+				counts[m.Name] = true
+			}
+		}
+		for j := range s.Members {
+			m := &s.Members[j]
+			at := &m.AnalyzedType
+			count := strings.TrimSuffix(m.Len, ",null-terminated")
+			typ, name := "const "+convertVkName(at.Type), arrayProxyName(m.Name)
+			if at.Type == "char" && at.Suffix() == "* const*" && count != m.Len {
+				typ, name = "const char *const", arrayProxyName(m.Name[1:])
+			} else if count != m.Len || at.Suffix() != "*" || at.Type == "void" {
+				continue
+			}
+			if !counts[count] || !at.IsConst || at.IsArray {
+				continue
+			}
+			name = ctx.naming.Member(name)
+			if accessors[name] {
+				continue
+			}
+			m.Span = &SpanSetter{Name: name, Type: typ, Count: count}
+		}
+	}
+}
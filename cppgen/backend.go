@@ -0,0 +1,124 @@
+package cppgen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// backend generates one output file of a run from the context. Backends only read the
+// context and run concurrently.
+type backend struct {
+	name string
+	file string // "" writes to STDOUT
+	lang string // the language of the output, a -lang or cmake, markdown
+	emit func(w io.Writer) error
+}
+
+// Backend generates the output of a -lang from the context. The C++ header
+// and the other languages are Backends registering themselves from an init
+// function of the file defining them, new ones are added the same way,
+// without changing the rest of the generator. Generate may run concurrently
+// with other backends, it mustn't modify the context.
+type Backend interface {
+	// Name is the value of -lang selecting the backend
+	Name() string
+	Generate(w io.Writer, ctx *Context, opts Options) error
+}
+
+// languageBackends are the registered Backends by name.
+var languageBackends = map[string]Backend{}
+
+// RegisterBackend makes b the backend of -lang b.Name(), replacing the one
+// registered before under that name.
+func RegisterBackend(b Backend) {
+	languageBackends[b.Name()] = b
+}
+
+// languageBackend writes the output of b to file.
+func languageBackend(b Backend, file string, ctx *Context, opts *Options) backend {
+	return backend{
+		name: b.Name() + " output",
+		file: file,
+		lang: b.Name(),
+		emit: func(w io.Writer) error {
+			return b.Generate(w, ctx, *opts)
+		},
+	}
+}
+
+// cppBackend generates the C++ header, or the module interface unit of
+// Options.Module.
+type cppBackend struct{}
+
+func init() {
+	RegisterBackend(cppBackend{})
+}
+
+func (cppBackend) Name() string { return "c++" }
+
+func (cppBackend) Generate(w io.Writer, ctx *Context, opts Options) error {
+	for _, t := range []struct {
+		name string
+		data interface{}
+	}{{"header", ctx.Params}, {"body", ctx}, {"footer", ctx.Params}} {
+		if err := ctx.execute(w, t.name, t.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runBackends runs the backends concurrently, each writing to its own file,
+// or only generating the output with discard. Returns the error of the first
+// failed backend in the given order.
+func runBackends(backends []backend, discard bool) error {
+	errs := make([]error, len(backends))
+	var wg sync.WaitGroup
+	for i, b := range backends {
+		wg.Add(1)
+		go func(i int, b backend) {
+			defer wg.Done()
+			run := b.run
+			if discard {
+				run = func() error { return b.emit(io.Discard) }
+			}
+			if err := run(); err != nil {
+				errs[i] = fmt.Errorf("%s: %v", b.name, err)
+			}
+		}(i, b)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *backend) run() error {
+	if b.file == "" {
+		w := bufio.NewWriter(os.Stdout)
+		if err := b.emit(w); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+	f, err := os.Create(b.file)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := b.emit(w); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,80 @@
+package cppgen
+
+import (
+	"io"
+	"log"
+	"testing"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// testSpec is the registry the tests and benchmarks generate from, a
+// trimmed vk.xml covering every kind of entity.
+const testSpec = "testdata/vk.xml"
+
+// discardLog drops what the generator logs until the test ends.
+func discardLog(tb testing.TB) {
+	logw := log.Writer()
+	log.SetOutput(io.Discard)
+	tb.Cleanup(func() { log.SetOutput(logw) })
+}
+
+// readTestRegistry reads testSpec and prepares it for a generation with
+// opts, as with -skip-broken.
+func readTestRegistry(tb testing.TB, opts *Options) (*generation, *registry.Registry) {
+	tb.Helper()
+	discardLog(tb)
+	reg, err := registry.ReadFile(testSpec)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	opts.SkipBroken = true
+	g, err := newGeneration(opts)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := g.prepareRegistry(reg); err != nil {
+		tb.Fatal(err)
+	}
+	return g, reg
+}
+
+// The benchmarks run with go test -bench ., the Interned variants build
+// the names with the interner as the generator does, the others without.
+
+func BenchmarkContext(b *testing.B) {
+	g, reg := readTestRegistry(b, NewOptions())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.buildContext(reg, nil)
+	}
+}
+
+func BenchmarkContextInterned(b *testing.B) {
+	g, reg := readTestRegistry(b, NewOptions())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.buildContext(reg, newInterner())
+	}
+}
+
+func benchmarkGenerate(b *testing.B, intern bool) {
+	g, reg := readTestRegistry(b, NewOptions())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var names *interner
+		if intern {
+			names = newInterner()
+		}
+		ctx := g.buildContext(reg, names)
+		if err := ctx.execute(io.Discard, "body", &ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGenerate(b *testing.B)         { benchmarkGenerate(b, false) }
+func BenchmarkGenerateInterned(b *testing.B) { benchmarkGenerate(b, true) }
@@ -0,0 +1,354 @@
+package cppgen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// render executes the named template for a single entity (a struct, a
+// command, ...), through the entity cache if there is one. It's the render
+// function of the templates of g.
+func (g *generation) render(name string, data interface{}) (string, error) {
+	if g.cache != nil {
+		return g.cache.render(name, data)
+	}
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, name, data)
+	return buf.String(), err
+}
+
+// analyze builds the context of reg, through the cache if there is one.
+// The diagnostics recorded building it are cached with it.
+func (g *generation) analyze(reg *registry.Registry) (Context, error) {
+	if g.cache == nil {
+		return g.newContext(reg), nil
+	}
+	path := g.cache.contextPath(reg)
+	if data, err := os.ReadFile(path); err == nil {
+		var entry contextEntry
+		// entries which can't be decoded are built again
+		if decodeSnapshot(data, &entry) == nil {
+			g.cache.count(true)
+			for _, d := range entry.Diagnostics {
+				g.diagnostics.diagnose(d.Kind, d.Entity, "%s", d.Reason)
+			}
+			ctx := entry.Context
+			g.bindContext(&ctx)
+			return ctx, nil
+		}
+	}
+	g.cache.count(false)
+	n := g.diagnostics.len()
+	entry := contextEntry{Context: g.newContext(reg)}
+	entry.Diagnostics = g.diagnostics.since(n)
+	data, err := encodeSnapshot(&entry)
+	if err == nil {
+		err = g.cache.write(path, data)
+	}
+	return entry.Context, err
+}
+
+// contextEntry is the analysis of a registry in the cache.
+type contextEntry struct {
+	Context     Context
+	Diagnostics []Diagnostic
+}
+
+var (
+	buildIDOnce sync.Once
+	buildIDText string
+)
+
+// buildID identifies the build of the generator, the Go code the analysis
+// and the templates call into. The build info of a build from a modified
+// tree, or one without version control information like a test, doesn't
+// tell it apart, the executable is digested then.
+func buildID() string {
+	buildIDOnce.Do(func() {
+		info, ok := debug.ReadBuildInfo()
+		if ok {
+			buildIDText = info.String()
+			if info.Main.Version != "" && info.Main.Version != "(devel)" {
+				return
+			}
+			for _, s := range info.Settings {
+				if s.Key == "vcs.modified" && s.Value == "false" {
+					return
+				}
+			}
+		}
+		if exe, err := os.Executable(); err == nil {
+			if data, err := os.ReadFile(exe); err == nil {
+				sum := sha256.Sum256(data)
+				buildIDText += hex.EncodeToString(sum[:])
+			}
+		}
+	})
+	return buildIDText
+}
+
+// renderCache keeps the analysis of the registry and the generated text of
+// single entities on disk. Both are keyed by the build of the generator and
+// the options. The analysis is keyed by a digest of the registry as well,
+// the text of an entity by a digest of the entity as the template sees it
+// and of the template with the templates it invokes. The entity data is
+// derived from the entity's XML subtree and carries everything else the
+// output depends on (converters of member types, guards, etc.), so an
+// entity is regenerated exactly when its text may change. Editing the
+// struct template regenerates structs only.
+//
+// Entries are never removed, the cache directory can be deleted at any time.
+type renderCache struct {
+	dir       string
+	salt      []byte
+	templates *template.Template
+
+	mu      sync.Mutex
+	digests map[string][]byte // template name -> digest
+	hits    int
+	misses  int
+}
+
+func newRenderCache(dir string, opts *Options, templates *template.Template) (*renderCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	// the files written and what's done with them don't change the text
+	key := *opts
+	key.OutputFile, key.CHeaderFile, key.FwdHeaderFile = "", "", ""
+	key.ReflectHeaderFile, key.MockFile, key.MarkdownFile = "", "", ""
+	key.IRFile, key.GraphFile, key.ReportFile, key.DiffFile = "", "", "", ""
+	key.CompileCheck, key.ClangFormat, key.ConfigFile = "", "", ""
+	key.Strict, key.CheckOnly, key.Watch = false, false, false
+	key.Format = formatFlag{}
+	h := sha256.New()
+	fmt.Fprintf(h, "vulkangen cache\x00%s\x00", buildID())
+	digestValue(h, reflect.ValueOf(&key), map[uintptr]bool{})
+	return &renderCache{
+		dir:       dir,
+		salt:      h.Sum(nil),
+		templates: templates,
+		digests:   map[string][]byte{},
+	}, nil
+}
+
+func (c *renderCache) render(name string, data interface{}) (string, error) {
+	h := sha256.New()
+	h.Write(c.salt)
+	h.Write(c.templateDigest(name))
+	digestValue(h, reflect.ValueOf(data), map[uintptr]bool{})
+	path := filepath.Join(c.dir, hex.EncodeToString(h.Sum(nil)))
+
+	if text, err := os.ReadFile(path); err == nil {
+		c.count(true)
+		return string(text), nil
+	}
+	c.count(false)
+	var buf bytes.Buffer
+	if err := c.templates.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	if err := c.write(path, buf.Bytes()); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// contextPath returns the path of the analysis of reg.
+func (c *renderCache) contextPath(reg *registry.Registry) string {
+	h := sha256.New()
+	h.Write(c.salt)
+	io.WriteString(h, "context\x00")
+	digestValue(h, reflect.ValueOf(reg), map[uintptr]bool{})
+	return filepath.Join(c.dir, "context-"+hex.EncodeToString(h.Sum(nil)))
+}
+
+// write replaces the entry at path with data. Concurrent writers of the
+// same entry write the same data.
+func (c *renderCache) write(path string, data []byte) error {
+	tmp, err := os.CreateTemp(c.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+func (c *renderCache) count(hit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if hit {
+		c.hits++
+	} else {
+		c.misses++
+	}
+}
+
+func (c *renderCache) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return fmt.Sprintf("cache: %d hits, %d misses", c.hits, c.misses)
+}
+
+// templateDigest digests the source of the named template and of the
+// templates it invokes.
+func (c *renderCache) templateDigest(name string) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d, ok := c.digests[name]; ok {
+		return d
+	}
+	h := sha256.New()
+	seen := map[string]bool{}
+	var add func(name string)
+	add = func(name string) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		t := c.templates.Lookup(name)
+		if t == nil || t.Tree == nil {
+			return
+		}
+		fmt.Fprintf(h, "%s\x00%s\x00", name, t.Tree.Root)
+		for _, n := range invokedTemplates(t.Tree.Root, nil) {
+			add(n)
+		}
+	}
+	add(name)
+	d := h.Sum(nil)
+	c.digests[name] = d
+	return d
+}
+
+// invokedTemplates appends the names of templates invoked by node to out
+func invokedTemplates(node parse.Node, out []string) []string {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n != nil {
+			for _, n := range n.Nodes {
+				out = invokedTemplates(n, out)
+			}
+		}
+	case *parse.IfNode:
+		out = invokedTemplates(n.List, out)
+		out = invokedTemplates(n.ElseList, out)
+	case *parse.RangeNode:
+		out = invokedTemplates(n.List, out)
+		out = invokedTemplates(n.ElseList, out)
+	case *parse.WithNode:
+		out = invokedTemplates(n.List, out)
+		out = invokedTemplates(n.ElseList, out)
+	case *parse.TemplateNode:
+		out = append(out, n.Name)
+	}
+	return out
+}
+
+// digestValue writes a deterministic encoding of v to h, following pointers
+// and interfaces. Pointers seen before are written as a marker only, which
+// also breaks cycles (handles and their parents). Unexported fields are
+// left out, the templates don't see them and a cached context doesn't keep
+// them.
+func digestValue(h hash.Hash, v reflect.Value, seen map[uintptr]bool) {
+	var buf [8]byte
+	writeUint := func(n uint64) {
+		binary.LittleEndian.PutUint64(buf[:], n)
+		h.Write(buf[:])
+	}
+	writeString := func(s string) {
+		writeUint(uint64(len(s)))
+		io.WriteString(h, s)
+	}
+
+	if !v.IsValid() {
+		writeString("invalid")
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			writeString("nil")
+			return
+		}
+		if seen[v.Pointer()] {
+			writeString("seen")
+			return
+		}
+		seen[v.Pointer()] = true
+		digestValue(h, v.Elem(), seen)
+	case reflect.Interface:
+		if v.IsNil() {
+			writeString("nil")
+			return
+		}
+		writeString(v.Elem().Type().String())
+		digestValue(h, v.Elem(), seen)
+	case reflect.Struct:
+		writeString(v.Type().String())
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			writeString(v.Type().Field(i).Name)
+			digestValue(h, v.Field(i), seen)
+		}
+	case reflect.Slice, reflect.Array:
+		writeUint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			digestValue(h, v.Index(i), seen)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		writeUint(uint64(len(keys)))
+		for _, k := range keys {
+			digestValue(h, k, seen)
+			digestValue(h, v.MapIndex(k), seen)
+		}
+	case reflect.String:
+		writeString(v.String())
+	case reflect.Bool:
+		if v.Bool() {
+			writeUint(1)
+		} else {
+			writeUint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeUint(math.Float64bits(v.Float()))
+	default:
+		// funcs and channels don't affect the output
+		writeString(v.Kind().String())
+	}
+}
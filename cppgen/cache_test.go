@@ -0,0 +1,171 @@
+package cppgen
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+func TestRenderCacheKey(t *testing.T) {
+	g, reg := readTestRegistry(t, NewOptions())
+	ctx := g.newContext(reg)
+	s := ctx.Structs[0]
+	dir := t.TempDir()
+
+	c, err := newRenderCache(dir, g.opts, g.templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	render := func(c *renderCache, data interface{}) string {
+		t.Helper()
+		text, err := c.render("struct", data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return text
+	}
+	first := render(c, &s)
+	if second := render(c, &s); second != first || c.hits != 1 || c.misses != 1 {
+		t.Errorf("rendering again: %s, text changed: %v", c, second != first)
+	}
+
+	// the entity as the template sees it is part of the key
+	renamed := s
+	renamed.Name += "Renamed"
+	render(c, &renamed)
+	if c.misses != 2 {
+		t.Errorf("rendering a changed struct: %s, want a miss", c)
+	}
+
+	// so are the options which change the text, not the files written
+	for _, o := range []struct {
+		what  string
+		setup func(*Options)
+		hit   bool
+	}{
+		{"-naming snake", func(o *Options) { o.Naming = "snake" }, false},
+		{"-exceptions", func(o *Options) { o.Exceptions = true }, false},
+		{"-o", func(o *Options) { o.OutputFile = "vk.hpp" }, true},
+		{"-report and -strict", func(o *Options) { o.ReportFile, o.Strict = "report.json", true }, true},
+		{"-check", func(o *Options) { o.CheckOnly = true }, true},
+	} {
+		opts := NewOptions()
+		opts.SkipBroken = true // as readTestRegistry set it
+		o.setup(opts)
+		c, err := newRenderCache(dir, opts, g.templates)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text := render(c, &s); (c.hits == 1) != o.hit || o.hit && text != first {
+			t.Errorf("rendering with %s: %s, want a hit: %v", o.what, c, o.hit)
+		}
+	}
+}
+
+// TestRenderCacheTemplateDigest checks that editing a template changes the
+// keys of the templates invoking it only.
+func TestRenderCacheTemplateDigest(t *testing.T) {
+	digests := func(text string) (structDigest, commandDigest []byte) {
+		templates := template.Must(template.New("").Parse(text))
+		c, err := newRenderCache(t.TempDir(), NewOptions(), templates)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c.templateDigest("struct"), c.templateDigest("command")
+	}
+
+	s1, c1 := digests(`{{ define "struct" }}{{ if . }}{{ template "member" . }}{{ end }}{{ end }}{{ define "member" }}m{{ end }}{{ define "command" }}c{{ end }}`)
+	s2, c2 := digests(`{{ define "struct" }}{{ if . }}{{ template "member" . }}{{ end }}{{ end }}{{ define "member" }}M{{ end }}{{ define "command" }}c{{ end }}`)
+	if bytes.Equal(s1, s2) {
+		t.Error("editing a template the struct template invokes doesn't change its digest")
+	}
+	if !bytes.Equal(c1, c2) {
+		t.Error("editing a template the command template doesn't invoke changes its digest")
+	}
+}
+
+// TestRenderCacheOutput checks that the header generated through the
+// cache is the same, whether the entities are cached yet or not.
+func TestRenderCacheOutput(t *testing.T) {
+	want := generateTestHeader(t, testSpec, nil)
+
+	opts := NewOptions()
+	opts.CacheDir = t.TempDir()
+	for _, run := range []string{"empty", "filled"} {
+		reg, err := registry.ReadFile(testSpec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g, err := newGeneration(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, err := g.prepare(reg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got bytes.Buffer
+		if err := languageBackend(cppBackend{}, "", ctx, opts).emit(&got); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got.Bytes(), want) {
+			t.Errorf("the header generated through the %s cache differs", run)
+		}
+		// the context is cached as well
+		if run == "filled" && (g.cache.misses != 0 || g.cache.hits == 0) {
+			t.Errorf("generating again: %s, want no misses", g.cache)
+		}
+		if run == "empty" && g.cache.hits != 0 {
+			t.Errorf("generating with an empty cache: %s, want no hits", g.cache)
+		}
+	}
+}
+
+// TestContextSnapshot checks that a context decoded from the cache encodes
+// as the one it was encoded from, and that changing the registry builds the
+// context again.
+func TestContextSnapshot(t *testing.T) {
+	g, reg := readTestRegistry(t, NewOptions())
+	ctx := g.newContext(reg)
+	data, err := encodeSnapshot(&ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Context
+	if err := decodeSnapshot(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	again, err := encodeSnapshot(&decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again, data) {
+		t.Error("the decoded context encodes differently")
+	}
+	handles := map[*Handle]bool{}
+	for _, h := range decoded.Handles {
+		handles[h] = true
+	}
+	for _, h := range decoded.Handles {
+		for _, p := range h.Parents {
+			if !handles[p] {
+				t.Errorf("parent %s of %s isn't a handle of the decoded context", p.Name, h.Name)
+			}
+		}
+	}
+	if err := decodeSnapshot(data[:len(data)-1], &decoded); err == nil {
+		t.Error("decoding a truncated snapshot succeeded")
+	}
+
+	c, err := newRenderCache(t.TempDir(), g.opts, g.templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := c.contextPath(reg)
+	reg.Types.Type = reg.Types.Type[1:]
+	if c.contextPath(reg) == path {
+		t.Error("changing the registry doesn't change the key of its context")
+	}
+}
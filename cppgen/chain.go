@@ -0,0 +1,89 @@
+package cppgen
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// StructExtension says that Name can be chained into the pNext chain of
+// Base, from the structextends attribute of the registry. Protects are the
+// guards of both structs, a specialization of StructExtends may only be
+// declared where both are.
+type StructExtension struct {
+	Protects []Protect
+	Name     string
+	Base     string
+}
+
+// newStructExtensions collects the structextends relations between
+// generated structs, aliases are resolved to the aliased struct. They're
+// sorted by the extending struct, then the base.
+func (ctx *Context) newStructExtensions(reg *registry.Registry) []StructExtension {
+	structs := map[string]*Struct{}
+	for i := range ctx.Structs {
+		structs[ctx.Structs[i].VkName] = &ctx.Structs[i]
+	}
+	aliases := map[string]string{}
+	for _, t := range reg.Types.Type {
+		if t.Alias != "" && !t.External {
+			aliases[t.Name] = t.Alias
+		}
+	}
+	var out []StructExtension
+	seen := map[[2]string]bool{}
+	for _, t := range reg.Types.Type {
+		if t.StructExtends == "" || t.Alias != "" || t.External {
+			continue
+		}
+		s, ok := structs[t.Name]
+		if !ok {
+			continue
+		}
+		for _, base := range strings.Split(t.StructExtends, ",") {
+			// the base may be filtered out
+			b, ok := structs[resolveAlias(aliases, base)]
+			if !ok {
+				continue
+			}
+			key := [2]string{s.Name, b.Name}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			e := StructExtension{Name: s.Name, Base: b.Name}
+			if s.Protect.Begin != "" {
+				e.Protects = append(e.Protects, s.Protect)
+			}
+			if b.Protect.Begin != "" && b.Protect != s.Protect {
+				e.Protects = append(e.Protects, b.Protect)
+			}
+			out = append(out, e)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Name != out[j].Name {
+			return out[i].Name < out[j].Name
+		}
+		return out[i].Base < out[j].Base
+	})
+	return out
+}
+
+// resolveChainQueries marks the value overloads of void commands filling a
+// struct which others extend, vkGetPhysicalDeviceFeatures2 and the like.
+// They get an overload taking the extending structs as template arguments
+// and returning them chained, see chainoverload.
+func (ctx *Context) resolveChainQueries() {
+	bases := map[string]bool{}
+	for _, e := range ctx.StructExtensions {
+		bases[e.Base] = true
+	}
+	for i := range ctx.Commands {
+		v := ctx.Commands[i].Value
+		if v != nil && v.Plain && v.Count == "" && bases[v.Type] {
+			v.Chain = true
+		}
+	}
+}
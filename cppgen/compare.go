@@ -0,0 +1,109 @@
+package cppgen
+
+import (
+	"fmt"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// CompareKind is how the comparison operators of a struct wrapper compare a
+// member of the wrapped C struct.
+type CompareKind int
+
+const (
+	// the builtin operators, scalars, enums, handles and pointers
+	CompareValue CompareKind = iota
+	// the wrappers of nested structs and unions
+	CompareWrapper
+	// strncmp, char arrays holding strings
+	CompareString
+	// memcmp, other arrays and types the generator knows nothing about
+	CompareBytes
+	// function pointers, they are only ordered as integers
+	CompareAddress
+	// bit-fields, cast back to their type from the int they are promoted to
+	CompareBitField
+)
+
+// cScalarTypes are the C types of the registry compared with the builtin
+// operators
+var cScalarTypes = map[string]bool{
+	"char": true, "float": true, "double": true, "int": true, "size_t": true,
+	"int8_t": true, "int16_t": true, "int32_t": true, "int64_t": true,
+	"uint8_t": true, "uint16_t": true, "uint32_t": true, "uint64_t": true,
+}
+
+// resolveComparisons decides how every struct member is compared. Arrays
+// and members of external types, which may be structs without operators,
+// are compared bytewise.
+func (ctx *Context) resolveComparisons(reg *registry.Registry) {
+	categories := map[string]string{}
+	for _, t := range reg.Types.Type {
+		name := t.Name
+		if name == "" {
+			name = t.InnerName
+		}
+		categories[name] = t.Category
+	}
+	for _, s := range ctx.Structs {
+		for i := range s.Members {
+			m := &s.Members[i]
+			at := &m.AnalyzedType
+			switch {
+			case at.BitWidth > 0:
+				m.Compare = CompareBitField
+			case at.IsArray && at.Type == "char":
+				m.Compare = CompareString
+			case at.IsArray:
+				m.Compare = CompareBytes
+			case at.IsPointer:
+				m.Compare = CompareValue
+			case cScalarTypes[at.Type]:
+				m.Compare = CompareValue
+			default:
+				switch categories[at.Type] {
+				case "basetype", "enum", "bitmask", "handle":
+					m.Compare = CompareValue
+				case "funcpointer":
+					m.Compare = CompareAddress
+				default:
+					m.Compare = CompareBytes
+					if _, ok := m.Converter.(*ReinterpretCastConverter); ok {
+						m.Compare = CompareWrapper
+					}
+				}
+			}
+		}
+	}
+}
+
+// Equal returns the C++ expression comparing the member of two structs for
+// equality, l and r are the expressions the member name is appended to.
+func (m *StructMember) Equal(l, r string) string {
+	return m.compare("==", l+m.Name, r+m.Name)
+}
+
+// ThreeWay returns the C++20 expression comparing the member of two structs
+// with operator<=>, see Equal.
+func (m *StructMember) ThreeWay(l, r string) string {
+	return m.compare("<=>", l+m.Name, r+m.Name)
+}
+
+func (m *StructMember) compare(op, a, b string) string {
+	switch m.Compare {
+	case CompareWrapper:
+		conv := m.Converter.(*ReinterpretCastConverter)
+		return fmt.Sprintf("reinterpret_cast<const %s&>(%s) %s reinterpret_cast<const %s&>(%s)", conv.CppName, a, op, conv.CppName, b)
+	case CompareString:
+		return fmt.Sprintf("std::strncmp(%s, %s, %s) %s 0", a, b, m.ArraySize, op)
+	case CompareBytes:
+		return fmt.Sprintf("std::memcmp(&%s, &%s, sizeof(%s)) %s 0", a, b, a, op)
+	case CompareAddress:
+		if op != "==" {
+			return fmt.Sprintf("reinterpret_cast<uintptr_t>(%s) %s reinterpret_cast<uintptr_t>(%s)", a, op, b)
+		}
+	case CompareBitField:
+		return fmt.Sprintf("static_cast<%s>(%s) %s static_cast<%s>(%s)", m.AnalyzedType.Type, a, op, m.AnalyzedType.Type, b)
+	}
+	return a + " " + op + " " + b
+}
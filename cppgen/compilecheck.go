@@ -0,0 +1,55 @@
+package cppgen
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// compileCheck writes the headers generated by backends to a temporary
+// directory and compiles a translation unit including header with compiler,
+// which takes GCC-style options. $VULKAN_SDK/include is searched for
+// vulkan.h if it's set, options to add are taken from $CXXFLAGS. The
+// compiler's messages are returned as part of the error if it fails.
+func compileCheck(compiler string, backends []backend, header string, cppStd int) error {
+	dir, err := os.MkdirTemp("", "vulkangen")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	var headers []backend
+	for _, b := range backends {
+		name := filepath.Base(b.file)
+		if b.file == "" {
+			name = header
+		}
+		switch filepath.Ext(name) {
+		case ".h", ".hpp":
+			b.file = filepath.Join(dir, name)
+			headers = append(headers, b)
+		}
+	}
+	if err := runBackends(headers, false); err != nil {
+		return err
+	}
+	tu := filepath.Join(dir, "check.cpp")
+	src := fmt.Sprintf("#include \"%s\"\n\nint main() { return 0; }\n", header)
+	if err := os.WriteFile(tu, []byte(src), 0644); err != nil {
+		return err
+	}
+	args := []string{fmt.Sprintf("-std=c++%d", cppStd), "-fsyntax-only", "-I" + dir}
+	if sdk := os.Getenv("VULKAN_SDK"); sdk != "" {
+		args = append(args, "-I"+filepath.Join(sdk, "include"))
+	}
+	args = append(args, strings.Fields(os.Getenv("CXXFLAGS"))...)
+	out, err := exec.Command(compiler, append(args, tu)...).CombinedOutput()
+	if err != nil {
+		// the messages refer to the temporary directory, which is gone by
+		// the time they're read
+		msg := strings.Replace(strings.TrimSpace(string(out)), dir+string(filepath.Separator), "", -1)
+		return fmt.Errorf("-compile-check: %s: %v\n%s", compiler, err, msg)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package cppgen
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// loadConfig sets the flags of fs from the JSON object in file, its keys are
+// flag names without the dash:
+//
+//	{
+//		"spec": ["vk.xml"],
+//		"o": "include/vk.hpp",
+//		"cpp-std": 17,
+//		"extensions": ["VK_KHR_swapchain", "VK_KHR_surface"],
+//		"unique-handles": true
+//	}
+//
+// Lists are joined with commas. Flags already set on the command line are
+// left alone. "spec" lists the spec files, which are returned.
+func loadConfig(fs *flag.FlagSet, file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	dec.UseNumber()
+	var config map[string]interface{}
+	if err := dec.Decode(&config); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	// errors come in the same order every time
+	sort.Strings(keys)
+	var specs []string
+	for _, k := range keys {
+		if k == "spec" {
+			if specs, err = configList(config[k]); err != nil {
+				return nil, fmt.Errorf("%s: spec: %v", file, err)
+			}
+			continue
+		}
+		if k == "config" || fs.Lookup(k) == nil {
+			return nil, fmt.Errorf("%s: unknown option %q", file, k)
+		}
+		if set[k] {
+			continue
+		}
+		v, err := configValue(config[k])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %v", file, k, err)
+		}
+		if err := fs.Set(k, v); err != nil {
+			return nil, fmt.Errorf("%s: %s: %v", file, k, err)
+		}
+	}
+	return specs, nil
+}
+
+// configValue is the flag value of a JSON value.
+func configValue(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool, json.Number:
+		return fmt.Sprint(v), nil
+	case []interface{}:
+		list, err := configList(v)
+		return strings.Join(list, ","), err
+	}
+	return "", fmt.Errorf("want a string, number, boolean or list, got %v", v)
+}
+
+// configList is a JSON list of strings, or a single string.
+func configList(v interface{}) ([]string, error) {
+	if s, ok := v.(string); ok {
+		return []string{s}, nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("want a list of strings, got %v", v)
+	}
+	out := make([]string, len(list))
+	for i, e := range list {
+		if out[i], ok = e.(string); !ok {
+			return nil, fmt.Errorf("want a list of strings, got %v", e)
+		}
+	}
+	return out, nil
+}
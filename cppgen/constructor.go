@@ -0,0 +1,51 @@
+package cppgen
+
+// Constructor is the constructor of a struct wrapper taking its members, so
+// that a struct can be built in a single expression. sType is set by the
+// constructor, flags and pNext, which usually stay empty, are taken last
+// and default to nothing.
+type Constructor struct {
+	Parameters []ConstructorParameter
+
+	// callable with a single argument
+	Explicit bool
+}
+
+type ConstructorParameter struct {
+	StructMember
+
+	// C++ default argument, or "" if there is none
+	Default string
+}
+
+// Constructor returns the member constructor of the struct, or nil if it
+// has none: returned only structs aren't filled by the application, only
+// one member of a union is set and aggregates are initialized directly.
+func (s Struct) Constructor() *Constructor {
+	if s.ReadOnly || s.Union || s.Aggregate {
+		return nil
+	}
+	var params, trailing []ConstructorParameter
+	for _, m := range s.Members {
+		_, isMask := m.Converter.(*BitMaskConverter)
+		switch {
+		case m.Name == "sType" && s.HasSType:
+		case m.Name == "flags" && isMask && !m.AnalyzedType.IsPointer:
+			trailing = append([]ConstructorParameter{{m, "{}"}}, trailing...)
+		case m.Name == "pNext":
+			trailing = append(trailing, ConstructorParameter{m, "nullptr"})
+		default:
+			params = append(params, ConstructorParameter{m, ""})
+		}
+	}
+	params = append(params, trailing...)
+	if len(params) == 0 {
+		return nil
+	}
+	// the default constructor zeroes the struct already
+	params[0].Default = ""
+	return &Constructor{
+		Parameters: params,
+		Explicit:   len(params) == 1 || params[1].Default != "",
+	}
+}
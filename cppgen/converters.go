@@ -1,15 +1,15 @@
-package main
+package cppgen
 
 import (
 	"fmt"
 	"strconv"
-	"strings"
 )
 
+// AnalyzedType is the CType of a member or parameter with what the
+// converters and templates ask of it.
 type AnalyzedType struct {
-	Name  string
-	Type  string
-	Extra string
+	Name string
+	CType
 
 	// result of Analyze
 	IsConst   bool
@@ -17,60 +17,25 @@ type AnalyzedType struct {
 	IsBlank   bool
 	IsArray   bool
 	Arity     int
-	Prefix    string
-	Suffix    string
 }
 
-func NewAnalyzedType(name, typ, extra string) AnalyzedType {
+func NewAnalyzedType(name string, ct CType) AnalyzedType {
 	at := AnalyzedType{
 		Name:  name,
-		Type:  typ,
-		Extra: strings.TrimSpace(extra),
+		CType: ct,
 	}
 	at.Analyze()
 	return at
 }
 
 func (at *AnalyzedType) Analyze() {
-	if at.Extra == "" {
-		at.IsBlank = true
-		return
+	at.IsConst = at.Const
+	at.IsArray = len(at.Dims) > 0
+	at.IsPointer = len(at.Pointers) > 0 || at.IsArray
+	at.IsBlank = !at.IsConst && !at.IsPointer
+	if at.IsArray {
+		at.Arity, _ = strconv.Atoi(at.Dims[len(at.Dims)-1])
 	}
-	if strings.HasPrefix(at.Extra, "const ") {
-		at.IsConst = true
-	}
-	if strings.HasSuffix(at.Extra, "]") {
-		e := at.Extra
-		i := strings.LastIndex(e, "[")
-		if i == -1 {
-			panic("invalid type")
-		}
-		at.Arity, _ = strconv.Atoi(e[i+1 : len(e)-1])
-		at.IsArray = true
-	}
-	if strings.ContainsAny(at.Extra, "*]") {
-		at.IsPointer = true
-	}
-	at.AssemblePrefixSufix()
-}
-
-func (at *AnalyzedType) AssemblePrefixSufix() {
-	e := at.Extra
-	if strings.HasPrefix(e, "const ") {
-		e = strings.TrimPrefix(e, "const ")
-		at.Prefix = "const "
-	}
-	if strings.Contains(e, "[") {
-		// there are better ways to replace [] or [2] with *, but let's keep
-		// this one for now
-		beg := strings.Index(e, "[")
-		end := strings.Index(e, "]")
-		if beg == -1 || end == -1 {
-			panic("invalid type")
-		}
-		e = e[:beg] + "*" + e[end+1:]
-	}
-	at.Suffix = e
 }
 
 type TypeConverter interface {
@@ -164,21 +129,21 @@ func (c *ReinterpretCastConverter) CppToVkArg(at AnalyzedType, src string) strin
 	if at.IsBlank {
 		return (*StaticCastConverter)(c).CppToVkArg(at, src)
 	}
-	return fmt.Sprintf("reinterpret_cast<%s%s%s>(%s)", at.Prefix, c.VkName, at.Suffix, src)
+	return fmt.Sprintf("reinterpret_cast<%s%s%s>(%s)", at.Prefix(), c.VkName, at.Suffix(), src)
 }
 
 func (c *ReinterpretCastConverter) CppToVk(at AnalyzedType, src, dst string) string {
 	if at.IsBlank {
 		return (*StaticCastConverter)(c).CppToVk(at, src, dst)
 	}
-	return fmt.Sprintf("%s = reinterpret_cast<%s%s%s>(%s);", dst, at.Prefix, c.VkName, at.Suffix, src)
+	return fmt.Sprintf("%s = reinterpret_cast<%s%s%s>(%s);", dst, at.Prefix(), c.VkName, at.Suffix(), src)
 }
 
 func (c *ReinterpretCastConverter) VkToCpp(at AnalyzedType, src string) string {
 	if at.IsBlank {
 		return (*StaticCastConverter)(c).VkToCpp(at, src)
 	}
-	return fmt.Sprintf("return reinterpret_cast<%s%s%s>(%s);", at.Prefix, c.CppName, at.Suffix, src)
+	return fmt.Sprintf("return reinterpret_cast<%s%s%s>(%s);", at.Prefix(), c.CppName, at.Suffix(), src)
 }
 
 type HandleConverter CommonConverter
@@ -203,3 +168,23 @@ func (c *HandleConverter) VkToCpp(at AnalyzedType, src string) string {
 	}
 	return fmt.Sprintf("return %s(%s);", c.CppName, src)
 }
+
+// NonDispatchableHandleConverter goes through fromRaw when converting from a
+// C handle, because the wrapper can't be constructed from it directly on
+// platforms where non-dispatchable handles are plain uint64_t.
+type NonDispatchableHandleConverter CommonConverter
+
+func (c *NonDispatchableHandleConverter) CppToVkArg(at AnalyzedType, src string) string {
+	return (*HandleConverter)(c).CppToVkArg(at, src)
+}
+
+func (c *NonDispatchableHandleConverter) CppToVk(at AnalyzedType, src, dst string) string {
+	return (*HandleConverter)(c).CppToVk(at, src, dst)
+}
+
+func (c *NonDispatchableHandleConverter) VkToCpp(at AnalyzedType, src string) string {
+	if at.IsPointer {
+		return (*ReinterpretCastConverter)(c).VkToCpp(at, src)
+	}
+	return fmt.Sprintf("return %s::fromRaw(reinterpret_cast<uint64_t>(%s));", c.CppName, src)
+}
@@ -0,0 +1,139 @@
+package cppgen
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+type Support int
+
+const (
+	Ignored Support = iota
+	Partial
+	Consumed
+)
+
+func (s Support) String() string {
+	switch s {
+	case Consumed:
+		return "consumed"
+	case Partial:
+		return "partial"
+	}
+	return "ignored"
+}
+
+// coverageTable describes how the generator treats registry elements and
+// attributes. Keys are element paths, attributes are appended with '@'. For
+// types/type the category value is part of the key. What the registry
+// package decodes is consumed unless listed here as partial, see init,
+// anything else not listed is ignored.
+var coverageTable = map[string]Support{
+	"registry":                                      Partial,
+	"registry/types/type@category=handle":           Consumed,
+	"registry/types/type@category=enum":             Consumed,
+	"registry/types/type@category=bitmask":          Partial,
+	"registry/types/type@category=struct":           Consumed,
+	"registry/types/type@category=union":            Consumed,
+	"registry/types/type@category=":                 Partial,
+	"registry/types/type@requires":                  Partial,
+	"registry/enums@type":                           Partial,
+	"registry/enums@bitwidth":                       Partial,
+	"registry/commands/command@name":                Partial,
+	"registry/commands/command@successcodes":        Partial,
+	"registry/commands/command/param@len":           Partial,
+	"registry/extensions":                           Partial,
+	"registry/extensions/extension":                 Partial,
+	"registry/extensions/extension@supported":       Partial,
+	"registry/extensions/extension/require":         Partial,
+	"registry/extensions/extension/require/type":    Partial,
+	"registry/extensions/extension/require/command": Partial,
+	"registry/extensions/extension/require/enum":    Partial,
+	"registry/feature":                              Partial,
+	"registry/feature/require":                      Partial,
+	"registry/feature/require/enum":                 Partial,
+}
+
+func init() {
+	for _, p := range registry.Paths() {
+		if _, ok := coverageTable[p]; !ok {
+			coverageTable[p] = Consumed
+		}
+	}
+}
+
+type coverageEntry struct {
+	Key     string
+	Count   int
+	Support Support
+}
+
+// analyzeCoverage walks the whole registry and counts occurrences of every
+// element and attribute kind.
+func analyzeCoverage(r io.Reader) ([]coverageEntry, error) {
+	counts := map[string]int{}
+	var path []string
+	d := xml.NewDecoder(r)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			path = append(path, t.Name.Local)
+			key := strings.Join(path, "/")
+			if key == "registry/types/type" {
+				category := ""
+				for _, a := range t.Attr {
+					if a.Name.Local == "category" {
+						category = a.Value
+					}
+				}
+				key += "@category=" + category
+			}
+			counts[key]++
+			elem := strings.Join(path, "/")
+			for _, a := range t.Attr {
+				if a.Name.Local == "category" && elem == "registry/types/type" {
+					continue
+				}
+				counts[elem+"@"+a.Name.Local]++
+			}
+		case xml.EndElement:
+			path = path[:len(path)-1]
+		}
+	}
+
+	out := make([]coverageEntry, 0, len(counts))
+	for k, n := range counts {
+		out = append(out, coverageEntry{Key: k, Count: n, Support: coverageTable[k]})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+func writeCoverageReport(w io.Writer, entries []coverageEntry) error {
+	var totals [Consumed + 1]int
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "ELEMENT/ATTRIBUTE\tCOUNT\tSUPPORT\n")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", e.Key, e.Count, e.Support)
+		totals[e.Support]++
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d kinds: %d consumed, %d partial, %d ignored\n",
+		len(entries), totals[Consumed], totals[Partial], totals[Ignored])
+	return err
+}
@@ -0,0 +1,52 @@
+package cppgen
+
+import (
+	"os"
+	"testing"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// TestCoverageReport checks the support reported for attributes of
+// testdata/vk.xml, and that what the registry package decodes isn't
+// reported as ignored.
+func TestCoverageReport(t *testing.T) {
+	f, err := os.Open(testSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	entries, err := analyzeCoverage(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]Support{}
+	for _, e := range entries {
+		got[e.Key] = e.Support
+	}
+	for key, want := range map[string]Support{
+		"registry/feature@name":                      Consumed,
+		"registry/feature@number":                    Consumed,
+		"registry/feature@api":                       Consumed,
+		"registry/types/type/member@len":             Consumed,
+		"registry/types/type/member@values":          Consumed,
+		"registry/extensions/extension@supported":    Partial,
+		"registry/extensions/extension@promotedto":   Ignored,
+		"registry/types/type/member@optional":        Ignored,
+		"registry/types/type@category=struct":        Consumed,
+		"registry/feature/require/enum@extnumber":    Consumed,
+		"registry/feature/require@api":               Consumed,
+		"registry/spirvcapabilities/spirvcapability": Consumed,
+	} {
+		if s, ok := got[key]; !ok {
+			t.Errorf("%s isn't in the report", key)
+		} else if s != want {
+			t.Errorf("%s is reported %s, want %s", key, s, want)
+		}
+	}
+	for _, p := range registry.Paths() {
+		if coverageTable[p] == Ignored {
+			t.Errorf("%s is decoded, but reported as ignored", p)
+		}
+	}
+}
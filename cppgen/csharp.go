@@ -0,0 +1,356 @@
+package cppgen
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CSharpFile is the C# file of -lang csharp: sequential structs and
+// explicit unions of blittable fields, enums, [Flags] enums for the
+// bitmasks, the commands as [DllImport] methods of the static class Vk and
+// as unmanaged function pointers of a loader table. It needs C# 9 and
+// unsafe code. Names follow the C++ header, with C# casing: types without
+// the Vk prefix, PascalCase members, values and methods.
+type CSharpFile struct {
+	Banner    []string
+	Namespace string
+	Constants []RustConstant
+	Handles   []CSharpHandle
+	Enums     []CSharpEnum
+	Structs   []CSharpStruct
+	Commands  []CSharpCommand
+
+	// C types of the registry which aren't generated, replaced with void
+	// behind pointers and leaving out what uses them by value
+	Missing []string
+}
+
+// CSharpHandle is a handle, wrapping an IntPtr if it's dispatchable and a
+// ulong otherwise.
+type CSharpHandle struct {
+	Name string
+	Type string
+}
+
+// CSharpEnum is an enum or, if Flags, a bitmask.
+type CSharpEnum struct {
+	Name   string
+	Type   string
+	Flags  bool
+	Values []RustConstant
+}
+
+type CSharpStruct struct {
+	Name   string
+	Union  bool
+	Fields []CSharpField
+
+	// the StructureType value New sets sType to, "" for structs without
+	// sType
+	SType string
+}
+
+// CSharpField is a struct member or command parameter. Fixed is the size
+// of a fixed size buffer member.
+type CSharpField struct {
+	Name  string
+	Type  string
+	Fixed int
+}
+
+type CSharpCommand struct {
+	VkName string
+	Name   string
+	Params []CSharpField
+	Ret    string
+}
+
+// FunctionPointer is the type of a function pointer to the command.
+func (c CSharpCommand) FunctionPointer() string {
+	var types []string
+	for _, p := range c.Params {
+		types = append(types, p.Type)
+	}
+	return "delegate* unmanaged<" + strings.Join(append(types, c.Ret), ", ") + ">"
+}
+
+// csharpKeywords are the keywords of C# which are member or parameter names
+// of the registry, or might be, they are written as @name.
+var csharpKeywords = map[string]bool{
+	"object": true, "event": true, "params": true, "string": true,
+	"base": true, "fixed": true, "ref": true, "out": true, "in": true,
+	"lock": true, "operator": true, "checked": true, "default": true,
+	"internal": true, "is": true, "as": true, "new": true, "this": true,
+	"type": true, "delegate": true, "implicit": true, "explicit": true,
+}
+
+// csharpCTypes are the C types vk.xml uses and their C# equivalents.
+var csharpCTypes = map[string]string{
+	"void":     "void",
+	"char":     "byte",
+	"int":      "int",
+	"float":    "float",
+	"double":   "double",
+	"size_t":   "nuint",
+	"int8_t":   "sbyte",
+	"uint8_t":  "byte",
+	"int16_t":  "short",
+	"uint16_t": "ushort",
+	"int32_t":  "int",
+	"uint32_t": "uint",
+	"int64_t":  "long",
+	"uint64_t": "ulong",
+
+	// the basetypes of vk.xml
+	"VkBool32":        "uint",
+	"VkFlags":         "uint",
+	"VkFlags64":       "ulong",
+	"VkDeviceSize":    "ulong",
+	"VkDeviceAddress": "ulong",
+	"VkSampleMask":    "uint",
+
+	"PFN_vkVoidFunction": "IntPtr",
+}
+
+// csharpFixedTypes are the types fixed size buffers can have, arrays of
+// other types are written as a member for each element.
+var csharpFixedTypes = map[string]bool{
+	"byte": true, "sbyte": true, "short": true, "ushort": true, "int": true,
+	"uint": true, "long": true, "ulong": true, "float": true, "double": true,
+}
+
+// csharpConstantTypes are the C# types of the types of rustConstant.
+var csharpConstantTypes = map[string]string{
+	"usize":  "uint",
+	"u32":    "uint",
+	"u64":    "ulong",
+	"f32":    "float",
+	"Bool32": "uint",
+}
+
+// csharpIdent makes a member or parameter name of a C name, PascalCase for
+// members and as it is for parameters.
+func csharpIdent(name string, member bool) string {
+	if member {
+		return strings.ToUpper(name[:1]) + name[1:]
+	}
+	if csharpKeywords[name] {
+		return "@" + name
+	}
+	return name
+}
+
+// csharpTypes maps the C types to the C# types generated for them.
+type csharpTypes struct {
+	names   map[string]string
+	missing map[string]bool
+
+	// the values of the API constants sizing arrays
+	sizes map[string]int
+}
+
+// csharpType returns the C# type of a member or parameter of C type ct and
+// the number of elements if it's an array, 0 otherwise. Arrays of
+// parameters are passed as pointers. ok is false if its type is used by
+// value but isn't generated, or it's a bit-field, which C# has no
+// equivalent of.
+func (cs *csharpTypes) csharpType(ct CType, param bool) (t string, n int, ok bool) {
+	t, ok = cs.names[ct.Type]
+	switch {
+	case !ok && strings.HasPrefix(ct.Type, "PFN_"):
+		// all function pointers are passed the same way, their signatures
+		// aren't generated
+		t, ok = "IntPtr", true
+	case !ok:
+		cs.missing[ct.Type] = true
+		t = "void"
+	}
+	if ct.BitWidth > 0 {
+		return "", 0, false
+	}
+	for _, size := range ct.Dims {
+		d, err := strconv.Atoi(size)
+		if err != nil {
+			d = cs.sizes[size]
+		}
+		if n == 0 {
+			n = 1
+		}
+		n *= d
+	}
+	pointers := len(ct.Pointers)
+	if n > 0 && param {
+		// decayed to a pointer in C
+		pointers, n = 1, 0
+	}
+	return t + strings.Repeat("*", pointers), n, ok || pointers > 0
+}
+
+func newCSharpFile(params *HeaderParams, ctx *Context) *CSharpFile {
+	f := &CSharpFile{Banner: params.Banner, Namespace: "Vulkan"}
+	ct := &csharpTypes{names: map[string]string{}, missing: map[string]bool{}, sizes: map[string]int{}}
+	for k, v := range csharpCTypes {
+		ct.names[k] = v
+	}
+	for _, c := range ctx.Constants {
+		rc, ok := rustConstant(c.Name, c.Value)
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(rc.Value); err == nil && rc.Type == "usize" {
+			ct.sizes[c.Name] = n
+		}
+		rc.Name = toCamelCase(rc.Name)
+		rc.Type = csharpConstantTypes[rc.Type]
+		switch {
+		case strings.HasPrefix(rc.Value, "!"):
+			rc.Value = fmt.Sprintf("~(%s)%s", rc.Type, rc.Value[1:])
+		case rc.Type == "float":
+			rc.Value += "f"
+		}
+		f.Constants = append(f.Constants, rc)
+	}
+	for _, h := range ctx.Handles {
+		name := strings.TrimPrefix(h.VkName, "Vk")
+		ct.names[h.VkName] = name
+		typ := "ulong"
+		if h.TypeSafe {
+			typ = "IntPtr"
+		}
+		f.Handles = append(f.Handles, CSharpHandle{Name: name, Type: typ})
+	}
+	enumValues := func(e *Enum, bits bool) []RustConstant {
+		var out []RustConstant
+		for i, v := range rustEnumValues(e, bits) {
+			if v.Name != "" {
+				out = append(out, RustConstant{Name: toCamelCase(v.Name), Value: fmt.Sprint(e.Values[i].Number)})
+			}
+		}
+		return out
+	}
+	bitEnums := map[string]bool{}
+	for _, bm := range ctx.BitMasks {
+		name := strings.TrimPrefix(bm.VkName, "Vk")
+		ct.names[bm.VkName] = name
+		ce := CSharpEnum{Name: name, Type: "uint", Flags: true}
+		if bm.BitWidth == 64 {
+			ce.Type = "ulong"
+		}
+		none := false
+		if bm.Enum != nil && bm.Enum.VkName != "" {
+			// the bits are values of the bitmask too, there's no alias
+			// naming them apart in C#
+			bitEnums[bm.Enum.VkName] = true
+			ct.names[bm.Enum.VkName] = name
+			for _, v := range enumValues(bm.Enum, true) {
+				n, _ := strconv.ParseInt(v.Value, 10, 64)
+				none = none || n == 0 || v.Name == "None"
+				ce.Values = append(ce.Values, RustConstant{Name: v.Name, Value: fmt.Sprintf("0x%x", uint64(n))})
+			}
+		}
+		if !none {
+			ce.Values = append([]RustConstant{{Name: "None", Value: "0"}}, ce.Values...)
+		}
+		f.Enums = append(f.Enums, ce)
+	}
+	for i := range ctx.Enums {
+		e := &ctx.Enums[i]
+		if bitEnums[e.VkName] {
+			continue
+		}
+		name := strings.TrimPrefix(e.VkName, "Vk")
+		ct.names[e.VkName] = name
+		f.Enums = append(f.Enums, CSharpEnum{Name: name, Type: "int", Values: enumValues(e, false)})
+	}
+	// C++ name -> C# name of the StructureType values
+	sTypes := map[string]string{}
+	for i := range ctx.Enums {
+		if e := &ctx.Enums[i]; e.VkName == "VkStructureType" {
+			for j, v := range rustEnumValues(e, false) {
+				sTypes[e.Values[j].Name] = toCamelCase(v.Name)
+			}
+		}
+	}
+	// those using a struct left out by value are left out too
+	for _, s := range ctx.Structs {
+		cs := CSharpStruct{Name: strings.TrimPrefix(s.VkName, "Vk"), Union: s.Union}
+		ok := true
+		for _, mem := range s.Members {
+			t, n, known := ct.csharpType(mem.AnalyzedType.CType, false)
+			if !known {
+				ok = false
+				break
+			}
+			name := csharpIdent(mem.Name, true)
+			if name == cs.Name {
+				// members can't be named like their struct
+				name += "_"
+			}
+			switch {
+			case n == 0:
+				cs.Fields = append(cs.Fields, CSharpField{Name: name, Type: t})
+			case csharpFixedTypes[t]:
+				cs.Fields = append(cs.Fields, CSharpField{Name: name, Type: t, Fixed: n})
+			default:
+				for i := 0; i < n; i++ {
+					cs.Fields = append(cs.Fields, CSharpField{Name: fmt.Sprintf("%s_%d", name, i), Type: t})
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		if s.HasSType {
+			cs.SType = sTypes[s.SType]
+		}
+		ct.names[s.VkName] = cs.Name
+		f.Structs = append(f.Structs, cs)
+	}
+	for _, a := range ctx.TypeAliases {
+		// C# has no aliases of types, they name the same type
+		if t := ct.names["Vk"+a.Target]; t != "" {
+			ct.names["Vk"+a.Name] = t
+		}
+	}
+	for _, c := range ctx.Commands {
+		cc := CSharpCommand{VkName: c.VkName, Name: strings.TrimPrefix(c.VkName, "vk"), Ret: "void"}
+		ok := true
+		for _, p := range c.Parameters {
+			t, _, known := ct.csharpType(p.AnalyzedType.CType, true)
+			if !known {
+				ok = false
+				break
+			}
+			cc.Params = append(cc.Params, CSharpField{Name: csharpIdent(p.Name, false), Type: t})
+		}
+		if c.RetVkType != "void" {
+			t, _, known := ct.csharpType(CType{Type: c.RetVkType}, false)
+			ok = ok && known
+			cc.Ret = t
+		}
+		if ok {
+			f.Commands = append(f.Commands, cc)
+		}
+	}
+	for t := range ct.missing {
+		f.Missing = append(f.Missing, t)
+	}
+	sort.Strings(f.Missing)
+	return f
+}
+
+// csharpBackend is -lang csharp.
+type csharpBackend struct{}
+
+func init() {
+	RegisterBackend(csharpBackend{})
+}
+
+func (csharpBackend) Name() string { return "csharp" }
+
+func (csharpBackend) Generate(w io.Writer, ctx *Context, opts Options) error {
+	return ctx.execute(w, "csharp", newCSharpFile(ctx.Params, ctx))
+}
@@ -0,0 +1,153 @@
+package cppgen
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CType is the C type of a struct member, command parameter or return value
+// taken apart. vk.xml declares them as <type>Type</type> with the rest of
+// the declarator, "const ", "* const*", "[4]", ":24", as text around the
+// <name>.
+type CType struct {
+	Type  string
+	Const bool
+
+	// one per level of indirection, innermost first, true if what the
+	// pointer points to is const: const char* const* is [true true]
+	Pointers []bool
+
+	// the array sizes outermost first, the name of the constant where the
+	// size is an <enum>
+	Dims []string
+
+	// the width of a bit-field, 0 if it isn't one
+	BitWidth int
+
+	// declared with an elaborated type specifier, struct Foo *
+	Elaborated bool
+}
+
+// parseCType takes apart the declaration of name, of type typ with extra
+// the text around the name. Some names of old registries end with their
+// array size, "[2]", it's moved to the type and name is returned without
+// it. Empty array sizes are left for the caller to fill in with the <enum>
+// of the declaration.
+func parseCType(typ, name, extra string) (CType, string) {
+	if i := strings.IndexByte(name, '['); i > 0 {
+		extra += name[i:]
+		name = name[:i]
+	}
+	ct := CType{Type: typ}
+	constNext := false
+	s := extra
+	for s != "" {
+		switch c := s[0]; {
+		case c == '*':
+			ct.Pointers = append(ct.Pointers, constNext)
+			constNext = false
+			s = s[1:]
+		case c == '[':
+			end := strings.IndexByte(s, ']')
+			if end == -1 {
+				// unbalanced, reported by validation
+				end = len(s) - 1
+			}
+			ct.Dims = append(ct.Dims, strings.TrimSpace(s[1:end]))
+			s = s[end+1:]
+		case c == ':':
+			s = strings.TrimSpace(s[1:])
+			n := 0
+			for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+				n++
+			}
+			ct.BitWidth, _ = strconv.Atoi(s[:n])
+			s = s[n:]
+		case isIdentByte(c):
+			n := 1
+			for n < len(s) && isIdentByte(s[n]) {
+				n++
+			}
+			switch s[:n] {
+			case "const":
+				constNext = true
+				if len(ct.Pointers) == 0 {
+					ct.Const = true
+				}
+			case "struct":
+				ct.Elaborated = true
+			}
+			s = s[n:]
+		default:
+			s = s[1:]
+		}
+	}
+	return ct, name
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// Prefix is what comes before the type in its spelling.
+func (ct CType) Prefix() string {
+	if ct.Const {
+		return "const "
+	}
+	return ""
+}
+
+// Suffix is what comes after the type in its spelling: the pointers, and
+// arrays decayed to one more.
+func (ct CType) Suffix() string {
+	var b strings.Builder
+	ct.writePointers(&b)
+	if len(ct.Dims) > 0 {
+		b.WriteByte('*')
+	}
+	return b.String()
+}
+
+func (ct CType) writePointers(b *strings.Builder) {
+	for i, c := range ct.Pointers {
+		if i > 0 && c {
+			b.WriteString(" const")
+		}
+		b.WriteByte('*')
+	}
+}
+
+// Spell returns the type with base as the name of Type, "const char*" for
+// base char of const char[4]. Arrays are spelled as the pointers they decay
+// to, bit-fields as their type.
+func (ct CType) Spell(base string) string {
+	return ct.Prefix() + base + ct.Suffix()
+}
+
+// ArrayDims returns the array sizes as declared, "[3][4]".
+func (ct CType) ArrayDims() string {
+	if len(ct.Dims) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(ct.Dims, "][") + "]"
+}
+
+// Declare returns the C declaration of name with base as the name of Type,
+// arrays and bit-fields included. An empty name gives the type as written
+// in a cast, float[3][4], without the width of bit-fields.
+func (ct CType) Declare(base, name string) string {
+	var b strings.Builder
+	b.WriteString(ct.Prefix())
+	b.WriteString(base)
+	ct.writePointers(&b)
+	if name != "" {
+		b.WriteByte(' ')
+		b.WriteString(name)
+	}
+	b.WriteString(ct.ArrayDims())
+	if ct.BitWidth > 0 && name != "" {
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(ct.BitWidth))
+	}
+	return b.String()
+}
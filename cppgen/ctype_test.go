@@ -0,0 +1,93 @@
+package cppgen
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCType(t *testing.T) {
+	for _, c := range []struct {
+		typ, name, extra string
+
+		want     CType
+		wantName string
+		spell    string
+		declare  string
+	}{
+		{
+			"uint32_t", "count", " ",
+			CType{Type: "uint32_t"}, "count",
+			"uint32_t", "uint32_t count",
+		},
+		{
+			"void", "pNext", "const * ",
+			CType{Type: "void", Const: true, Pointers: []bool{true}}, "pNext",
+			"const void*", "const void* pNext",
+		},
+		{
+			"char", "ppEnabledLayerNames", "const * const* ",
+			CType{Type: "char", Const: true, Pointers: []bool{true, true}}, "ppEnabledLayerNames",
+			"const char* const*", "const char* const* ppEnabledLayerNames",
+		},
+		{
+			"VkBuffer", "pBuffers", "* ",
+			CType{Type: "VkBuffer", Pointers: []bool{false}}, "pBuffers",
+			"VkBuffer*", "VkBuffer* pBuffers",
+		},
+		{
+			"float", "matrix", " [3][4]",
+			CType{Type: "float", Dims: []string{"3", "4"}}, "matrix",
+			"float*", "float matrix[3][4]",
+		},
+		{
+			"char", "deviceName", " []",
+			CType{Type: "char", Dims: []string{""}}, "deviceName",
+			"char*", "char deviceName[]",
+		},
+		{
+			// old registries append the size to the name
+			"char", "deviceName[256]", "",
+			CType{Type: "char", Dims: []string{"256"}}, "deviceName",
+			"char*", "char deviceName[256]",
+		},
+		{
+			"uint32_t", "instanceCustomIndex", ":24",
+			CType{Type: "uint32_t", BitWidth: 24}, "instanceCustomIndex",
+			"uint32_t", "uint32_t instanceCustomIndex:24",
+		},
+		{
+			"VkBaseOutStructure", "pNext", "struct * ",
+			CType{Type: "VkBaseOutStructure", Pointers: []bool{false}, Elaborated: true}, "pNext",
+			"VkBaseOutStructure*", "VkBaseOutStructure* pNext",
+		},
+	} {
+		ct, name := parseCType(c.typ, c.name, c.extra)
+		if !reflect.DeepEqual(ct, c.want) || name != c.wantName {
+			t.Errorf("parseCType(%q, %q, %q) = %+v, %q, want %+v, %q", c.typ, c.name, c.extra, ct, name, c.want, c.wantName)
+			continue
+		}
+		if got := ct.Spell(c.typ); got != c.spell {
+			t.Errorf("Spell of %s %s = %q, want %q", c.typ, c.name, got, c.spell)
+		}
+		if got := ct.Declare(c.typ, name); got != c.declare {
+			t.Errorf("Declare of %s %s = %q, want %q", c.typ, c.name, got, c.declare)
+		}
+	}
+}
+
+// TestCTypeDeclareCast checks the types as written in casts, without a
+// name and the width of bit-fields.
+func TestCTypeDeclareCast(t *testing.T) {
+	for _, c := range []struct {
+		typ, name, extra, want string
+	}{
+		{"float", "matrix", "[3][4]", "float[3][4]"},
+		{"uint32_t", "mask", ":8", "uint32_t"},
+		{"char", "ppNames", "const * const*", "const char* const*"},
+	} {
+		ct, _ := parseCType(c.typ, c.name, c.extra)
+		if got := ct.Declare(c.typ, ""); got != c.want {
+			t.Errorf("Declare of %s%s without a name = %q, want %q", c.typ, c.extra, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package cppgen
+
+// DebugName is setDebugName, which names a handle in validation messages
+// and debuggers through vkSetDebugUtilsObjectNameEXT, the object type
+// coming from ObjectTypeTraits. Command is the wrapper of the command it
+// calls, Dispatch adds an overload calling through DispatchLoaderDynamic.
+type DebugName struct {
+	Protect  Protect
+	Name     string
+	Command  string
+	Noexcept string
+	Dispatch bool
+}
+
+// newDebugName returns setDebugName, or nil unless the command, its struct
+// and the object types of handles are generated.
+func (ctx *Context) newDebugName() *DebugName {
+	if !ctx.HasObjectTypes() {
+		return nil
+	}
+	hasInfo := false
+	for _, s := range ctx.Structs {
+		if s.VkName == "VkDebugUtilsObjectNameInfoEXT" {
+			hasInfo = true
+		}
+	}
+	if !hasInfo {
+		return nil
+	}
+	for _, c := range ctx.Commands {
+		if c.VkName == "vkSetDebugUtilsObjectNameEXT" {
+			return &DebugName{
+				Protect:  c.Protect,
+				Name:     ctx.naming.Function("setDebugName"),
+				Command:  c.Name,
+				Noexcept: c.Noexcept(),
+				Dispatch: ctx.Dispatch != nil,
+			}
+		}
+	}
+	return nil
+}
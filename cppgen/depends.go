@@ -0,0 +1,302 @@
+package cppgen
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// depExpr is a parsed dependency expression of an extension, e.g.
+// "VK_KHR_get_physical_device_properties2+VK_VERSION_1_1,VK_VERSION_1_2".
+// '+' is AND, ',' is OR, AND binds tighter and parentheses group.
+type depExpr struct {
+	op   byte // '+', ',' or 0 for a name
+	name string
+	args []*depExpr
+}
+
+type depParser struct {
+	s   string
+	pos int
+}
+
+func parseDepends(s string) (*depExpr, error) {
+	p := depParser{s: s}
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(s) {
+		return nil, fmt.Errorf("unexpected %q at offset %d", s[p.pos], p.pos)
+	}
+	return e, nil
+}
+
+func (p *depParser) or() (*depExpr, error) {
+	return p.list(',', p.and)
+}
+
+func (p *depParser) and() (*depExpr, error) {
+	return p.list('+', p.term)
+}
+
+// list parses operands separated by op, a single operand is returned as is
+func (p *depParser) list(op byte, operand func() (*depExpr, error)) (*depExpr, error) {
+	e, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos == len(p.s) || p.s[p.pos] != op {
+		return e, nil
+	}
+	e = &depExpr{op: op, args: []*depExpr{e}}
+	for p.pos < len(p.s) && p.s[p.pos] == op {
+		p.pos++
+		arg, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		e.args = append(e.args, arg)
+	}
+	return e, nil
+}
+
+func (p *depParser) term() (*depExpr, error) {
+	if p.pos < len(p.s) && p.s[p.pos] == '(' {
+		p.pos++
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos == len(p.s) || p.s[p.pos] != ')' {
+			return nil, fmt.Errorf("missing ) at offset %d", p.pos)
+		}
+		p.pos++
+		return e, nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) && isDepNameChar(p.s[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		if p.pos == len(p.s) {
+			return nil, fmt.Errorf("missing name at the end")
+		}
+		return nil, fmt.Errorf("unexpected %q at offset %d", p.s[p.pos], p.pos)
+	}
+	return &depExpr{name: p.s[start:p.pos]}, nil
+}
+
+func isDepNameChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func (e *depExpr) eval(have func(name string) bool) bool {
+	switch e.op {
+	case '+':
+		for _, a := range e.args {
+			if !a.eval(have) {
+				return false
+			}
+		}
+		return true
+	case ',':
+		for _, a := range e.args {
+			if a.eval(have) {
+				return true
+			}
+		}
+		return false
+	}
+	return have(e.name)
+}
+
+// names appends the names the expression mentions to out, in order
+func (e *depExpr) names(out []string) []string {
+	if e.op == 0 {
+		return append(out, e.name)
+	}
+	for _, a := range e.args {
+		out = a.names(out)
+	}
+	return out
+}
+
+// satisfy returns the names to add to the ones we have to make the
+// expression true, using only available names. The first satisfiable
+// alternative of an OR is picked. ok is false if it can't be satisfied.
+func (e *depExpr) satisfy(have, available func(name string) bool) (add []string, ok bool) {
+	if e.eval(have) {
+		return nil, true
+	}
+	switch e.op {
+	case '+':
+		for _, a := range e.args {
+			more, ok := a.satisfy(have, available)
+			if !ok {
+				return nil, false
+			}
+			add = append(add, more...)
+		}
+		return add, true
+	case ',':
+		for _, a := range e.args {
+			if add, ok := a.satisfy(have, available); ok {
+				return add, true
+			}
+		}
+		return nil, false
+	}
+	if available(e.name) {
+		return []string{e.name}, true
+	}
+	return nil, false
+}
+
+// selectExtensions completes the -extensions whitelist with the extensions
+// the selected ones depend on, transitively, or without a whitelist adds the
+// extensions depending on those of -exclude-extensions to them. Core
+// versions are always available. Fails with a usageError if a selected or
+// excluded extension doesn't exist, a selected one is excluded or its
+// dependencies can't be satisfied.
+func selectExtensions(reg *registry.Registry, opts *Options) error {
+	extensions := map[string]*registry.Extension{}
+	for i := range reg.Extensions.Extension {
+		e := &reg.Extensions.Extension[i]
+		extensions[e.Name] = e
+	}
+	features := map[string]bool{}
+	for _, f := range reg.Features {
+		features[f.Name] = true
+	}
+	for _, name := range opts.ExcludeExtensions {
+		if _, ok := extensions[name]; !ok {
+			return usageError{fmt.Errorf("unknown excluded extension %s", name)}
+		}
+	}
+	if len(opts.Extensions) == 0 {
+		if len(opts.ExcludeExtensions) > 0 {
+			return excludeDependents(reg, opts, features)
+		}
+		return nil
+	}
+
+	selected := map[string]bool{}
+	have := func(name string) bool { return features[name] || selected[name] }
+	available := func(name string) bool {
+		e, ok := extensions[name]
+		return ok && opts.extensionUnavailable(e) == ""
+	}
+	queue := append([]string(nil), opts.Extensions...)
+	for _, name := range queue {
+		selected[name] = true
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		e, ok := extensions[name]
+		if !ok {
+			return usageError{fmt.Errorf("unknown extension %s", name)}
+		}
+		if why := opts.extensionUnavailable(e); why != "" {
+			return usageError{fmt.Errorf("selected %s", why)}
+		}
+		if e.Depends == "" {
+			continue
+		}
+		expr, err := parseDepends(e.Depends)
+		if err != nil {
+			return fmt.Errorf("extension %s: depends: %v", name, err)
+		}
+		add, ok := expr.satisfy(have, available)
+		if !ok {
+			return usageError{fmt.Errorf("extension %s depends on %s, which can't be satisfied", name, e.Depends)}
+		}
+		for _, dep := range add {
+			if selected[dep] {
+				continue
+			}
+			log.Printf("pulled in extension %s: required by %s", dep, name)
+			selected[dep] = true
+			queue = append(queue, dep)
+			opts.Extensions = append(opts.Extensions, dep)
+		}
+	}
+	return nil
+}
+
+// excludeDependents adds the extensions whose dependencies can't be
+// satisfied without the excluded ones to them, transitively.
+func excludeDependents(reg *registry.Registry, opts *Options, features map[string]bool) error {
+	generated := map[string]bool{}
+	for i := range reg.Extensions.Extension {
+		e := &reg.Extensions.Extension[i]
+		if opts.extensionUnavailable(e) == "" {
+			generated[e.Name] = true
+		}
+	}
+	have := func(name string) bool { return features[name] || generated[name] }
+	for changed := true; changed; {
+		changed = false
+		for i := range reg.Extensions.Extension {
+			e := &reg.Extensions.Extension[i]
+			if !generated[e.Name] || e.Depends == "" {
+				continue
+			}
+			expr, err := parseDepends(e.Depends)
+			if err != nil {
+				return fmt.Errorf("extension %s: depends: %v", e.Name, err)
+			}
+			if expr.eval(have) {
+				continue
+			}
+			log.Printf("excluded extension %s: depends on %s", e.Name, e.Depends)
+			generated[e.Name] = false
+			opts.ExcludeExtensions = append(opts.ExcludeExtensions, e.Name)
+			changed = true
+		}
+	}
+	return nil
+}
+
+// ExtensionInfo is the dependency metadata of a generated extension.
+// Dependencies are the names the Depends expression mentions, Offset is the
+// index of the first one in the flattened table of all extensions.
+type ExtensionInfo struct {
+	Name         string
+	Number       int
+	Depends      string
+	Dependencies []string
+	Offset       int
+}
+
+func newExtensionInfos(reg *registry.Registry, opts *Options) []ExtensionInfo {
+	var out []ExtensionInfo
+	offset := 0
+	for i := range reg.Extensions.Extension {
+		e := &reg.Extensions.Extension[i]
+		if opts.extensionExcluded(e) != "" {
+			continue
+		}
+		info := ExtensionInfo{Name: e.Name, Number: e.Number, Depends: e.Depends, Offset: offset}
+		if expr, err := parseDepends(e.Depends); e.Depends != "" && err == nil {
+			info.Dependencies = dedup(expr.names(nil))
+		}
+		offset += len(info.Dependencies)
+		out = append(out, info)
+	}
+	return out
+}
+
+func dedup(names []string) []string {
+	seen := map[string]bool{}
+	out := names[:0]
+	for _, n := range names {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	return out
+}
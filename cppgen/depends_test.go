@@ -0,0 +1,37 @@
+package cppgen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// TestSelectExtensionsUsageErrors checks that -extensions and
+// -exclude-extensions which can't be generated are errors of the options.
+func TestSelectExtensionsUsageErrors(t *testing.T) {
+	for _, c := range []struct {
+		extensions, exclude []string
+		err                 string
+	}{
+		{[]string{"VK_BOGUS"}, nil, "unknown extension VK_BOGUS"},
+		{nil, []string{"VK_BOGUS"}, "unknown excluded extension VK_BOGUS"},
+		{[]string{"VK_KHR_surface"}, []string{"VK_KHR_surface"}, "selected excluded extension VK_KHR_surface"},
+		{[]string{"VK_NV_external_sci_sync"}, nil, "selected extension VK_NV_external_sci_sync for vulkansc"},
+		{[]string{"VK_KHR_xlib_surface"}, []string{"VK_KHR_surface"}, "extension VK_KHR_xlib_surface depends on VK_KHR_surface, which can't be satisfied"},
+	} {
+		reg, err := registry.ReadFile(testSpec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		discardLog(t)
+		opts := NewOptions()
+		opts.Extensions, opts.ExcludeExtensions = c.extensions, c.exclude
+		err = Generate(&bytes.Buffer{}, reg, *opts)
+		if err == nil || err.Error() != c.err {
+			t.Errorf("-extensions %v -exclude-extensions %v: %v, want %s", c.extensions, c.exclude, err, c.err)
+		} else if exitCode(err, exitSpec) != exitUsage {
+			t.Errorf("-extensions %v -exclude-extensions %v: exit status %d, want %d", c.extensions, c.exclude, exitCode(err, exitSpec), exitUsage)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package cppgen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// declaration is a top-level declaration of a generated header: a class, a
+// function, a specialization, etc. with the comments and guards following
+// it up to the next one. Key is its first line, numbered if the same line
+// starts several declarations (overloads split over lines).
+type declaration struct {
+	Key  string
+	Text string
+}
+
+// splitDeclarations splits a generated header into its top-level
+// declarations. A declaration starts with an unindented line which isn't a
+// comment, a preprocessor directive, a closing brace or a label, template
+// <...> lines belong to the declaration they precede.
+func splitDeclarations(text string) []declaration {
+	var out []declaration
+	var cur *declaration
+	seen := map[string]int{}
+	templ := false
+	for _, line := range strings.SplitAfter(text, "\n") {
+		t := strings.TrimRight(line, "\n")
+		if startsDeclaration(t) && !templ {
+			out = append(out, declaration{})
+			cur = &out[len(out)-1]
+		}
+		if cur == nil {
+			// the banner and the includes before the first declaration
+			out = append(out, declaration{Key: "(preamble)"})
+			cur = &out[len(out)-1]
+		}
+		if cur.Key == "" && startsDeclaration(t) && !isTemplateHeader(t) {
+			key := strings.TrimRight(t, " {;")
+			seen[key]++
+			if n := seen[key]; n > 1 {
+				key = fmt.Sprintf("%s (#%d)", key, n)
+			}
+			cur.Key = key
+		}
+		cur.Text += line
+		templ = isTemplateHeader(t)
+	}
+	return out
+}
+
+// isTemplateHeader reports whether the line is only the template <...> of
+// the declaration on the next line.
+func isTemplateHeader(line string) bool {
+	return strings.HasPrefix(line, "template <") && strings.HasSuffix(line, ">")
+}
+
+func startsDeclaration(line string) bool {
+	if line == "" || strings.HasSuffix(line, ":") {
+		return false
+	}
+	// guarded cases of a switch are unindented
+	if strings.HasPrefix(line, "case ") || strings.HasPrefix(line, "default:") {
+		return false
+	}
+	return !strings.ContainsRune(" \t}{)#/", rune(line[0]))
+}
+
+// diffHeader compares the header emitted by b with the file, the summary
+// of added, removed and changed declarations is written to w. Reports
+// whether they're the same.
+func diffHeader(w io.Writer, file string, b *backend) (bool, error) {
+	old, err := os.ReadFile(file)
+	if err != nil {
+		return false, err
+	}
+	var buf bytes.Buffer
+	if err := b.emit(&buf); err != nil {
+		return false, fmt.Errorf("%s: %v", b.name, err)
+	}
+	if bytes.Equal(old, buf.Bytes()) {
+		return true, nil
+	}
+	oldDecls := map[string]string{}
+	for _, d := range splitDeclarations(string(old)) {
+		oldDecls[d.Key] = d.Text
+	}
+	var added, changed, removed []string
+	newDecls := map[string]bool{}
+	for _, d := range splitDeclarations(buf.String()) {
+		newDecls[d.Key] = true
+		text, ok := oldDecls[d.Key]
+		switch {
+		case !ok:
+			added = append(added, d.Key)
+		case text != d.Text:
+			changed = append(changed, d.Key)
+		}
+	}
+	for _, d := range splitDeclarations(string(old)) {
+		if !newDecls[d.Key] {
+			removed = append(removed, d.Key)
+		}
+	}
+	fmt.Fprintf(w, "%s is out of date: %d added, %d removed, %d changed declarations\n", file, len(added), len(removed), len(changed))
+	for _, l := range []struct {
+		mark  string
+		decls []string
+	}{{"+", added}, {"-", removed}, {"~", changed}} {
+		for _, d := range l.decls {
+			fmt.Fprintf(w, "%s %s\n", l.mark, d)
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,79 @@
+package cppgen
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// DispatchCommand is a command DispatchLoaderDynamic loads, see
+// Options.DynamicDispatch. Aliases are the other names of the command, which
+// are looked up if the implementation doesn't know the command by its own,
+// e.g. the extension name of a command promoted to core.
+type DispatchCommand struct {
+	Protect Protect
+	VkName  string
+	Aliases []string
+
+	// the command is dispatched on a device or one of its children, it's
+	// loaded with vkGetDeviceProcAddr once there is a device
+	Device bool
+
+	// the command isn't dispatched on any handle (vkCreateInstance), it's
+	// loaded with vkGetInstanceProcAddr before there is an instance
+	Global bool
+}
+
+// newDispatchCommands returns the commands of the dynamic dispatch loader
+// other than vkGetInstanceProcAddr and vkGetDeviceProcAddr, which the
+// loader starts from.
+func (ctx *Context) newDispatchCommands(reg *registry.Registry) []DispatchCommand {
+	aliases := map[string][]string{}
+	commandAliases := map[string]string{}
+	for _, c := range reg.Commands.Command {
+		if c.Alias != "" {
+			commandAliases[c.Name] = c.Alias
+		}
+	}
+	for name := range commandAliases {
+		target := resolveAlias(commandAliases, name)
+		aliases[target] = append(aliases[target], name)
+	}
+	device := ctx.Handle("VkDevice")
+	var out []DispatchCommand
+	for _, c := range ctx.Commands {
+		if c.VkName == "vkGetInstanceProcAddr" || c.VkName == "vkGetDeviceProcAddr" {
+			continue
+		}
+		dc := DispatchCommand{
+			Protect: c.Protect,
+			VkName:  c.VkName,
+			Aliases: aliases[c.VkName],
+			Global:  true,
+		}
+		sort.Strings(dc.Aliases)
+		if len(c.Parameters) > 0 {
+			if h := ctx.Handle(c.Parameters[0].AnalyzedType.Type); h != nil && h.TypeSafe && c.Parameters[0].AnalyzedType.IsBlank {
+				dc.Global = false
+				dc.Device = h == device || device != nil && h.IsOwnedBy(device)
+			}
+		}
+		out = append(out, dc)
+	}
+	return out
+}
+
+// Load returns the statements loading the command with getProcAddr, which
+// is called with the handle and the name of the command, trying the aliases
+// if it returns nothing.
+func (c DispatchCommand) Load(getProcAddr, handle string) string {
+	load := func(name string) string {
+		return fmt.Sprintf("%s = reinterpret_cast<PFN_%s>(%s(%s, \"%s\"));", c.VkName, c.VkName, getProcAddr, handle, name)
+	}
+	out := load(c.VkName)
+	for _, a := range c.Aliases {
+		out += "\n\t\tif (!" + c.VkName + ")\n\t\t\t" + load(a)
+	}
+	return out
+}
@@ -0,0 +1,42 @@
+package cppgen
+
+import (
+	"io"
+	"path/filepath"
+)
+
+// exampleHeader is the name of the generated C++ header in example projects
+const exampleHeader = "vk.hpp"
+
+// Example is the data of the example project templates. It is generated
+// with the same options as the header, so that the project builds against
+// exactly that header.
+type Example struct {
+	Banner        []string
+	Header        string
+	UniqueHandles bool
+	Aggregates    bool
+
+	// the C++ standard the example is built with, designated initializers
+	// of aggregates need C++20
+	CppStd int
+}
+
+// exampleBackends writes the CMake project and the main.cpp of the example
+// project to dir, the header backends write to dir as well.
+func exampleBackends(dir string, example *Example, ctx *Context) []backend {
+	file := func(name, lang, template string) backend {
+		return backend{
+			name: "example " + name,
+			file: filepath.Join(dir, name),
+			lang: lang,
+			emit: func(w io.Writer) error {
+				return ctx.execute(w, template, example)
+			},
+		}
+	}
+	return []backend{
+		file("CMakeLists.txt", "cmake", "examplecmake"),
+		file("main.cpp", "c++", "examplemain"),
+	}
+}
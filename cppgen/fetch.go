@@ -0,0 +1,89 @@
+package cppgen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const khronosSpecURL = "https://raw.githubusercontent.com/KhronosGroup/Vulkan-Docs/%s/xml/vk.xml"
+
+// specURL returns the URL to fetch the spec from, or "" if the spec file
+// is given on the command line.
+func (o *Options) specURL() string {
+	if o.SpecURL != "" {
+		return o.SpecURL
+	}
+	if o.SpecVersion != "" {
+		return fmt.Sprintf(khronosSpecURL, o.SpecVersion)
+	}
+	return ""
+}
+
+// specCachePath returns the cache file for url, tagged releases are named
+// after the tag, other URLs after their hash.
+func specCachePath(dir, url string) string {
+	prefix, suffix := khronosSpecURL[:strings.Index(khronosSpecURL, "%s")], "/xml/vk.xml"
+	tag := strings.TrimSuffix(strings.TrimPrefix(url, prefix), suffix)
+	if len(tag) != len(url)-len(prefix)-len(suffix) || strings.Contains(tag, "/") {
+		sum := sha256.Sum256([]byte(url))
+		tag = hex.EncodeToString(sum[:8])
+	}
+	return filepath.Join(dir, "vk-"+tag+".xml")
+}
+
+// fetchSpec returns the path of a local copy of the spec at url, downloading
+// it into the cache directory unless it's there already. Cached copies never
+// expire, which is right for tags, specs fetched from a branch have to be
+// removed from the cache to be refreshed.
+func fetchSpec(url, cacheDir string) (string, error) {
+	if cacheDir == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return "", err
+		}
+		cacheDir = filepath.Join(dir, "vulkangen")
+	}
+	path := specCachePath(cacheDir, url)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", err
+	}
+
+	log.Printf("fetching %s", url)
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+
+	// download next to the final location, so that an interrupted fetch
+	// never leaves a truncated spec in the cache
+	tmp, err := os.CreateTemp(cacheDir, "fetch-*.xml")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
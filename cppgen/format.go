@@ -0,0 +1,91 @@
+package cppgen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"strings"
+)
+
+// formatFlag is -format, on its own it turns formatting on, -format=file
+// also names the .clang-format file to use.
+type formatFlag struct {
+	on   bool
+	file string
+}
+
+func (f *formatFlag) String() string {
+	return f.file
+}
+
+func (f *formatFlag) IsBoolFlag() bool { return true }
+
+func (f *formatFlag) Set(s string) error {
+	switch s {
+	case "true":
+		*f = formatFlag{on: true}
+	case "false":
+		*f = formatFlag{}
+	default:
+		*f = formatFlag{on: true, file: s}
+	}
+	return nil
+}
+
+// formatted reports whether output in lang is passed through clang-format,
+// only C and C++ are.
+func formatted(lang string) bool {
+	return lang == "c" || lang == "c++"
+}
+
+// formatBackends makes the backends writing headers and sources pass their
+// output through the clang-format of opts. If clang-format can't be found
+// that's logged and the output is written as it is.
+func formatBackends(backends []backend, opts *Options) {
+	path, err := exec.LookPath(opts.ClangFormat)
+	if err != nil {
+		log.Printf("-format: %v, the output isn't formatted", err)
+		return
+	}
+	for i := range backends {
+		b := &backends[i]
+		if !formatted(b.lang) {
+			continue
+		}
+		emit, file := b.emit, b.file
+		b.emit = func(w io.Writer) error {
+			var buf bytes.Buffer
+			if err := emit(&buf); err != nil {
+				return err
+			}
+			return runClangFormat(w, &buf, path, file, opts.Format.file)
+		}
+	}
+}
+
+// runClangFormat formats src as the file would be, with the style of
+// styleFile. Without one clang-format looks for .clang-format in the
+// directories of the file, the current one for STDOUT.
+func runClangFormat(w io.Writer, src io.Reader, path, file, styleFile string) error {
+	if file == "" {
+		file = "vk.hpp"
+	}
+	style := "--style=file"
+	if styleFile != "" {
+		style = "--style=file:" + styleFile
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(path, style, "--assume-filename="+file)
+	cmd.Stdin = src
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("clang-format: %v: %s", err, msg)
+		}
+		return fmt.Errorf("clang-format: %v", err)
+	}
+	return nil
+}
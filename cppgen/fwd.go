@@ -0,0 +1,35 @@
+package cppgen
+
+// ForwardHeader declares the enums, flags, handles and structs of the C++
+// header without defining them, for headers which only pass them around.
+// It needs neither vulkan.h nor the platform headers, so the declarations
+// aren't guarded by the platforms or extensions they belong to: those which
+// the C++ header leaves out are merely never defined.
+type ForwardHeader struct {
+	GuardBegin       string
+	GuardEnd         string
+	Banner           []string
+	Namespace        string
+	VersionNamespace string
+
+	Enums       []Enum
+	BitMasks    []BitMask
+	Handles     []*Handle
+	Structs     []Struct
+	TypeAliases []Alias
+}
+
+func newForwardHeader(params *HeaderParams, ctx *Context) *ForwardHeader {
+	fwd := &ForwardHeader{
+		Banner:           params.Banner,
+		Namespace:        params.Namespace,
+		VersionNamespace: params.VersionNamespace,
+		Enums:            ctx.Enums,
+		BitMasks:         ctx.BitMasks,
+		Handles:          ctx.Handles,
+		Structs:          ctx.Structs,
+		TypeAliases:      ctx.TypeAliases,
+	}
+	fwd.GuardBegin, fwd.GuardEnd = includeGuard(guardName(params.IncludeGuard, "FWD"), "//")
+	return fwd
+}
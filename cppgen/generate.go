@@ -0,0 +1,180 @@
+package cppgen
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// ValidationErrors are the registry validation errors, which fail the
+// generation unless Options.SkipBroken is set.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	return fmt.Sprintf("%d registry validation errors, use -skip-broken to generate around them", len(e))
+}
+
+// Generate writes the output of opts.Lang for reg to w, the C++ header by
+// default. reg is resolved for generation in place, extensions and entities
+// opts leaves out are removed from it. The options writing further files
+// (OutputFile, CHeaderFile, ReportFile, ...) belong to the command line and
+// are ignored, so is SplitDir. Generate can be called concurrently, for
+// different registries.
+func Generate(w io.Writer, reg *registry.Registry, opts Options) error {
+	g, err := newGeneration(&opts)
+	if err != nil {
+		return err
+	}
+	ctx, err := g.prepare(reg)
+	if err != nil {
+		return err
+	}
+	return languageBackend(languageBackends[opts.Lang], "", ctx, &opts).emit(w)
+}
+
+// usageError is an error of the options rather than of the registry, the
+// command line exits with exitUsage for it.
+type usageError struct {
+	err error
+}
+
+func (e usageError) Error() string { return e.err.Error() }
+
+// generation is a single run of the generator, sharing nothing with other
+// runs: the templates, with those of Options.TemplatesDir, the diagnostics
+// recorded and the entity cache of Options.CacheDir. Generate and Main go
+// through it alike.
+type generation struct {
+	opts        *Options
+	templates   *template.Template
+	diagnostics diagnostics
+	cache       *renderCache
+}
+
+// newGeneration checks opts and sets up a generation with them.
+func newGeneration(opts *Options) (*generation, error) {
+	if err := opts.check(); err != nil {
+		return nil, usageError{err}
+	}
+	g := &generation{opts: opts}
+	var err error
+	g.templates, err = tpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	g.templates.Funcs(template.FuncMap{"render": g.render})
+	if opts.TemplatesDir != "" {
+		if err := loadTemplates(g.templates, opts.TemplatesDir); err != nil {
+			return nil, usageError{err}
+		}
+	}
+	if opts.CacheDir != "" && !opts.CheckOnly {
+		// checking renders every entity rather than reusing the cache
+		g.cache, err = newRenderCache(opts.CacheDir, opts, g.templates)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// prepare resolves reg for generation, see prepareRegistry, and returns the
+// context the backends generate from, with the header parameters.
+func (g *generation) prepare(reg *registry.Registry) (*Context, error) {
+	if err := g.prepareRegistry(reg); err != nil {
+		return nil, err
+	}
+	params, err := newHeaderParams(reg, g.opts)
+	if err != nil {
+		return nil, usageError{err}
+	}
+	ctx, err := g.analyze(reg)
+	if err != nil {
+		return nil, err
+	}
+	g.diagnostics.recordIgnored(reg)
+	if ctx.Sections.Handles {
+		params.Handles = ctx.Handles
+	}
+	ctx.Params = &params
+	return &ctx, nil
+}
+
+// prepareRegistry resolves reg for generation: the entities of other APIs
+// are removed, extensions are sorted by number and selected, entities the
+// options filter out are removed with the ones referencing them and so are
+// broken and unsupported ones. Broken entities are ValidationErrors without
+// SkipBroken.
+func (g *generation) prepareRegistry(reg *registry.Registry) error {
+	opts := g.opts
+	selectAPI(reg)
+	// extensions add enum values and fill the extension table in the order
+	// of their numbers, wherever they are in the file
+	sort.SliceStable(reg.Extensions.Extension, func(i, j int) bool {
+		return reg.Extensions.Extension[i].Number < reg.Extensions.Extension[j].Number
+	})
+	resolvePlatforms(reg, &g.diagnostics)
+	if err := selectExtensions(reg, opts); err != nil {
+		return err
+	}
+	pulled, excluded := crossReference(reg, deselectedEntities(reg, opts), opts.PullInTypes)
+	for _, r := range pulled {
+		log.Print("pulled in ", r)
+	}
+	g.diagnostics.recordExclusions(excluded)
+	errs := validateRegistry(reg)
+	if len(errs) > 0 && !opts.SkipBroken {
+		return ValidationErrors(errs)
+	}
+	// what the generator can't handle is left out in any case, with what
+	// depends on it, the header would refer to it otherwise
+	g.diagnostics.recordExclusions(skipBroken(reg, append(errs, unsupportedEntities(reg)...)))
+	return nil
+}
+
+// newHeaderParams returns the parameters of the header and the files going
+// with it for reg and opts.
+func newHeaderParams(reg *registry.Registry, opts *Options) (HeaderParams, error) {
+	headerParams := HeaderParams{
+		Namespace:    opts.Namespace,
+		ModuleName:   strings.Replace(opts.Namespace, "::", ".", -1),
+		VulkanHeader: includeSpec(opts.VulkanHeader),
+		IncludeGuard: opts.IncludeGuard,
+
+		ExportMacro: opts.ExportMacro,
+		Exceptions:  opts.Exceptions,
+		Module:      opts.Module,
+		CppStd:      opts.CppStd,
+		SafeStructs: opts.SafeStructs,
+	}
+	headerParams.GuardBegin, headerParams.GuardEnd = includeGuard(opts.IncludeGuard, "//")
+	if opts.VersionNamespace {
+		headerParams.VersionNamespace = versionNamespace(reg)
+	}
+	if opts.Module {
+		// the module declaration follows the includes, see the header
+		// template
+		headerParams.GuardBegin = "module;"
+	}
+	setupPlatforms(&headerParams, reg, opts)
+	var err error
+	headerParams.Banner, err = newBanner(reg, opts)
+	if err != nil {
+		return HeaderParams{}, err
+	}
+	for _, d := range opts.Defines {
+		headerParams.Defines = append(headerParams.Defines, parseDefine(d))
+	}
+	for _, inc := range opts.Includes {
+		headerParams.Includes = append(headerParams.Includes, includeSpec(inc))
+	}
+	for _, inc := range opts.EpilogueIncludes {
+		headerParams.EpilogueIncludes = append(headerParams.EpilogueIncludes, includeSpec(inc))
+	}
+	return headerParams, nil
+}
@@ -0,0 +1,81 @@
+package cppgen
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// userTemplates returns a -templates directory adding a comment to the
+// preamble of the header.
+func userTemplates(t *testing.T) string {
+	dir := t.TempDir()
+	extra := `{{ define "headerextra" }}// from the user templates{{ end }}`
+	if err := os.WriteFile(filepath.Join(dir, "extra.tmpl"), []byte(extra), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// TestGenerateTemplatesDontLeak checks that the templates of -templates are
+// those of the generation loading them only.
+func TestGenerateTemplatesDontLeak(t *testing.T) {
+	dir := userTemplates(t)
+	custom := generateTestHeader(t, testSpec, func(o *Options) { o.TemplatesDir = dir })
+	if !bytes.Contains(custom, []byte("// from the user templates")) {
+		t.Error("the header doesn't use the user templates")
+	}
+	if plain := generateTestHeader(t, testSpec, nil); bytes.Contains(plain, []byte("// from the user templates")) {
+		t.Error("the user templates of an earlier generation are used")
+	}
+}
+
+// TestGenerateConcurrently checks that concurrent generations with
+// different options don't see each other, run it with -race.
+func TestGenerateConcurrently(t *testing.T) {
+	dir := userTemplates(t)
+	setups := []func(*Options){
+		nil,
+		func(o *Options) { o.Naming = "snake" },
+		func(o *Options) { o.Lang = "rust" },
+		func(o *Options) { o.CacheDir = t.TempDir() },
+		func(o *Options) { o.TemplatesDir = dir },
+	}
+	want := make([][]byte, len(setups))
+	for i, setup := range setups {
+		want[i] = generateTestHeader(t, testSpec, setup)
+	}
+	regs := make([]*registry.Registry, len(setups))
+	for i := range regs {
+		var err error
+		if regs[i], err = registry.ReadFile(testSpec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := make([]bytes.Buffer, len(setups))
+	errs := make([]error, len(setups))
+	var wg sync.WaitGroup
+	for i, setup := range setups {
+		opts := NewOptions()
+		if setup != nil {
+			setup(opts)
+		}
+		wg.Add(1)
+		go func(i int, opts *Options) {
+			defer wg.Done()
+			errs[i] = Generate(&got[i], regs[i], *opts)
+		}(i, opts)
+	}
+	wg.Wait()
+	for i := range setups {
+		if errs[i] != nil {
+			t.Errorf("generation %d: %v", i, errs[i])
+		} else if !bytes.Equal(got[i].Bytes(), want[i]) {
+			t.Errorf("the output of generation %d differs when generated concurrently", i)
+		}
+	}
+}
@@ -0,0 +1,1746 @@
+package cppgen
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+const helpText = `
+usage: vk_cpp_generator [options] <spec_file> [<companion_spec_file>...]
+       vk_cpp_generator [options] -spec-version <tag> [<companion_spec_file>...]
+       vk_cpp_generator coverage <spec_file>
+       vk_cpp_generator validate <spec_file>
+       vk_cpp_generator [options] example -output <dir> <spec_file> [<companion_spec_file>...]
+
+Convert XML specification into C++ header. Writes to STDOUT, unless
+<output_file> is specified.
+
+The output doesn't depend on the order of the registry: enums, bitmasks,
+handles, commands and aliases are sorted by name, structs by their
+dependencies, then by name, and enum values added by extensions come in the
+order of the extension numbers.
+
+With -module the output is a C++20 module interface unit instead, to be
+written to vk.cppm (-o vk.cppm) and imported with "import vk;". Macros the
+header defines, like VK_TYPESAFE_HANDLES, are not visible to importers.
+
+With -split <dir> the header is written to <dir> in parts, to include only
+what a translation unit needs: vk_enums.hpp, vk_handles.hpp, vk_structs.hpp
+and vk_funcs.hpp, each including the one before it.
+
+With -fwd-header <file> the types are also declared in a header of their own
+(vk_fwd.hpp), which needs neither vulkan.h nor the C++ header, for headers
+which only take them by reference or pointer.
+
+With -only and -skip only some sections of the header are generated: enums
+(with bitmasks), handles, structs and commands (with everything else). A
+section needs those before it, -only structs generates enums and handles
+too, and skipping one of them is an error.
+
+The header is generated in the vk namespace and includes <vulkan/vulkan.h>.
+-namespace and -vulkan-include change them, e.g. -namespace acme::vk
+-vulkan-include third_party/vulkan.h. Nested namespaces need -cpp-std 17, a
+module is named after the namespace with dots (acme.vk).
+
+The headers start with #pragma once. With -include-guard <macro> they are
+guarded by #ifndef <macro> instead, for compilers and build systems which
+don't handle it; the parts of -split, the -c-header, the -fwd-header and the
+-reflect-header append _ENUMS, _HANDLES, _STRUCTS, _FUNCS, _C, _FWD and
+_REFLECT to the macro.
+
+The header targets C++11 by default, using features of later standards where
+the compiler has them. With -cpp-std 14, 17 or 20 it assumes that standard:
+setters are constexpr since 14, commands returning something are
+[[nodiscard]] since 17, ArrayProxy takes a std::span, pointer and count
+members get a setter taking a std::span and StructureChain is constrained
+with requires since 20.
+
+With -safe-structs the structs pointing to something get a deep-copying
+variant in vk::safe, owning copies of their arrays, strings and pNext chains,
+for layers and tools keeping create infos beyond the call.
+
+With -json-structs the listed structs, and those they contain, get to_json
+and from_json templates converting them to and from nlohmann::json, or any
+JSON type with its interface, to capture and replay configurations: enums by
+name, flags as arrays of the names of their bits, nested structs as objects.
+Structs pointing to anything but their pNext chain are skipped.
+
+With -spec-version or -spec-url the spec is downloaded from the Khronos
+registry (or the given URL) and cached locally.
+
+Types and enums of companion specs (video.xml) are merged into the main spec,
+so that structs using them resolve. No C++ wrappers are generated for them.
+Other specs given after the main one, such as registries of vendor
+extensions, are layered on top of it in order: what they declare is generated
+like the rest and replaces whatever the specs before them declare by the same
+name.
+
+The coverage command reports which registry elements and attributes the
+generator consumes, supports partially or ignores.
+
+The validate command checks the spec against the parts of the registry
+schema the generator relies on, reporting errors with line numbers, and the
+registry it describes against the invariants checked before generating.
+
+The example command writes the header generated with the given options to
+<dir> together with a CMake project using it: main.cpp creates an instance
+and prints the properties of a physical device.
+
+With -templates <dir> the templates defined by the *.tmpl files of <dir>
+replace the built-in ones of the same name, "handle", "struct", "command",
+etc., see templates.go. The empty "headerextra", "handleextra" and
+"structextra" templates can be defined to add to the end of the preamble, of
+handle classes and of struct classes, to add methods or macros without
+replacing what's there.
+
+With -diff <file> the C++ header isn't written but compared with the file,
+to check in CI that a generated header is up to date. The declarations which
+were added (+), removed (-) or changed (~) are listed and the exit status is
+1 if there are any.
+
+Registry entities which aren't generated as declared are logged. -report
+<file> also writes them to a JSON file, each with a kind: skipped (broken or
+unsupported), degraded (generated without something it refers to),
+excluded (left out by the options) or ignored (left to vulkan.h, like
+defines and function pointers). With -strict the run fails if anything was
+skipped or degraded.
+
+Options can also be read from a JSON file with -config <file>, an object
+whose keys are the names of the options below, e.g. {"cpp-std": 17,
+"extensions": ["VK_KHR_swapchain"], "o": "vk.hpp"}, and "spec" the list of
+spec files, which the arguments replace. Options given on the command line
+override those of the file.
+
+With -check nothing is written: the spec is read and everything the options
+select is generated and thrown away, reporting the problems found along the
+way, to check a new vk.xml quickly in CI. It can be combined with -strict.
+
+With -format the C and C++ headers are passed through clang-format before
+they're written, styled by the .clang-format file found from the output file
+or the one given as -format=<file>. If clang-format can't be found the
+output is written as it is.
+
+With -compile-check <compiler> the headers are also written to a temporary
+directory and a source file including them is compiled with the given C++
+compiler, taking GCC-style options, for the C++ standard of -cpp-std. Options
+to add are taken from $CXXFLAGS and $VULKAN_SDK/include is searched for
+vulkan.h. The run fails if it doesn't compile.
+
+With -watch vulkangen keeps running and generates the output again whenever
+the spec files, the -config and -banner files or the *.tmpl files of
+-templates change, for a quick edit, generate and compile loop while
+customizing templates. A failed run is reported and the next change is
+waited for.
+
+With -lang c a C header is generated instead of the C++ one, with the
+helpers which make sense in C: vkString_Format() and the like returning the
+names of enum values, vkInit_ImageCreateInfo() and the like returning structs
+with sType set and the rest zeroed, and the loader table of -c-header.
+With -lang rust a Rust module is generated: #[repr(C)] structs and unions
+with a Default setting sType, enums and bitmasks as newtypes with constants
+for their values, the commands as extern "system" declarations and function
+pointer types, and a DispatchTable loading them. It only uses core.
+With -lang zig a Zig file is generated, for Zig 0.14: extern structs and
+unions, non-exhaustive enums, bitmasks as packed structs of bools, the
+commands as extern functions and function pointer types, and a DispatchTable
+loading them.
+With -lang csharp a C# file is generated, for C# 9 with unsafe code:
+sequential structs and explicit unions, enums and [Flags] enums for the
+bitmasks, the commands as [DllImport] methods of the static class Vk and a
+DispatchTable of unmanaged function pointers loading them.
+With -lang python a Python module is generated: ctypes structures and
+unions, IntEnums and IntFlags, the commands' prototypes and a DispatchTable
+loading them from the Vulkan loader, with the names of the C++ header.
+
+-markdown <file> also writes a Markdown reference of the generated handles,
+enums, bitmasks, structs and commands, with their members, values and
+signatures and links to the man pages of what they wrap.
+
+-reflect-header <file> also writes a header of runtime metadata (vk_reflect.hpp)
+for inspectors, serializers and other code handling Vulkan objects
+generically: the structs with the names, C types, offsets and sizes of their
+members, the enums with the names of their values and the commands with their
+signatures, in the meta namespace (vk::meta), vk::reflect visits the members
+of a struct. It only needs vulkan.h.
+
+-mock <file> also writes a C++ source (vk_mock.cpp) defining every command as
+a stub, for unit tests to link instead of the Vulkan loader: the stubs
+record their calls, make up the handles they return and return VK_SUCCESS,
+or the result set with vkmock::setResult. Tests define VKMOCK_API_ONLY and
+include the source for the declarations of vkmock.
+
+-dump-ir <file> writes the registry as it's resolved for generation to a
+JSON file: the handles, enums with their computed values, bitmasks, structs
+and commands with their analyzed types, aliases, constants and extensions,
+each with the guard of its platform. Other tools can build on it without
+parsing vk.xml themselves.
+
+-dump-graph <file> writes the dependency graph of the generated structs,
+handles, enums and commands as GraphViz (deps.dot): what each of them
+references, grouped by the version or extension requiring it, with the
+position of the structs in the header. It shows why a type is generated and
+in which order.
+
+The exit status is 2 for bad options or arguments, 3 if the spec can't be
+read, parsed or validated, 4 if generating or writing the output failed and
+5 if -strict found skipped or degraded entities.
+
+Options:
+`
+
+// Exit codes, 1 is left to -diff and validate, for differences and schema
+// errors. Failures are reported without a stack trace, panics are bugs.
+const (
+	exitUsage    = 2 // bad options or arguments, as for undefined flags
+	exitSpec     = 3 // the spec can't be read, parsed or validated
+	exitGenerate = 4 // generating or writing the output failed
+	exitStrict   = 5 // -strict and entities were skipped or degraded
+)
+
+func fatal(code int, v ...interface{}) {
+	log.Print(v...)
+	os.Exit(code)
+}
+
+func fatalf(code int, format string, v ...interface{}) {
+	log.Printf(format, v...)
+	os.Exit(code)
+}
+
+// exitCode returns exitUsage for errors of the options, code for the
+// others.
+func exitCode(err error, code int) int {
+	if _, ok := err.(usageError); ok {
+		return exitUsage
+	}
+	return code
+}
+
+// check exits with code if there is an error.
+func check(code int, err error) {
+	if err != nil {
+		fatal(code, err)
+	}
+}
+
+func convertEnumValueName(naming namingPolicy, expand, enum, name string) string {
+	return trimEnumValueName(naming, expand, enumValuePrefix(enum), enum, name)
+}
+
+// VkImageUsageFlagBitsKHR -> VK_IMAGE_USAGE, VkPipelineStageFlagBits2 ->
+// VK_PIPELINE_STAGE_2
+func enumValuePrefix(enum string) string {
+	senum, _ := trimTagSuffix(enum)
+	if s := strings.TrimSuffix(senum, "FlagBits2"); s != senum {
+		return toSnakeCase(s) + "_2"
+	}
+	return toSnakeCase(strings.TrimSuffix(senum, "FlagBits"))
+}
+
+// trimEnumValueName is convertEnumValueName with the enum value prefix
+// computed by the caller, it is the same for all values of an enum.
+func trimEnumValueName(naming namingPolicy, expand, prefix, enum, name string) string {
+	// strip prefix
+	if expand != "" && strings.HasPrefix(name, expand) {
+		name = strings.TrimPrefix(name, expand)
+	} else if strings.HasPrefix(name, prefix+"_") {
+		name = name[len(prefix)+1:]
+	}
+
+	if enum == "VkResult" {
+		// special case
+		name = strings.TrimPrefix(name, "VK_")
+	}
+	if strings.HasPrefix(name, "VK_COLORSPACE") {
+		// hack
+		name = strings.TrimPrefix(name, "VK_COLORSPACE")
+	}
+
+	name, _ = trimTagSuffix(name)
+	name = strings.TrimSuffix(name, "_BIT")
+	return naming.EnumValue(name)
+}
+
+func convertVkName(name string) string {
+	return strings.TrimPrefix(name, "Vk")
+}
+
+func convertEnumName(name string) string    { return convertVkName(name) }
+func convertHandleName(name string) string  { return convertVkName(name) }
+func convertBitMaskName(name string) string { return convertVkName(name) }
+func convertStructName(name string) string  { return convertVkName(name) }
+func convertCommandName(name string) string {
+	name = strings.TrimPrefix(name, "vk")
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[0:1]) + name[1:]
+}
+
+// PIPELINE_DEPTH_STENCIL_STATE_CREATE_INFO -> PipelineDepthStencilStateCreateInfo
+func toCamelCase(s string) string {
+	if len(s) <= 1 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	var prev rune
+	for i, r := range s {
+		if r != '_' {
+			if i == 0 || prev == '_' || unicode.IsDigit(prev) {
+				b.WriteRune(r)
+			} else {
+				b.WriteRune(unicode.ToLower(r))
+			}
+		}
+		prev = r
+	}
+	return b.String()
+}
+
+// PipelineDepthStencilStateCreateInfo -> PIPELINE_DEPTH_STENCIL_STATE_CREATE_INFO
+func toSnakeCase(s string) string {
+	if len(s) <= 1 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + len(s)/4)
+	var prev rune
+	for i, r := range s {
+		if i != 0 && unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)) {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+		prev = r
+	}
+	return b.String()
+}
+
+var knownTags = []string{
+	"KHR",
+	"EXT",
+}
+
+func trimTagSuffix(s string) (string, string) {
+	for _, tag := range knownTags {
+		stag := "_" + tag
+		if strings.HasSuffix(s, stag) {
+			return strings.TrimSuffix(s, stag), tag
+		}
+		if strings.HasSuffix(s, tag) {
+			return strings.TrimSuffix(s, tag), tag
+		}
+	}
+	return s, ""
+}
+
+func bitMaskNameToEnumName(s string) string {
+	s, tagUsed := trimTagSuffix(s)
+	if strings.HasSuffix(s, "Flags") {
+		s = strings.TrimSuffix(s, "Flags") + "FlagBits"
+	}
+	return s + tagUsed
+}
+
+// bitMaskEnumName returns the name of the enum defining bits of a bitmask
+// type. Newer specs point to it explicitly with "bitvalues" (64-bit flags) or
+// "requires", the name is derived from the bitmask name only for old specs.
+func bitMaskEnumName(t *registry.Type) string {
+	if t.BitValues != "" {
+		return t.BitValues
+	}
+	if t.Requires != "" {
+		return t.Requires
+	}
+	return bitMaskNameToEnumName(t.InnerName)
+}
+
+// pipelineCacheUUID, deviceLUID, etc.
+func isUUIDName(name string) bool {
+	return strings.HasSuffix(name, "UUID") || strings.HasSuffix(name, "LUID")
+}
+
+// structMemberLen returns the len attribute of a member, or its altlen if
+// it's written in latexmath (codeSize / 4).
+func structMemberLen(m *registry.TypeName) string {
+	if strings.HasPrefix(m.Len, "latexmath:") && m.AltLen != "" {
+		return m.AltLen
+	}
+	return m.Len
+}
+
+// extensionProtect returns the guard of everything the extension defines,
+// provisional extensions are only declared by vulkan.h if
+// VK_ENABLE_BETA_EXTENSIONS is defined.
+func extensionProtect(e *registry.Extension) Protect {
+	if e.Unguarded {
+		return Protect{}
+	}
+	macro := e.Protect
+	if macro == "" && e.Provisional {
+		macro = "VK_ENABLE_BETA_EXTENSIONS"
+	}
+	if macro == "" {
+		return Protect{}
+	}
+	return Protect{
+		Begin: "#ifdef " + macro,
+		End:   "#endif",
+	}
+}
+
+// apiConstants returns the integer API constants (VK_UUID_SIZE, etc.), used
+// to resolve symbolic array sizes.
+func apiConstants(reg *registry.Registry) map[string]int {
+	constants := map[string]int{}
+	for _, xe := range reg.Enums {
+		if xe.Name != "API Constants" {
+			continue
+		}
+		for _, v := range xe.Values {
+			if n, err := registry.ParseEnumValue(v.Value); err == nil {
+				constants[v.Name] = int(n)
+			}
+		}
+	}
+	return constants
+}
+
+type HeaderParams struct {
+	GuardBegin string
+	GuardEnd   string
+	Namespace  string
+
+	// the module exporting Namespace, see Options.Module
+	ModuleName string
+
+	// include file spec of vulkan.h, see Options.VulkanHeader
+	VulkanHeader string
+
+	// see Options.IncludeGuard
+	IncludeGuard string
+
+	// inline namespace in Namespace, see versionNamespace
+	VersionNamespace string
+
+	// emitted before vulkan.h is included
+	Defines []Define
+
+	// symbol visibility macro, see Options.ExportMacro
+	ExportMacro string
+
+	// see Options.Exceptions
+	Exceptions bool
+
+	// see Options.Module
+	Module bool
+
+	// see Options.CppStd
+	CppStd int
+
+	// see Options.SafeStructs
+	SafeStructs bool
+
+	// the part of the split header this one follows, it's included instead
+	// of the preamble, see splitParts
+	Include string
+
+	// lines of the comment atop the file, see newBanner
+	Banner []string
+
+	// handles std::hash is specialized for, after the namespace is closed
+	Handles []*Handle
+
+	// include file specs (<foo.h> or "foo.h"), included after vulkan.h and
+	// at the very end of the header
+	Includes         []string
+	EpilogueIncludes []string
+}
+
+// CHeader is the C-compatible part of the output, the C++ header includes it.
+type CHeader struct {
+	GuardBegin   string
+	GuardEnd     string
+	VulkanHeader string
+
+	Banner    []string
+	Defines   []Define
+	Constants []Constant
+	Commands  []Command
+}
+
+type Define struct {
+	Name  string
+	Value string
+}
+
+// parseDefine parses NAME or NAME=VALUE.
+func parseDefine(s string) Define {
+	if i := strings.Index(s, "="); i != -1 {
+		return Define{Name: s[:i], Value: s[i+1:]}
+	}
+	return Define{Name: s}
+}
+
+// includeGuard returns the lines opening and closing a header: #pragma once,
+// or else an #ifndef guard of macro, its #endif commented in the style of
+// comment ("//" or "/*").
+func includeGuard(macro, comment string) (begin, end string) {
+	if macro == "" {
+		return "#pragma once", ""
+	}
+	note := "// " + macro
+	if comment == "/*" {
+		note = "/* " + macro + " */"
+	}
+	return "#ifndef " + macro + "\n#define " + macro, "\n#endif " + note
+}
+
+// guardName is the include guard macro of the header named part next to
+// the one guarded by macro, none if that one isn't guarded either.
+func guardName(macro, part string) string {
+	if macro == "" {
+		return ""
+	}
+	return macro + "_" + part
+}
+
+// isNamespace reports whether s is a C++ namespace, maybe nested (a::b).
+func isNamespace(s string) bool {
+	for _, n := range strings.Split(s, "::") {
+		if !isIdentifier(n) {
+			return false
+		}
+	}
+	return true
+}
+
+// isIdentifier reports whether s can be a C macro name.
+func isIdentifier(s string) bool {
+	if s == "" || s[0] >= '0' && s[0] <= '9' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDepNameChar(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// includeSpec quotes a bare include file name, <foo.h> and "foo.h" are left
+// as is.
+func includeSpec(s string) string {
+	if strings.HasPrefix(s, "<") || strings.HasPrefix(s, `"`) {
+		return s
+	}
+	return `"` + s + `"`
+}
+
+// Constant is an API constant (VK_UUID_SIZE, etc.), Value is the C literal
+// from the registry.
+type Constant struct {
+	Name  string
+	Value string
+}
+
+type Handle struct {
+	Protect  Protect
+	Name     string
+	VkName   string
+	TypeSafe bool
+
+	// Handles this one is created from and owned by, usually just one. The
+	// first one is the primary parent.
+	Parents []*Handle
+
+	// ObjectType enum value of the handle, if the registry specifies it
+	ObjectType *EnumValue
+
+	// member functions calling the commands taking the handle first
+	Methods []Method
+}
+
+func (h *Handle) Parent() *Handle {
+	if len(h.Parents) == 0 {
+		return nil
+	}
+	return h.Parents[0]
+}
+
+// Ancestors returns the chain of primary parents, starting from the root
+// (usually Instance) and ending with the direct parent.
+func (h *Handle) Ancestors() []*Handle {
+	var out []*Handle
+	for p := h.Parent(); p != nil; p = p.Parent() {
+		out = append([]*Handle{p}, out...)
+		if len(out) > 32 {
+			panic("cyclic handle parents: " + h.VkName)
+		}
+	}
+	return out
+}
+
+// IsOwnedBy reports whether owner is one of the handle's ancestors,
+// following all parents, not just the primary ones.
+func (h *Handle) IsOwnedBy(owner *Handle) bool {
+	for _, p := range h.Parents {
+		if p == owner || p.IsOwnedBy(owner) {
+			return true
+		}
+	}
+	return false
+}
+
+type EnumValue struct {
+	Protect Protect
+	Name    string
+	VkName  string
+
+	// value literal exactly as written in the registry, empty for values
+	// given by bitpos or extension offset
+	Value string
+
+	// computed numeric value, valid if HasNumber
+	Number    int64
+	HasNumber bool
+}
+
+type Protect struct {
+	Begin string
+	End   string
+}
+
+type Enum struct {
+	Protect Protect
+	Name    string
+	VkName  string
+	Values  []EnumValue
+	Aliases []Alias
+	used    bool
+
+	// 64 for the bits of VkFlags64 bitmasks, 0 for int enums
+	BitWidth int
+
+	// values by number minus StringBase for densely numbered enums, holes
+	// are left blank. getEnumString indexes the array of their names.
+	StringArray []EnumValue
+	StringBase  int64
+
+	// values sorted by number, getEnumString does a binary search over them
+	// instead of a switch if set
+	StringTable []EnumValue
+}
+
+// Base is the underlying type of the enum class, empty for int.
+func (e Enum) Base() string {
+	if e.BitWidth == 64 {
+		return "VkFlags64"
+	}
+	return ""
+}
+
+// HasUnguardedValue reports whether the enum has a value available
+// regardless of the platform or beta extension guards, so that the array of
+// its values can't be empty.
+func (e Enum) HasUnguardedValue() bool {
+	for _, v := range e.Values {
+		if v.Protect.Begin == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// GuardedCount is the number of values of an enum under the guard Protect.
+type GuardedCount struct {
+	Protect Protect
+	Count   int
+}
+
+// ValueCounts returns the number of values of the enum under each guard in
+// order of appearance, the unguarded ones first, for the size of the array
+// of its values to follow the guards.
+func (e Enum) ValueCounts() []GuardedCount {
+	counts := []GuardedCount{{}}
+	index := map[string]int{"": 0}
+	for _, v := range e.Values {
+		i, ok := index[v.Protect.Begin]
+		if !ok {
+			i = len(counts)
+			index[v.Protect.Begin] = i
+			counts = append(counts, GuardedCount{Protect: v.Protect})
+		}
+		counts[i].Count++
+	}
+	return counts
+}
+
+// buildStringTable sets up the StringArray of enums whose values are known
+// and at least half of their range, or else the StringTable of those with
+// at least min values. The others get a switch.
+func (e *Enum) buildStringTable(min int) {
+	if len(e.Values) == 0 {
+		return
+	}
+	lo, hi := e.Values[0].Number, e.Values[0].Number
+	for _, v := range e.Values {
+		if !v.HasNumber {
+			return
+		}
+		if v.Number < lo {
+			lo = v.Number
+		}
+		if v.Number > hi {
+			hi = v.Number
+		}
+	}
+	// the bits of 64-bit flags go up to the sign bit, the range may not fit
+	// in an int64
+	if uint64(hi-lo) < 2*uint64(len(e.Values)) {
+		e.StringBase = lo
+		e.StringArray = make([]EnumValue, hi-lo+1)
+		for _, v := range e.Values {
+			if e.StringArray[v.Number-lo].Name == "" {
+				e.StringArray[v.Number-lo] = v
+			}
+		}
+		return
+	}
+	if min <= 0 || len(e.Values) < min {
+		return
+	}
+	e.StringTable = append([]EnumValue(nil), e.Values...)
+	sort.SliceStable(e.StringTable, func(i, j int) bool {
+		return e.StringTable[i].Number < e.StringTable[j].Number
+	})
+}
+
+// StringIndex is the index of the name of value e in the StringArray.
+func (e Enum) StringIndex() string {
+	if e.StringBase == 0 {
+		return "static_cast<int64_t>(e)"
+	}
+	return fmt.Sprintf("static_cast<int64_t>(e) - (%d)", e.StringBase)
+}
+
+// hasName reports whether a value or an alias is called name.
+func (e *Enum) hasName(name string) bool {
+	for _, v := range e.Values {
+		if v.Name == name {
+			return true
+		}
+	}
+	for _, a := range e.Aliases {
+		if a.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Value returns the enum value with the given vk name, or nil.
+func (e *Enum) Value(vkName string) *EnumValue {
+	for i := range e.Values {
+		if e.Values[i].VkName == vkName {
+			return &e.Values[i]
+		}
+	}
+	return nil
+}
+
+type BitMask struct {
+	Protect Protect
+	Name    string
+	VkName  string
+	Enum    *Enum
+
+	// 32 for VkFlags, 64 for VkFlags64
+	BitWidth int
+}
+
+type Command struct {
+	Protect    Protect
+	Name       string
+	VkName     string
+	RetType    string
+	RetVkType  string
+	Parameters []CommandParameter
+
+	// the Result is passed through checkResult, see Options.Exceptions
+	Throws bool
+
+	// overload returning the output parameter, nil if there is none
+	Value *ValueReturn
+
+	// overload taking input arrays as ArrayProxy, nil if there is none
+	Arrays *ArrayOverload
+}
+
+// Noexcept is the exception specification of the command wrapper.
+func (c Command) Noexcept() string {
+	return noexceptSpec(c.Throws)
+}
+
+// noexceptSpec returns noexcept, or for wrappers throwing Error on error
+// codes VKGEN_CHECKED_NOEXCEPT, which is noexcept only if
+// VKGEN_NO_EXCEPTIONS is defined.
+func noexceptSpec(throws bool) string {
+	if throws {
+		return "VKGEN_CHECKED_NOEXCEPT"
+	}
+	return "noexcept"
+}
+
+type CommandParameter struct {
+	Name         string
+	Type         string
+	VkType       string
+	AnalyzedType AnalyzedType
+	Converter    TypeConverter
+
+	// len attribute of pointer parameters, the number of elements
+	Len string
+}
+
+type Struct struct {
+	Protect Protect
+	Name    string
+	VkName  string
+
+	// the C name of the sType value, from the values attribute of the
+	// member, empty if the struct has none
+	TypeName string
+	HasSType bool
+	Members  []StructMember
+	ReadOnly bool
+	Union    bool
+
+	// see Options.AggregateStructs, unions remain wrappers
+	Aggregate bool
+
+	// the StructureType value of TypeName, empty if the struct has no sType
+	// value or the enum has no such value
+	SType string
+}
+
+type StructMember struct {
+	Name         string
+	Type         string
+	VkType       string
+	AnalyzedType AnalyzedType
+	Converter    TypeConverter
+	IsVersion    bool
+	IsUUID       bool
+	Compare      CompareKind
+
+	// const char * member, its setter also takes a std::string
+	IsString bool
+
+	// C expression for the number of elements, for fixed array members
+	ArraySize string
+
+	// len attribute of pointer members, the number of elements
+	Len string
+
+	// setter assigning the member and its count from a std::span, nil if
+	// there is none
+	Span *SpanSetter
+
+	naming namingPolicy
+}
+
+// Accessor names the accessors of the member, those with a suffix
+// (pipelineCacheUUIDArray) are named with it appended.
+func (m *StructMember) Accessor(suffix string) string {
+	return m.naming.Member(m.Name + suffix)
+}
+
+// Constexpr reports whether the accessors of the member can be constexpr,
+// which they can't if they convert the pointer through reinterpret_cast.
+func (m *StructMember) Constexpr() bool {
+	switch m.Converter.(type) {
+	case NopConverter:
+		return true
+	case *StaticCastConverter, *BitMaskConverter:
+		return !m.AnalyzedType.IsPointer
+	}
+	return false
+}
+
+// Print returns the statement printing the member of the struct s to os,
+// through its getter, or directly in aggregates, see the ostream template.
+// Getters of fixed arrays return a pointer to the first element.
+func (m *StructMember) Print(aggregate bool) string {
+	name := m.Accessor("")
+	value := "s." + name
+	if !aggregate {
+		value += "()"
+	}
+	at := &m.AnalyzedType
+	switch {
+	case strings.HasPrefix(at.Type, "PFN_"):
+		return fmt.Sprintf("os << reinterpret_cast<const void*>(%s);", value)
+	case at.IsArray && at.Type != "char" && !aggregate:
+		return fmt.Sprintf("printArray(os, %s, sizeof(s.c_ptr()->%s) / sizeof(*%s));", value, m.Name, value)
+	}
+	return fmt.Sprintf("printValue(os, %s);", value)
+}
+
+// Reflect returns the value of the member of the struct s that reflect
+// passes to the visitor: the getter, or the member in aggregates. Fixed
+// arrays are passed as arrays rather than the pointer their getter returns,
+// bit-fields by value.
+func (m *StructMember) Reflect(aggregate bool) string {
+	at := &m.AnalyzedType
+	switch {
+	case aggregate && at.BitWidth > 0:
+		return fmt.Sprintf("static_cast<%s>(s.%s)", at.Type, m.Accessor(""))
+	case aggregate:
+		return "s." + m.Accessor("")
+	case at.IsArray:
+		return fmt.Sprintf("reinterpret_cast<const %s (&)%s>(s.c_ptr()->%s)", convertVkName(at.Type), at.ArrayDims(), m.Name)
+	}
+	return "s." + m.Accessor("") + "()"
+}
+
+// Context is everything the templates generate code from. It is immutable
+// once newContext returns, the backends read it concurrently.
+type Context struct {
+	Handles  []*Handle
+	BitMasks []BitMask
+	Enums    []Enum
+	Structs  []Struct
+	Commands []Command
+
+	StructExtensions []StructExtension
+
+	// nil unless Options.DynamicDispatch
+	Dispatch []DispatchCommand
+
+	Constants []Constant
+
+	Extensions        []ExtensionInfo
+	Exceptions        bool
+	Module            bool
+	UniqueHandles     []UniqueHandle
+	TypeAliases       []Alias
+	CommandAliases    []Alias
+	SpirvExtensions   []SpirvEntry
+	SpirvCapabilities []SpirvEntry
+	Sync              Sync
+	Serializers       []SerialStruct
+	JSONStructs       []JSONStruct
+
+	// the parameters of the headers, set once they're complete, before the
+	// backends run
+	Params *HeaderParams
+
+	// the types named with Options.TypePrefix and TypeSuffix, declared
+	// after everything else, which uses the C types of the same names
+	AffixedTypes []Alias
+
+	// nil unless vkSetDebugUtilsObjectNameEXT is generated
+	DebugName *DebugName
+
+	// deep-copying struct variants, see Options.SafeStructs
+	SafeStructs []SafeStruct
+
+	// see Options.CppStd
+	CppStd int
+
+	// see Options.Namespace
+	Namespace string
+
+	// see Options.Only and Skip
+	Sections Sections
+
+	converters map[string]TypeConverter
+	names      *interner
+	naming     namingPolicy
+
+	// of the generation building the context
+	templates *template.Template
+	diags     *diagnostics
+}
+
+// execute writes the output of the named template for data to w.
+func (ctx *Context) execute(w io.Writer, name string, data interface{}) error {
+	return ctx.templates.ExecuteTemplate(w, name, data)
+}
+
+// Handle returns the handle with the given vk name, or nil.
+func (ctx *Context) Handle(vkName string) *Handle {
+	for _, h := range ctx.Handles {
+		if h.VkName == vkName {
+			return h
+		}
+	}
+	return nil
+}
+
+// HasObjectTypes reports whether handles are mapped to ObjectType values.
+func (ctx *Context) HasObjectTypes() bool {
+	for _, h := range ctx.Handles {
+		if h.ObjectType != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// sortByName puts the enums, bitmasks, handles, commands and aliases in the
+// order of their names instead of that of the registry, so that moving them
+// around in vk.xml doesn't change the header. Structs are sorted by
+// sortStructsByDeps, enum values and struct members keep the registry order.
+func (ctx *Context) sortByName() {
+	sort.SliceStable(ctx.Enums, func(i, j int) bool { return ctx.Enums[i].Name < ctx.Enums[j].Name })
+	sort.SliceStable(ctx.BitMasks, func(i, j int) bool { return ctx.BitMasks[i].Name < ctx.BitMasks[j].Name })
+	sort.SliceStable(ctx.Handles, func(i, j int) bool { return ctx.Handles[i].Name < ctx.Handles[j].Name })
+	sort.SliceStable(ctx.Commands, func(i, j int) bool { return ctx.Commands[i].Name < ctx.Commands[j].Name })
+	sort.SliceStable(ctx.TypeAliases, func(i, j int) bool { return ctx.TypeAliases[i].Name < ctx.TypeAliases[j].Name })
+	sort.SliceStable(ctx.CommandAliases, func(i, j int) bool { return ctx.CommandAliases[i].Name < ctx.CommandAliases[j].Name })
+}
+
+type StructsSort []Struct
+
+func (s StructsSort) Len() int           { return len(s) }
+func (s StructsSort) Less(i, j int) bool { return s[i].VkName < s[j].VkName }
+func (s StructsSort) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func (ctx *Context) sortStructsByDeps() {
+	// we need to sort struct by deps
+	set := map[string]*Struct{}
+	for i, s := range ctx.Structs {
+		set[s.VkName] = &ctx.Structs[i]
+	}
+
+	// now we just go over structs many times, if a struct has no deps in set,
+	// we remove it from set and add it to array, then repeat, note that this
+	// process will not break cycles, but I've added protection against cycles.
+	// Each round is sorted by name, so the order doesn't depend on the map
+	// or the registry.
+	lastOutLen := 0
+	out := make([]Struct, 0, len(ctx.Structs))
+	for len(set) > 0 {
+		for _, s := range set {
+			hasDeps := false
+			for _, m := range s.Members {
+				if m.AnalyzedType.Type == s.VkName {
+					// pointer to itself, e.g. VkBaseOutStructure::pNext
+					continue
+				}
+				if _, ok := set[m.AnalyzedType.Type]; ok {
+					hasDeps = true
+					break
+				}
+			}
+			if !hasDeps {
+				out = append(out, *s)
+			}
+		}
+		if len(out) == lastOutLen {
+			panic("circular dependencies detected")
+		}
+		for i := lastOutLen; i < len(out); i++ {
+			delete(set, out[i].VkName)
+		}
+		sort.Sort(StructsSort(out[lastOutLen:]))
+		lastOutLen = len(out)
+	}
+	ctx.Structs = out
+}
+
+func (ctx *Context) resolveStructMemberConverters() {
+	for _, s := range ctx.Structs {
+		for i := range s.Members {
+			m := &s.Members[i]
+			if m.AnalyzedType.IsArray {
+				// hacky way to handle array types
+				m.Converter = ctx.names.arrayConverter(m.AnalyzedType.Type)
+				continue
+			}
+
+			// try to get a type-specific converter
+			conv, ok := ctx.converters[m.AnalyzedType.Type]
+			if ok {
+				m.Converter = conv
+			}
+		}
+	}
+}
+
+func (ctx *Context) resolveCommandParameterConverters() {
+	for _, c := range ctx.Commands {
+		for i := range c.Parameters {
+			p := &c.Parameters[i]
+			conv, ok := ctx.converters[p.AnalyzedType.Type]
+			if ok {
+				p.Converter = conv
+			}
+		}
+	}
+}
+
+func (g *generation) newContext(reg *registry.Registry) Context {
+	return g.buildContext(reg, newInterner())
+}
+
+// buildContext is newContext with the given interner, nil builds every name
+// from scratch.
+func (g *generation) buildContext(reg *registry.Registry, names *interner) Context {
+	opts := g.opts
+	var ctx Context
+	g.bindContext(&ctx)
+	ctx.names = names
+	ctx.CppStd = opts.CppStd
+	ctx.Namespace = opts.Namespace
+	ctx.Sections, _ = selectSections(opts.Only, opts.Skip)
+	ctx.converters = map[string]TypeConverter{}
+	enumMap := map[string]*Enum{}      // vk enum name -> Enum
+	expandMap := map[string]string{}   // vk enum name -> expand prefix
+	protectMap := map[string]Protect{} // vk type name -> protect string
+	handleParents := map[*Handle][]string{}
+	constants := apiConstants(reg)
+	for i := range reg.Extensions.Extension {
+		e := &reg.Extensions.Extension[i]
+		protect := extensionProtect(e)
+		if protect.Begin == "" {
+			continue
+		}
+		for _, t := range e.Require.Types {
+			protectMap[t.Name] = protect
+		}
+		for _, c := range e.Require.Commands {
+			protectMap[c.Name] = protect
+		}
+	}
+	for _, xe := range reg.Enums {
+		if xe.External {
+			continue
+		}
+		if xe.Name == "API Constants" {
+			for _, v := range xe.Values {
+				if v.Value != "" {
+					ctx.Constants = append(ctx.Constants, Constant{Name: v.Name, Value: v.Value})
+				}
+			}
+		}
+		e := &Enum{
+			Protect:  protectMap[xe.Name],
+			Name:     convertEnumName(xe.Name),
+			VkName:   xe.Name,
+			BitWidth: xe.BitWidth,
+		}
+		for _, v := range xe.Values {
+			if v.Alias != "" {
+				continue
+			}
+			n, ok := registry.EnumValueNumber(v.Value, v.BitPos)
+			e.Values = append(e.Values, EnumValue{
+				Name:      ctx.names.enumValueName(ctx.naming, xe.Expand, xe.Name, v.Name),
+				VkName:    v.Name,
+				Value:     v.Value,
+				Number:    n,
+				HasNumber: ok,
+			})
+		}
+		enumMap[xe.Name] = e
+		expandMap[xe.Name] = xe.Expand
+		ctx.converters[xe.Name] = &StaticCastConverter{
+			CppName: e.Name,
+			VkName:  xe.Name,
+		}
+	}
+	// Core versions and extensions add values to existing enums.
+	extendEnum := func(req *registry.Require, protect Protect, ext int) {
+		for _, re := range req.Enums {
+			if re.Extends == "" || re.Alias != "" {
+				continue
+			}
+			e, ok := enumMap[re.Extends]
+			if !ok {
+				ctx.diags.diagnose("skipped", re.Name, "enum value %s extends unknown enum %s", re.Name, re.Extends)
+				continue
+			}
+			if e.Value(re.Name) != nil {
+				// the same value is often required by several blocks
+				continue
+			}
+			n, ok := re.Number(ext)
+			e.Values = append(e.Values, EnumValue{
+				Protect:   protect,
+				Name:      ctx.names.enumValueName(ctx.naming, expandMap[re.Extends], re.Extends, re.Name),
+				VkName:    re.Name,
+				Value:     re.Value,
+				Number:    n,
+				HasNumber: ok,
+			})
+		}
+	}
+	for i := range reg.Features {
+		extendEnum(&reg.Features[i].Require, Protect{}, 0)
+	}
+	for i := range reg.Extensions.Extension {
+		e := &reg.Extensions.Extension[i]
+		if opts.extensionExcluded(e) != "" {
+			continue
+		}
+		extendEnum(&e.Require, extensionProtect(e), e.Number)
+	}
+	ctx.resolveEnumAliases(reg, opts, enumMap, expandMap)
+	for _, e := range enumMap {
+		e.buildStringTable(opts.EnumStringTable)
+	}
+	// Separate pass on bitmasks, so that we know which enums are used.
+	// Technically bitmasks are placed before enums in vk.xml, but who
+	// guaranees that.
+	for _, t := range reg.Types.Type {
+		if t.External || t.Alias != "" {
+			continue
+		}
+		switch t.Category {
+		case "bitmask":
+			if t.InnerType != "VkFlags" && t.InnerType != "VkFlags64" {
+				ctx.diags.diagnose("skipped", t.InnerName, "unrecognized bitmask type %s of %s", t.InnerType, t.InnerName)
+				continue
+			}
+
+			enumName := bitMaskEnumName(&t)
+			enum, ok := enumMap[enumName]
+			if !ok {
+				// broken xml, some enums are missing, let's just create them
+				enum = &Enum{Name: convertEnumName(enumName), VkName: enumName}
+				enumMap[enumName] = enum
+			}
+			// we also clear protect, because in all cases bit mask is already
+			// wrapped
+			enum.Protect = Protect{}
+			enum.used = true
+
+			bm := BitMask{
+				Protect:  protectMap[t.InnerName],
+				Name:     convertBitMaskName(t.InnerName),
+				VkName:   t.InnerName,
+				Enum:     enum,
+				BitWidth: 32,
+			}
+			if t.InnerType == "VkFlags64" {
+				bm.BitWidth, enum.BitWidth = 64, 64
+			}
+			ctx.BitMasks = append(ctx.BitMasks, bm)
+			ctx.converters[t.InnerName] = &BitMaskConverter{
+				CppName: bm.Name,
+				VkName:  bm.VkName,
+			}
+		}
+	}
+	for _, t := range reg.Types.Type {
+		if t.External || t.Alias != "" {
+			continue
+		}
+		switch t.Category {
+		case "handle":
+			h := &Handle{
+				Protect:  protectMap[t.InnerName],
+				Name:     convertHandleName(t.InnerName),
+				VkName:   t.InnerName,
+				TypeSafe: t.InnerType == "VK_DEFINE_HANDLE",
+			}
+			handleParents[h] = splitList(t.Parent)
+			if t.ObjTypeEnum != "" {
+				if e, ok := enumMap["VkObjectType"]; ok {
+					h.ObjectType = e.Value(t.ObjTypeEnum)
+				}
+				if h.ObjectType == nil {
+					ctx.diags.diagnose("degraded", h.VkName, "unknown object type %s of handle %s", t.ObjTypeEnum, h.VkName)
+				}
+			}
+			ctx.Handles = append(ctx.Handles, h)
+			if h.TypeSafe {
+				ctx.converters[t.InnerName] = &HandleConverter{
+					CppName: h.Name,
+					VkName:  h.VkName,
+				}
+			} else {
+				ctx.converters[t.InnerName] = &NonDispatchableHandleConverter{
+					CppName: h.Name,
+					VkName:  h.VkName,
+				}
+			}
+		case "enum":
+			enum, ok := enumMap[t.Name]
+			if !ok {
+				enum = &Enum{Name: convertEnumName(t.Name), VkName: t.Name}
+			}
+			if enum.used {
+				continue
+			}
+			ctx.Enums = append(ctx.Enums, *enum)
+		case "struct", "union":
+			if t.Name == "VkRect3D" { // TODO: vulkan/vulkan.h contains no such thing
+				continue
+			}
+			name := convertStructName(t.Name)
+			s := Struct{
+				Protect:  protectMap[t.Name],
+				Name:     name,
+				VkName:   t.Name,
+				ReadOnly: t.ReturnedOnly,
+				Union:    t.Category == "union",
+			}
+			s.Aggregate = opts.AggregateStructs && !s.Union
+			for _, m := range t.Members {
+				if m.Name == "sType" {
+					s.HasSType = true
+					if m.Values != "" {
+						s.TypeName = m.Values
+					}
+				}
+				ct, name := parseCType(m.Type, m.Name, m.Extra)
+				for i, d := range ct.Dims {
+					if d == "" {
+						ct.Dims[i] = m.Enum
+					}
+				}
+				at := NewAnalyzedType(name, ct)
+				if at.IsArray && m.Enum != "" {
+					n, ok := constants[m.Enum]
+					if !ok {
+						ctx.diags.diagnose("degraded", t.Name, "unknown array size %s of %s.%s", m.Enum, t.Name, m.Name)
+					}
+					at.Arity = n
+				}
+				sm := StructMember{
+					Name:         name,
+					Type:         ctx.names.typeName(ct, true),
+					VkType:       ctx.names.typeName(ct, false),
+					AnalyzedType: at,
+					Converter:    NopConverter{},
+					Len:          structMemberLen(&m),
+					naming:       ctx.naming,
+					IsVersion:    at.IsBlank && m.Type == "uint32_t" && opts.isVersionMember(name),
+					IsString:     m.Type == "char" && at.IsConst && at.Suffix() == "*",
+				}
+				if at.IsArray {
+					sm.ArraySize = m.Enum
+					if sm.ArraySize == "" {
+						sm.ArraySize = strconv.Itoa(at.Arity)
+					}
+					sm.IsUUID = m.Type == "uint8_t" && isUUIDName(name)
+				}
+				s.Members = append(s.Members, sm)
+			}
+			if e, ok := enumMap["VkStructureType"]; ok && s.TypeName != "" {
+				if v := e.Value(s.TypeName); v != nil {
+					s.SType = v.Name
+				}
+			}
+			ctx.Structs = append(ctx.Structs, s)
+			ctx.converters[t.Name] = &ReinterpretCastConverter{
+				CppName: s.Name,
+				VkName:  s.VkName,
+			}
+		}
+	}
+	for _, h := range ctx.Handles {
+		for _, p := range handleParents[h] {
+			if ph := ctx.Handle(p); ph != nil {
+				h.Parents = append(h.Parents, ph)
+			} else {
+				ctx.diags.diagnose("degraded", h.VkName, "unknown parent %s of handle %s", p, h.VkName)
+			}
+		}
+	}
+	for _, c := range reg.Commands.Command {
+		if c.Alias != "" {
+			continue
+		}
+		ret, _ := parseCType(c.Proto.Type, c.Proto.Name, c.Proto.Extra)
+		cmd := Command{
+			Protect:   protectMap[c.Proto.Name],
+			Name:      ctx.naming.Function(convertCommandName(c.Proto.Name)),
+			VkName:    c.Proto.Name,
+			RetType:   ctx.names.typeName(ret, true),
+			RetVkType: ctx.names.typeName(ret, false),
+		}
+		for _, p := range c.Params {
+			ct, name := parseCType(p.Type, p.Name, p.Extra)
+			cp := CommandParameter{
+				Name:         name,
+				Type:         ctx.names.typeName(ct, true),
+				VkType:       ctx.names.typeName(ct, false),
+				AnalyzedType: NewAnalyzedType(name, ct),
+				Converter:    NopConverter{},
+				Len:          p.Len,
+			}
+			cmd.Parameters = append(cmd.Parameters, cp)
+		}
+		cmd.Throws = opts.Exceptions && cmd.RetType == "Result"
+		cmd.Value = ctx.newValueReturn(&cmd, &c)
+		cmd.Arrays = ctx.newArrayOverload(&cmd, &c)
+		ctx.Commands = append(ctx.Commands, cmd)
+	}
+	ctx.resolveAliases(reg, protectMap)
+	if opts.TypePrefix != "" || opts.TypeSuffix != "" {
+		ctx.AffixedTypes = ctx.affixedTypeAliases(opts.TypePrefix, opts.TypeSuffix)
+	}
+	ctx.sortByName()
+	ctx.resolveMethods()
+	ctx.StructExtensions = ctx.newStructExtensions(reg)
+	ctx.resolveChainQueries()
+	ctx.Exceptions = opts.Exceptions
+	ctx.Module = opts.Module
+	if opts.DynamicDispatch {
+		ctx.Dispatch = ctx.newDispatchCommands(reg)
+	}
+	if opts.UniqueHandles {
+		ctx.UniqueHandles = newUniqueHandles(&ctx)
+	}
+	ctx.DebugName = ctx.newDebugName()
+	ctx.Extensions = newExtensionInfos(reg, opts)
+	ctx.SpirvExtensions = newSpirvEntries(reg.SpirvExtensions.SpirvExtension)
+	ctx.SpirvCapabilities = newSpirvEntries(reg.SpirvCapabilities.SpirvCapability)
+	ctx.Sync = newSync(&reg.Sync)
+	ctx.Sync.Module = opts.Module
+	ctx.sortStructsByDeps()
+	if opts.SafeStructs {
+		ctx.SafeStructs = ctx.newSafeStructs()
+	}
+	ctx.resolveStructMemberConverters()
+	if opts.CppStd >= 20 {
+		ctx.resolveSpanSetters()
+	}
+	ctx.resolveComparisons(reg)
+	ctx.resolveCommandParameterConverters()
+	ctx.resolveSerializers(opts.SerializeStructs)
+	ctx.resolveJSONConverters(opts.JSONStructs)
+	// the interner and the converters are only needed while building
+	ctx.names, ctx.converters = nil, nil
+	return ctx
+}
+
+// bindContext sets what ctx takes from the generation using it, which isn't
+// part of the analysis kept by the cache.
+func (g *generation) bindContext(ctx *Context) {
+	ctx.templates = g.templates
+	ctx.diags = &g.diagnostics
+	ctx.naming, _ = newNamingPolicy(g.opts.Naming)
+	for i := range ctx.Structs {
+		for j := range ctx.Structs[i].Members {
+			ctx.Structs[i].Members[j].naming = ctx.naming
+		}
+	}
+}
+
+// Main runs the command line of the generator, see helpText. It exits the
+// process on failure.
+func Main() {
+	flag.Usage = func() {
+		fmt.Print(helpText[1:])
+		flag.PrintDefaults()
+	}
+	opts := NewOptions()
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	if opts.ConfigFile != "" {
+		specs, err := loadConfig(flag.CommandLine, opts.ConfigFile)
+		if err != nil {
+			fatal(exitUsage, err)
+		}
+		if flag.NArg() == 0 && len(specs) > 0 {
+			// the spec files of the config become the arguments
+			flag.CommandLine.Parse(append([]string{"--"}, specs...))
+		}
+	}
+	nargs := flag.NArg()
+	if opts.Watch {
+		switch {
+		case nargs > 0 && (flag.Arg(0) == "coverage" || flag.Arg(0) == "validate" || flag.Arg(0) == "example"):
+			fatalf(exitUsage, "-watch can't be used with the %s command", flag.Arg(0))
+		case opts.OutputFile == "" && opts.SplitDir == "" && !opts.CheckOnly:
+			fatal(exitUsage, "-watch needs -o, -split or -check, the header isn't written to STDOUT again and again")
+		case opts.DiffFile != "":
+			fatal(exitUsage, "-watch can't be used with -diff")
+		}
+		files := flag.Args()
+		for _, f := range []string{opts.ConfigFile, opts.BannerFile} {
+			if f != "" {
+				files = append(files, f)
+			}
+		}
+		var dirs []string
+		if opts.TemplatesDir != "" {
+			dirs = append(dirs, opts.TemplatesDir)
+		}
+		check(exitUsage, watch(os.Args[1:], files, dirs))
+		return
+	}
+	if nargs == 2 && flag.Arg(0) == "coverage" {
+		f, err := os.Open(flag.Arg(1))
+		check(exitSpec, err)
+		defer f.Close()
+		entries, err := analyzeCoverage(f)
+		check(exitSpec, err)
+		check(exitGenerate, writeCoverageReport(os.Stdout, entries))
+		return
+	}
+	if nargs == 2 && flag.Arg(0) == "validate" {
+		f, err := os.Open(flag.Arg(1))
+		check(exitSpec, err)
+		defer f.Close()
+		errs, err := validateSpec(f)
+		for _, e := range errs {
+			if e.Line > 0 {
+				fmt.Fprintf(os.Stderr, "%s:%s\n", flag.Arg(1), e)
+			} else {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", flag.Arg(1), e)
+			}
+		}
+		check(exitSpec, err)
+		if len(errs) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+	specfiles := flag.Args()
+	var exampleDir string
+	if nargs >= 1 && flag.Arg(0) == "example" {
+		fs := flag.NewFlagSet("example", flag.ExitOnError)
+		fs.StringVar(&exampleDir, "output", "", "Directory to write the example project to")
+		fs.Parse(specfiles[1:])
+		if exampleDir == "" {
+			fatal(exitUsage, "example: -output is required")
+		}
+		if opts.Module {
+			fatal(exitUsage, "example: -module is not supported, the example includes the header")
+		}
+		if opts.Naming != "camel" {
+			fatal(exitUsage, "example: -naming is not supported, the example uses camelCase names")
+		}
+		if opts.Namespace != "vk" {
+			fatal(exitUsage, "example: -namespace is not supported, the example uses the vk namespace")
+		}
+		if opts.CheckOnly {
+			fatal(exitUsage, "example: -check is not supported, the example is written to -output")
+		}
+		check(exitGenerate, os.MkdirAll(exampleDir, 0755))
+		specfiles = fs.Args()
+		opts.OutputFile = filepath.Join(exampleDir, exampleHeader)
+		if opts.CHeaderFile != "" {
+			opts.CHeaderFile = filepath.Join(exampleDir, filepath.Base(opts.CHeaderFile))
+		}
+	}
+	if opts.Lang != "c++" {
+		switch {
+		case opts.Module || opts.SplitDir != "" || exampleDir != "":
+			fatalf(exitUsage, "-lang %s can't be used with -module, -split or example, they make C++ headers", opts.Lang)
+		case opts.CHeaderFile != "" || opts.FwdHeaderFile != "" || opts.ReflectHeaderFile != "":
+			fatalf(exitUsage, "-lang %s can't be used with -c-header, -fwd-header or -reflect-header, they go with the C++ header", opts.Lang)
+		case len(opts.Only) > 0 || len(opts.Skip) > 0:
+			fatalf(exitUsage, "-lang %s can't be used with -only or -skip, they choose sections of the C++ header", opts.Lang)
+		case opts.CompileCheck != "" || opts.Format.on || opts.DiffFile != "":
+			fatalf(exitUsage, "-lang %s can't be used with -compile-check, -format or -diff, they work on C++ headers", opts.Lang)
+		}
+	}
+	if opts.SplitDir != "" {
+		switch {
+		case len(opts.Only) > 0 || len(opts.Skip) > 0:
+			fatal(exitUsage, "-split can't be used with -only or -skip, the parts of the header include each other")
+		case opts.Module:
+			fatal(exitUsage, "-split can't be used with -module, the module is a single file")
+		case opts.OutputFile != "" || exampleDir != "":
+			fatal(exitUsage, "-split writes the header to its directory, it can't be used with -o or example")
+		}
+	}
+	if opts.CompileCheck != "" {
+		switch {
+		case opts.Module:
+			fatal(exitUsage, "-compile-check can't be used with -module, the module isn't included")
+		case opts.DiffFile != "" || exampleDir != "":
+			fatal(exitUsage, "-compile-check can't be used with -diff or example")
+		}
+	}
+	if opts.CheckOnly && opts.DiffFile != "" {
+		fatal(exitUsage, "-check can't be used with -diff, it doesn't compare anything")
+	}
+	if opts.DiffFile != "" {
+		switch {
+		case opts.OutputFile != "" || exampleDir != "" || opts.SplitDir != "":
+			fatal(exitUsage, "-diff compares the header instead of writing it, it can't be used with -o, -split or example")
+		case opts.CHeaderFile != "" || opts.FwdHeaderFile != "" || opts.ReflectHeaderFile != "" || opts.MockFile != "" || opts.MarkdownFile != "":
+			fatal(exitUsage, "-diff compares the C++ header only, it can't be used with -c-header, -fwd-header, -reflect-header, -mock or -markdown")
+		}
+	}
+	if opts.Module && opts.FwdHeaderFile != "" {
+		fatal(exitUsage, "-fwd-header can't be used with -module, types of a module can't be declared outside of it")
+	}
+	g, err := newGeneration(opts)
+	if err != nil {
+		fatal(exitCode(err, exitGenerate), err)
+	}
+	url := opts.specURL()
+	if url == "" && len(specfiles) < 1 {
+		flag.Usage()
+		os.Exit(exitUsage)
+	}
+
+	if url != "" {
+		specfile, err := fetchSpec(url, opts.SpecCache)
+		check(exitSpec, err)
+		specfiles = append([]string{specfile}, specfiles...)
+	}
+	specfile := specfiles[0]
+	reg, err := registry.ReadFile(specfile)
+	check(exitSpec, err)
+	// the entities of other APIs go before layering the registries, those of
+	// an overlay would replace them instead of the Vulkan ones otherwise
+	selectAPI(reg)
+	for _, name := range specfiles[1:] {
+		other, err := registry.ReadFile(name)
+		check(exitSpec, err)
+		selectAPI(other)
+		if isCompanion(other) {
+			mergeRegistry(reg, other)
+			continue
+		}
+		for _, r := range overlayRegistry(reg, other) {
+			log.Printf("%s: replaces %s", name, r)
+		}
+	}
+	ctx, err := g.prepare(reg)
+	if err != nil {
+		if errs, ok := err.(ValidationErrors); ok {
+			for _, err := range errs {
+				log.Print(err)
+			}
+		}
+		fatalf(exitCode(err, exitSpec), "%s: %v", specfile, err)
+	}
+	params := ctx.Params
+	if opts.CHeaderFile != "" {
+		params.Includes = append(params.Includes, includeSpec(filepath.Base(opts.CHeaderFile)))
+	}
+	if opts.IRFile != "" {
+		check(exitGenerate, writeIR(opts.IRFile, ctx))
+	}
+	if opts.GraphFile != "" {
+		check(exitGenerate, writeGraph(opts.GraphFile, reg, ctx))
+	}
+	if opts.ReportFile != "" {
+		check(exitGenerate, g.diagnostics.writeReport(opts.ReportFile))
+	}
+	if n := g.diagnostics.strictFailures(); opts.Strict && n > 0 {
+		fatalf(exitStrict, "-strict: %d registry entities were skipped or generated in part", n)
+	}
+	var backends []backend
+	if opts.SplitDir != "" {
+		if !opts.CheckOnly {
+			check(exitGenerate, os.MkdirAll(opts.SplitDir, 0755))
+		}
+		backends = splitBackends(opts.SplitDir, params, ctx)
+	} else {
+		backends = append(backends, languageBackend(languageBackends[opts.Lang], opts.OutputFile, ctx, opts))
+	}
+	if opts.CHeaderFile != "" {
+		cheader := CHeader{
+			VulkanHeader: params.VulkanHeader,
+			Banner:       params.Banner,
+			Defines:      params.Defines,
+			Constants:    ctx.Constants,
+			Commands:     ctx.Commands,
+		}
+		cheader.GuardBegin, cheader.GuardEnd = includeGuard(guardName(opts.IncludeGuard, "C"), "/*")
+		backends = append(backends, backend{
+			name: "C header",
+			file: opts.CHeaderFile,
+			lang: "c",
+			emit: func(w io.Writer) error {
+				return ctx.execute(w, "cheader", &cheader)
+			},
+		})
+	}
+	if opts.MarkdownFile != "" {
+		doc := newMarkdownDoc(params, ctx)
+		backends = append(backends, backend{
+			name: "Markdown reference",
+			file: opts.MarkdownFile,
+			lang: "markdown",
+			emit: func(w io.Writer) error {
+				return ctx.execute(w, "markdown", doc)
+			},
+		})
+	}
+	if opts.FwdHeaderFile != "" {
+		fwd := newForwardHeader(params, ctx)
+		backends = append(backends, backend{
+			name: "forward declaration header",
+			file: opts.FwdHeaderFile,
+			lang: "c++",
+			emit: func(w io.Writer) error {
+				return ctx.execute(w, "fwdheader", fwd)
+			},
+		})
+	}
+	if opts.ReflectHeaderFile != "" {
+		backends = append(backends, reflectBackend(opts.ReflectHeaderFile, params, ctx))
+	}
+	if opts.MockFile != "" {
+		backends = append(backends, mockBackend(opts.MockFile, params, ctx))
+	}
+	if exampleDir != "" {
+		example := &Example{
+			Banner:        params.Banner,
+			Header:        exampleHeader,
+			UniqueHandles: opts.UniqueHandles,
+			Aggregates:    opts.AggregateStructs,
+			CppStd:        opts.CppStd,
+		}
+		if example.Aggregates && example.CppStd < 20 {
+			example.CppStd = 20
+		}
+		backends = append(backends, exampleBackends(exampleDir, example, ctx)...)
+	}
+	if opts.Format.on {
+		formatBackends(backends, opts)
+	}
+	if opts.DiffFile != "" {
+		// the C++ header is the only backend
+		same, err := diffHeader(os.Stdout, opts.DiffFile, &backends[0])
+		check(exitGenerate, err)
+		if !same {
+			os.Exit(1)
+		}
+		return
+	}
+	check(exitGenerate, runBackends(backends, opts.CheckOnly))
+	if opts.CompileCheck != "" {
+		header := "vk.hpp"
+		switch {
+		case opts.SplitDir != "":
+			// the last part includes the others
+			header = splitParts[len(splitParts)-1].file
+		case opts.OutputFile != "":
+			header = filepath.Base(opts.OutputFile)
+		}
+		check(exitGenerate, compileCheck(opts.CompileCheck, backends, header, opts.CppStd))
+	}
+	if opts.CheckOnly {
+		log.Printf("%s: %d outputs generated, %d registry entities skipped or generated in part", specfile, len(backends), g.diagnostics.strictFailures())
+		return
+	}
+	if g.cache != nil {
+		log.Print(g.cache)
+	}
+}
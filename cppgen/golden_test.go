@@ -0,0 +1,221 @@
+package cppgen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// generateTestHeader generates the header of spec with the options set up
+// by setup, as the command line does without -skip-broken.
+func generateTestHeader(t *testing.T, spec string, setup func(*Options)) []byte {
+	t.Helper()
+	reg, err := registry.ReadFile(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := NewOptions()
+	if setup != nil {
+		setup(opts)
+	}
+	return generateHeader(t, reg, opts)
+}
+
+// generateHeader returns the header of reg generated with opts.
+func generateHeader(t *testing.T, reg *registry.Registry, opts *Options) []byte {
+	t.Helper()
+	discardLog(t)
+	var buf bytes.Buffer
+	if err := Generate(&buf, reg, *opts); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// compileHeaders writes the headers of files to a directory and compiles a
+// source including them in order against testdata/include, the vulkan.h of
+// testdata/vk.xml, with the C++ compiler of $CXX or else g++. The test is
+// skipped without a compiler.
+func compileHeaders(t *testing.T, files []testFile, std string, flags ...string) {
+	t.Helper()
+	cxx := os.Getenv("CXX")
+	if cxx == "" {
+		cxx = "g++"
+	}
+	if _, err := exec.LookPath(cxx); err != nil {
+		t.Skipf("no C++ compiler: %v", err)
+	}
+	include, err := filepath.Abs("testdata/include")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	var src bytes.Buffer
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f.name), f.text, 0644); err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&src, "#include \"%s\"\n", f.name)
+	}
+	src.WriteString("int main() { return 0; }\n")
+	tu := filepath.Join(dir, "tu.cpp")
+	if err := os.WriteFile(tu, src.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	args := append([]string{"-std=" + std, "-fsyntax-only", "-Wall", "-Werror", "-I", include}, flags...)
+	out, err := exec.Command(cxx, append(args, tu)...).CombinedOutput()
+	if err != nil {
+		t.Errorf("%s %v: %v\n%s", cxx, args, err, out)
+	}
+}
+
+type testFile struct {
+	name string
+	text []byte
+}
+
+// TestGeneratedHeaderCompiles compiles the headers generated from the test
+// specs with several options.
+func TestGeneratedHeaderCompiles(t *testing.T) {
+	force32, err := filepath.Abs("testdata/force32.h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		name  string
+		spec  string
+		setup func(*Options)
+		std   string
+		flags []string
+	}{
+		{name: "default", spec: testSpec, std: "c++17"},
+		// non-dispatchable handles are uint64_t on 32-bit targets
+		{name: "32-bit", spec: testSpec, std: "c++17", flags: []string{"-include", force32}},
+		{name: "unique-handles", spec: testSpec, std: "c++17", setup: func(o *Options) {
+			o.UniqueHandles = true
+		}},
+		{name: "exceptions", spec: testSpec, std: "c++17", setup: func(o *Options) {
+			o.Exceptions = true
+		}},
+		{name: "aggregates", spec: testSpec, std: "c++20", setup: func(o *Options) {
+			o.CppStd = 20
+			o.AggregateStructs = true
+		}},
+		{name: "dynamic-dispatch", spec: testSpec, std: "c++17", setup: func(o *Options) {
+			o.DynamicDispatch = true
+		}},
+		{name: "snake", spec: testSpec, std: "c++17", setup: func(o *Options) {
+			o.Naming = "snake"
+		}},
+		{name: "snake-all", spec: testSpec, std: "c++17", setup: func(o *Options) {
+			o.Naming = "snake"
+			o.Exceptions = true
+			o.UniqueHandles = true
+			o.DynamicDispatch = true
+		}},
+		{name: "c++11", spec: testSpec, std: "c++11", setup: func(o *Options) {
+			o.CppStd = 11
+		}},
+		{name: "c++14", spec: testSpec, std: "c++14", setup: func(o *Options) {
+			o.CppStd = 14
+		}},
+		{name: "c++20", spec: testSpec, std: "c++20", setup: func(o *Options) {
+			o.CppStd = 20
+		}},
+		{name: "serialize", spec: testSpec, std: "c++17", setup: func(o *Options) {
+			o.SerializeStructs = listFlag{"VkMemoryBarrier2", "VkExtent3D"}
+		}},
+		{name: "legacy", spec: "testdata/vk_legacy.xml", std: "c++17"},
+		{name: "legacy-c++20", spec: "testdata/vk_legacy.xml", std: "c++20", setup: func(o *Options) {
+			o.CppStd = 20
+		}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			header := generateTestHeader(t, c.spec, c.setup)
+			compileHeaders(t, []testFile{{"vk.hpp", header}}, c.std, c.flags...)
+		})
+	}
+}
+
+// TestBaseStructuresCompile compiles code using VkBaseInStructure and
+// VkBaseOutStructure, whose pNext is spelled with an elaborated type
+// specifier.
+func TestBaseStructuresCompile(t *testing.T) {
+	header := generateTestHeader(t, testSpec, nil)
+	for _, name := range []string{"BaseInStructure", "BaseOutStructure"} {
+		if !bytes.Contains(header, []byte("class "+name+" {")) {
+			t.Errorf("the header doesn't define %s", name)
+		}
+	}
+	use := []byte(`inline const VkBaseInStructure *next(const vk::BaseInStructure &s) { return s.c_ptr()->pNext; }
+inline vk::BaseOutStructure *next(vk::BaseOutStructure &s) { return const_cast<vk::BaseOutStructure *>(s.pNext()); }
+`)
+	compileHeaders(t, []testFile{{"vk.hpp", header}, {"use.hpp", use}}, "c++17")
+}
+
+// TestTypesafeHandles checks that the handles are typesafe where the
+// non-dispatchable handles of vulkan.h are distinct types.
+func TestTypesafeHandles(t *testing.T) {
+	force32, err := filepath.Abs("testdata/force32.h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := generateTestHeader(t, testSpec, nil)
+	use := []byte(`static_assert(VK_TYPESAFE_HANDLES == VK_USE_64_BIT_PTR_DEFINES, "VK_TYPESAFE_HANDLES doesn't follow VK_USE_64_BIT_PTR_DEFINES");
+`)
+	for _, flags := range [][]string{
+		nil,
+		{"-include", force32},
+		{"-DVK_USE_64_BIT_PTR_DEFINES=0"},
+	} {
+		compileHeaders(t, []testFile{{"vk.hpp", header}, {"use.hpp", use}}, "c++17", flags...)
+	}
+}
+
+// TestSpirvTablesWithoutEnablesCompile compiles the SPIR-V tables of
+// entries without any enable, which have no table of enables.
+func TestSpirvTablesWithoutEnablesCompile(t *testing.T) {
+	reg, err := registry.ReadFile(testSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range reg.SpirvExtensions.SpirvExtension {
+		reg.SpirvExtensions.SpirvExtension[i].Enables = nil
+	}
+	header := generateHeader(t, reg, NewOptions())
+	if !bytes.Contains(header, []byte("spirvExtensions[]")) || bytes.Contains(header, []byte("spirvExtensionEnables[]")) {
+		t.Error("the SPIR-V extensions are generated with a table of enables")
+	}
+	compileHeaders(t, []testFile{{"vk.hpp", header}}, "c++17", "-pedantic")
+}
+
+// TestReflectHeaderCompiles compiles the header of -reflect-header included
+// after the C++ header, whose vk::reflect it must not clash with.
+func TestReflectHeaderCompiles(t *testing.T) {
+	g, reg := readTestRegistry(t, NewOptions())
+	params, err := newHeaderParams(reg, g.opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := g.newContext(reg)
+	params.Handles = ctx.Handles
+	ctx.Params = &params
+
+	var files []testFile
+	for _, b := range []backend{
+		languageBackend(cppBackend{}, "vk.hpp", &ctx, g.opts),
+		reflectBackend("vk_reflect.hpp", &params, &ctx),
+	} {
+		var buf bytes.Buffer
+		if err := b.emit(&buf); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, testFile{b.file, buf.Bytes()})
+	}
+	compileHeaders(t, files, "c++17")
+}
@@ -0,0 +1,195 @@
+package cppgen
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// graphShapes are the node shapes of the kinds of entities.
+var graphShapes = map[string]string{
+	"handle":  "box",
+	"struct":  "ellipse",
+	"enum":    "hexagon",
+	"bitmask": "hexagon",
+	"command": "component",
+}
+
+// graphNode is an entity of the graph, Group is the version or extension
+// it's drawn in.
+type graphNode struct {
+	Name  string
+	Kind  string
+	Label string
+	Group string
+	Style string
+}
+
+// graphEdge is a reference of From to To, drawn with Style and Label if
+// they are set.
+type graphEdge struct {
+	From, To     string
+	Style, Label string
+}
+
+// writeGraph writes the dependency graph of the entities of ctx to file in
+// the DOT language: structs point to the types of their members, commands
+// to those of their parameters, handles to their parents and structs
+// extending others through pNext to those. Structs are labeled with their
+// position in the header, which declares them after what they depend on.
+// Entities are grouped by the first version or extension of registry
+// requiring them.
+func writeGraph(file string, reg *registry.Registry, ctx *Context) error {
+	var nodes []graphNode
+	known := map[string]bool{}
+	node := func(n graphNode) {
+		if n.Label == "" {
+			n.Label = n.Name
+		}
+		known[n.Name] = true
+		nodes = append(nodes, n)
+	}
+	for _, h := range ctx.Handles {
+		node(graphNode{Name: h.VkName, Kind: "handle"})
+	}
+	for _, e := range ctx.Enums {
+		node(graphNode{Name: e.VkName, Kind: "enum"})
+	}
+	for _, bm := range ctx.BitMasks {
+		node(graphNode{Name: bm.VkName, Kind: "bitmask"})
+	}
+	for i, s := range ctx.Structs {
+		n := graphNode{Name: s.VkName, Kind: "struct", Label: fmt.Sprintf("%s\\n#%d", s.VkName, i+1)}
+		if s.Union {
+			n.Style = "dashed"
+		}
+		node(n)
+	}
+	for _, c := range ctx.Commands {
+		node(graphNode{Name: c.VkName, Kind: "command"})
+	}
+
+	var edges []graphEdge
+	seen := map[graphEdge]bool{}
+	edge := func(e graphEdge) {
+		if known[e.From] && known[e.To] && e.From != e.To && !seen[e] {
+			seen[e] = true
+			edges = append(edges, e)
+		}
+	}
+	for _, h := range ctx.Handles {
+		for _, p := range h.Parents {
+			edge(graphEdge{From: h.VkName, To: p.VkName, Style: "dashed", Label: "parent"})
+		}
+	}
+	for _, bm := range ctx.BitMasks {
+		if bm.Enum != nil {
+			edge(graphEdge{From: bm.VkName, To: bm.Enum.VkName})
+		}
+	}
+	vkNames := map[string]string{}
+	for _, s := range ctx.Structs {
+		vkNames[s.Name] = s.VkName
+		for _, m := range s.Members {
+			edge(graphEdge{From: s.VkName, To: m.AnalyzedType.Type})
+		}
+	}
+	for _, se := range ctx.StructExtensions {
+		edge(graphEdge{From: vkNames[se.Name], To: vkNames[se.Base], Style: "dotted", Label: "extends"})
+	}
+	for _, c := range ctx.Commands {
+		edge(graphEdge{From: c.VkName, To: c.RetVkType})
+		for _, p := range c.Parameters {
+			edge(graphEdge{From: c.VkName, To: p.AnalyzedType.Type})
+		}
+	}
+
+	// the versions come first, an entity goes with the first requiring it
+	group := map[string]string{}
+	var groups []string
+	require := func(name string, r *registry.Require) {
+		groups = append(groups, name)
+		for _, t := range r.Types {
+			if _, ok := group[t.Name]; !ok {
+				group[t.Name] = name
+			}
+		}
+		for _, c := range r.Commands {
+			if _, ok := group[c.Name]; !ok {
+				group[c.Name] = name
+			}
+		}
+	}
+	for i := range reg.Features {
+		require(reg.Features[i].Name, &reg.Features[i].Require)
+	}
+	exts := append([]registry.Extension(nil), reg.Extensions.Extension...)
+	sort.SliceStable(exts, func(i, j int) bool { return exts[i].Number < exts[j].Number })
+	for i := range exts {
+		require(exts[i].Name, &exts[i].Require)
+	}
+	for i := range nodes {
+		nodes[i].Group = group[nodes[i].Name]
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, "// Dependency graph of the generated Vulkan entities: handles are boxes,")
+	fmt.Fprintln(w, "// structs ellipses (unions dashed, #N their position in the header), enums")
+	fmt.Fprintln(w, "// and bitmasks hexagons and commands components. Edges point from an")
+	fmt.Fprintln(w, "// entity to what it references.")
+	fmt.Fprintln(w, "digraph vulkan {")
+	fmt.Fprintln(w, "\trankdir=LR;")
+	fmt.Fprintln(w, "\tnode [fontname=\"Helvetica\", fontsize=10];")
+	writeNode := func(indent string, n graphNode) {
+		fmt.Fprintf(w, "%s%q [shape=%s, label=\"%s\"", indent, n.Name, graphShapes[n.Kind], n.Label)
+		if n.Style != "" {
+			fmt.Fprintf(w, ", style=%s", n.Style)
+		}
+		fmt.Fprintln(w, "];")
+	}
+	for i, g := range append(groups, "") {
+		var members []graphNode
+		for _, n := range nodes {
+			if n.Group == g {
+				members = append(members, n)
+			}
+		}
+		switch {
+		case len(members) == 0:
+		case g == "":
+			// declared by no version or extension, base types and the like
+			for _, n := range members {
+				writeNode("\t", n)
+			}
+		default:
+			fmt.Fprintf(w, "\tsubgraph cluster_%d {\n\t\tlabel=%q;\n", i, g)
+			for _, n := range members {
+				writeNode("\t\t", n)
+			}
+			fmt.Fprintln(w, "\t}")
+		}
+	}
+	for _, e := range edges {
+		fmt.Fprintf(w, "\t%q -> %q", e.From, e.To)
+		switch {
+		case e.Style != "" && e.Label != "":
+			fmt.Fprintf(w, " [style=%s, label=%q]", e.Style, e.Label)
+		case e.Style != "":
+			fmt.Fprintf(w, " [style=%s]", e.Style)
+		}
+		fmt.Fprintln(w, ";")
+	}
+	fmt.Fprintln(w, "}")
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,246 @@
+package cppgen
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+// IR is the JSON form of the Context written by -dump-ir, for tools which
+// want the registry as the generator sees it: filtered by the options,
+// aliases resolved, enum values computed and types analyzed. Entities are
+// named by their C names (VkName) and their C++ names (Name). Guard is the
+// #if line of the platform or provisional extension guarding the entity,
+// if any.
+type IR struct {
+	Constants      []IRConstant  `json:"constants"`
+	Handles        []IRHandle    `json:"handles"`
+	Enums          []IREnum      `json:"enums"`
+	BitMasks       []IRBitMask   `json:"bitmasks"`
+	Structs        []IRStruct    `json:"structs"`
+	Commands       []IRCommand   `json:"commands"`
+	TypeAliases    []IRAlias     `json:"type_aliases"`
+	CommandAliases []IRAlias     `json:"command_aliases"`
+	Extensions     []IRExtension `json:"extensions"`
+}
+
+// IRConstant is an API constant, Value is its C expression.
+type IRConstant struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type IRExtension struct {
+	Name    string `json:"name"`
+	Number  int    `json:"number"`
+	Depends string `json:"depends,omitempty"`
+}
+
+type IRHandle struct {
+	Name         string   `json:"name"`
+	VkName       string   `json:"vk_name"`
+	Guard        string   `json:"guard,omitempty"`
+	Dispatchable bool     `json:"dispatchable"`
+	Parents      []string `json:"parents,omitempty"`
+	ObjectType   string   `json:"object_type,omitempty"`
+}
+
+type IREnum struct {
+	Name    string        `json:"name"`
+	VkName  string        `json:"vk_name"`
+	Guard   string        `json:"guard,omitempty"`
+	Values  []IREnumValue `json:"values"`
+	Aliases []IRAlias     `json:"aliases,omitempty"`
+}
+
+type IREnumValue struct {
+	Name   string `json:"name"`
+	VkName string `json:"vk_name"`
+	Guard  string `json:"guard,omitempty"`
+	// nil if the value can't be computed
+	Value *int64 `json:"value"`
+}
+
+type IRBitMask struct {
+	Name   string `json:"name"`
+	VkName string `json:"vk_name"`
+	Guard  string `json:"guard,omitempty"`
+	// the enum of the bits, "" if there are none
+	Bits string `json:"bits,omitempty"`
+	// 32 or 64
+	BitWidth int `json:"bit_width"`
+}
+
+type IRStruct struct {
+	Name     string `json:"name"`
+	VkName   string `json:"vk_name"`
+	Guard    string `json:"guard,omitempty"`
+	Union    bool   `json:"union,omitempty"`
+	ReadOnly bool   `json:"returned_only,omitempty"`
+	// the VkStructureType value of sType
+	SType   string     `json:"stype,omitempty"`
+	Extends []string   `json:"extends,omitempty"`
+	Members []IRMember `json:"members"`
+}
+
+// IRMember is a struct member or command parameter. Type and VkType are
+// the full C++ and C types, BaseType the C type without qualifiers,
+// pointers or array sizes. Pointers is the number of levels of indirection,
+// Dims the array sizes outermost first and BitWidth the width of
+// bit-fields.
+type IRMember struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	VkType    string   `json:"vk_type"`
+	BaseType  string   `json:"base_type"`
+	Const     bool     `json:"const,omitempty"`
+	Pointer   bool     `json:"pointer,omitempty"`
+	Pointers  int      `json:"pointers,omitempty"`
+	Dims      []string `json:"dims,omitempty"`
+	BitWidth  int      `json:"bit_width,omitempty"`
+	ArraySize string   `json:"array_size,omitempty"`
+	Len       string   `json:"len,omitempty"`
+}
+
+type IRCommand struct {
+	Name       string     `json:"name"`
+	VkName     string     `json:"vk_name"`
+	Guard      string     `json:"guard,omitempty"`
+	Return     string     `json:"return"`
+	VkReturn   string     `json:"vk_return"`
+	Parameters []IRMember `json:"parameters"`
+}
+
+type IRAlias struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+	Guard  string `json:"guard,omitempty"`
+}
+
+func newIR(ctx *Context) *IR {
+	ir := &IR{}
+	for _, c := range ctx.Constants {
+		ir.Constants = append(ir.Constants, IRConstant{Name: c.Name, Value: c.Value})
+	}
+	for _, e := range ctx.Extensions {
+		ir.Extensions = append(ir.Extensions, IRExtension{Name: e.Name, Number: e.Number, Depends: e.Depends})
+	}
+	for _, h := range ctx.Handles {
+		ih := IRHandle{
+			Name:         h.Name,
+			VkName:       h.VkName,
+			Guard:        h.Protect.Begin,
+			Dispatchable: h.TypeSafe,
+		}
+		for _, p := range h.Parents {
+			ih.Parents = append(ih.Parents, p.VkName)
+		}
+		if h.ObjectType != nil {
+			ih.ObjectType = h.ObjectType.VkName
+		}
+		ir.Handles = append(ir.Handles, ih)
+	}
+	for _, e := range ctx.Enums {
+		ie := IREnum{Name: e.Name, VkName: e.VkName, Guard: e.Protect.Begin, Values: []IREnumValue{}}
+		for _, v := range e.Values {
+			iv := IREnumValue{Name: v.Name, VkName: v.VkName, Guard: v.Protect.Begin}
+			if v.HasNumber {
+				n := v.Number
+				iv.Value = &n
+			}
+			ie.Values = append(ie.Values, iv)
+		}
+		ie.Aliases = irAliases(e.Aliases)
+		ir.Enums = append(ir.Enums, ie)
+	}
+	for _, bm := range ctx.BitMasks {
+		ib := IRBitMask{Name: bm.Name, VkName: bm.VkName, Guard: bm.Protect.Begin, BitWidth: bm.BitWidth}
+		if bm.Enum != nil {
+			ib.Bits = bm.Enum.VkName
+		}
+		ir.BitMasks = append(ir.BitMasks, ib)
+	}
+	vkNames := map[string]string{}
+	for _, s := range ctx.Structs {
+		vkNames[s.Name] = s.VkName
+	}
+	sTypes := map[string]string{}
+	for _, e := range ctx.Enums {
+		if e.VkName == "VkStructureType" {
+			for _, v := range e.Values {
+				sTypes[v.Name] = v.VkName
+			}
+		}
+	}
+	extends := map[string][]string{}
+	for _, se := range ctx.StructExtensions {
+		extends[se.Name] = append(extends[se.Name], vkNames[se.Base])
+	}
+	for _, s := range ctx.Structs {
+		is := IRStruct{
+			Name:     s.Name,
+			VkName:   s.VkName,
+			Guard:    s.Protect.Begin,
+			Union:    s.Union,
+			ReadOnly: s.ReadOnly,
+			SType:    sTypes[s.SType],
+			Extends:  extends[s.Name],
+			Members:  []IRMember{},
+		}
+		for _, m := range s.Members {
+			im := irMember(m.Name, m.Type, m.VkType, m.AnalyzedType)
+			im.ArraySize = m.ArraySize
+			im.Len = m.Len
+			is.Members = append(is.Members, im)
+		}
+		ir.Structs = append(ir.Structs, is)
+	}
+	for _, c := range ctx.Commands {
+		ic := IRCommand{
+			Name:       c.Name,
+			VkName:     c.VkName,
+			Guard:      c.Protect.Begin,
+			Return:     c.RetType,
+			VkReturn:   c.RetVkType,
+			Parameters: []IRMember{},
+		}
+		for _, p := range c.Parameters {
+			ic.Parameters = append(ic.Parameters, irMember(p.Name, p.Type, p.VkType, p.AnalyzedType))
+		}
+		ir.Commands = append(ir.Commands, ic)
+	}
+	ir.TypeAliases = irAliases(ctx.TypeAliases)
+	ir.CommandAliases = irAliases(ctx.CommandAliases)
+	return ir
+}
+
+func irMember(name, typ, vkType string, at AnalyzedType) IRMember {
+	return IRMember{
+		Name:     name,
+		Type:     strings.TrimSpace(typ),
+		VkType:   strings.TrimSpace(vkType),
+		BaseType: at.Type,
+		Const:    at.IsConst,
+		Pointer:  at.IsPointer && !at.IsArray,
+		Pointers: len(at.Pointers),
+		Dims:     at.Dims,
+		BitWidth: at.BitWidth,
+	}
+}
+
+func irAliases(aliases []Alias) []IRAlias {
+	var out []IRAlias
+	for _, a := range aliases {
+		out = append(out, IRAlias{Name: a.Name, Target: a.Target, Guard: a.Protect.Begin})
+	}
+	return out
+}
+
+// writeIR writes the IR of ctx to file.
+func writeIR(file string, ctx *Context) error {
+	data, err := json.MarshalIndent(newIR(ctx), "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0644)
+}
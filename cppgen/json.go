@@ -0,0 +1,196 @@
+package cppgen
+
+import "fmt"
+
+// JSONMember describes how a struct member is converted to and from JSON.
+// Kind is one of number, enum, flags, handle, struct or chars (a char array
+// written as a string), Type is the C++ type of enums, flags and structs.
+// Size is set for arrays of the other kinds.
+type JSONMember struct {
+	Name   string
+	VkType string
+	Kind   string
+	Type   string
+	Size   string
+}
+
+// write returns the statement of to_json setting the Json dst to the C value
+// src of an element of the member.
+func (m *JSONMember) write(dst, src string) string {
+	switch m.Kind {
+	case "enum":
+		return fmt.Sprintf("%s = enumToJson<Json>(static_cast<%s>(%s));", dst, m.Type, src)
+	case "flags":
+		return fmt.Sprintf("%s = flagsToJson<Json>(%s(%s));", dst, m.Type, src)
+	case "handle":
+		return fmt.Sprintf("%s = reinterpret_cast<uint64_t>(%s);", dst, src)
+	case "struct":
+		return fmt.Sprintf("to_json(%s, reinterpret_cast<const %s &>(%s));", dst, m.Type, src)
+	case "chars":
+		return fmt.Sprintf("%s = charsToJson<Json>(%s);", dst, src)
+	}
+	return fmt.Sprintf("%s = %s;", dst, src)
+}
+
+// read returns the statements of from_json setting the C value dst of an
+// element of the member from the Json src.
+func (m *JSONMember) read(dst, src string) []string {
+	switch m.Kind {
+	case "enum", "flags":
+		fn := "enumFromJson"
+		if m.Kind == "flags" {
+			fn = "flagsFromJson"
+		}
+		return []string{
+			fmt.Sprintf("%s e = static_cast<%s>(%s);", m.Type, m.Type, dst),
+			fmt.Sprintf("%s(%s, e);", fn, src),
+			fmt.Sprintf("%s = static_cast<%s>(e);", dst, m.VkType),
+		}
+	case "handle":
+		return []string{fmt.Sprintf("%s = reinterpret_cast<%s>(%s.template get<uint64_t>());", dst, m.VkType, src)}
+	case "struct":
+		return []string{fmt.Sprintf("from_json(%s, reinterpret_cast<%s &>(%s));", src, m.Type, dst)}
+	case "chars":
+		return []string{fmt.Sprintf("charsFromJson(%s, %s);", src, dst)}
+	}
+	return []string{fmt.Sprintf("%s = %s.template get<%s>();", dst, src, m.VkType)}
+}
+
+// ToJSON returns the statements of to_json writing the member of the C
+// struct c to the Json object j.
+func (m *JSONMember) ToJSON() []string {
+	key := fmt.Sprintf("j[%q]", m.Name)
+	if m.Size == "" {
+		return []string{m.write(key, "c."+m.Name)}
+	}
+	return []string{
+		key + " = Json::array();",
+		fmt.Sprintf("for (size_t i = 0; i < %s; i++) {", m.Size),
+		"\tJson e;",
+		"\t" + m.write("e", "c."+m.Name+"[i]"),
+		"\t" + key + ".push_back(e);",
+		"}",
+	}
+}
+
+// FromJSON returns the statements of from_json reading the member of the C
+// struct c from v, the Json of the member.
+func (m *JSONMember) FromJSON() []string {
+	if m.Size == "" {
+		return m.read("c."+m.Name, "(*v)")
+	}
+	out := []string{fmt.Sprintf("for (size_t i = 0; i < %s && i < v->size(); i++) {", m.Size)}
+	for _, s := range m.read("c."+m.Name+"[i]", "(*v)[i]") {
+		out = append(out, "\t"+s)
+	}
+	return append(out, "}")
+}
+
+type JSONStruct struct {
+	Protect  Protect
+	Name     string
+	VkName   string
+	HasPNext bool
+	Members  []JSONMember
+}
+
+// jsonNumberTypes are the C types written as JSON numbers.
+var jsonNumberTypes = map[string]bool{
+	"size_t": true,
+	"int":    true,
+}
+
+// resolveJSONConverters figures out which of the requested structs (and
+// structs they contain) get to_json and from_json, those with fixed-size
+// data and handles. sType and pNext aren't converted, other pointers and
+// unions make the struct unconvertible.
+func (ctx *Context) resolveJSONConverters(names []string) {
+	structs := map[string]*Struct{}
+	for i, s := range ctx.Structs {
+		structs[s.VkName] = &ctx.Structs[i]
+	}
+	// the enums whose values can be looked up by name, the others are
+	// written as numbers
+	named := map[string]bool{}
+	for _, e := range ctx.Enums {
+		named[e.VkName] = e.HasUnguardedValue()
+	}
+	for _, bm := range ctx.BitMasks {
+		named[bm.VkName] = bm.Enum.HasUnguardedValue()
+	}
+
+	// nil value means the struct can't be converted
+	resolved := map[string]*JSONStruct{}
+	var resolve func(name string) *JSONStruct
+	resolve = func(name string) *JSONStruct {
+		if js, ok := resolved[name]; ok {
+			return js
+		}
+		resolved[name] = nil
+		s, ok := structs[name]
+		if !ok || s.Union {
+			return nil
+		}
+		js := &JSONStruct{Protect: s.Protect, Name: s.Name, VkName: s.VkName}
+		for _, m := range s.Members {
+			at := &m.AnalyzedType
+			switch {
+			case m.Name == "pNext":
+				js.HasPNext = true
+				continue
+			case m.Name == "sType" && s.HasSType:
+				continue
+			case at.IsPointer && !at.IsArray || len(at.Dims) > 1:
+				return nil
+			}
+			jm := JSONMember{Name: m.Name, VkType: at.Type, Kind: "number"}
+			if at.IsArray {
+				jm.Size = m.ArraySize
+			}
+			_, scalar := serialScalarOps[at.Type]
+			switch conv := ctx.converters[at.Type].(type) {
+			case *StaticCastConverter:
+				if named[at.Type] {
+					jm.Kind, jm.Type = "enum", conv.CppName
+				}
+			case *BitMaskConverter:
+				if named[at.Type] {
+					jm.Kind, jm.Type = "flags", conv.CppName
+				}
+			case *HandleConverter, *NonDispatchableHandleConverter:
+				jm.Kind = "handle"
+			case *ReinterpretCastConverter:
+				if resolve(at.Type) == nil {
+					return nil
+				}
+				jm.Kind, jm.Type = "struct", conv.CppName
+			default:
+				switch {
+				case at.Type == "char" && at.IsArray:
+					jm.Kind, jm.Size = "chars", ""
+				case !scalar && !jsonNumberTypes[at.Type]:
+					return nil
+				}
+			}
+			js.Members = append(js.Members, jm)
+		}
+		resolved[name] = js
+		return js
+	}
+
+	for _, name := range names {
+		if resolve(name) == nil {
+			if _, ok := structs[name]; ok {
+				ctx.diags.diagnose("skipped", name, "struct %s contains pointers, unions or unsupported types, no JSON conversion", name)
+			}
+		}
+	}
+
+	// keep the dependency order of structs, nested ones come first
+	ctx.JSONStructs = nil
+	for _, s := range ctx.Structs {
+		if js := resolved[s.VkName]; js != nil {
+			ctx.JSONStructs = append(ctx.JSONStructs, *js)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package cppgen
+
+import (
+	"io"
+	"sort"
+	"strings"
+)
+
+// languages lists the values of -lang, c++ first.
+func languages() []string {
+	list := []string{"c++"}
+	for lang := range languageBackends {
+		if lang != "c++" {
+			list = append(list, lang)
+		}
+	}
+	sort.Strings(list[1:])
+	return list
+}
+
+// CWrapper is the C header of -lang c: string conversions of the enums,
+// initializers of the structs setting sType and the loader table of the C
+// header, for C code wanting the helpers of the C++ header.
+type CWrapper struct {
+	GuardBegin   string
+	GuardEnd     string
+	VulkanHeader string
+
+	Banner    []string
+	Defines   []Define
+	Constants []Constant
+	Enums     []CEnum
+	Structs   []CStruct
+	Commands  []Command
+}
+
+// CEnum is an enum of the C header, Name is its C name without the Vk
+// prefix.
+type CEnum struct {
+	Protect Protect
+	Name    string
+	VkName  string
+	Values  []EnumValue
+}
+
+// CStruct is a struct with an sType of the C header, SType is the C name of
+// its VkStructureType value.
+type CStruct struct {
+	Protect Protect
+	Name    string
+	VkName  string
+	SType   string
+}
+
+func newCWrapper(params *HeaderParams, ctx *Context) *CWrapper {
+	w := &CWrapper{
+		VulkanHeader: params.VulkanHeader,
+		Banner:       params.Banner,
+		Defines:      params.Defines,
+		Constants:    ctx.Constants,
+		Commands:     ctx.Commands,
+	}
+	w.GuardBegin, w.GuardEnd = includeGuard(params.IncludeGuard, "/*")
+	// the enums of bitmasks are guarded along with them
+	protect := map[string]Protect{}
+	for _, bm := range ctx.BitMasks {
+		if bm.Enum != nil {
+			protect[bm.Enum.VkName] = bm.Protect
+		}
+	}
+	var sTypes *Enum
+	for i, e := range ctx.Enums {
+		if e.VkName == "VkStructureType" {
+			sTypes = &ctx.Enums[i]
+		}
+		if len(e.Values) == 0 {
+			continue
+		}
+		p, ok := protect[e.VkName]
+		if !ok {
+			p = e.Protect
+		}
+		w.Enums = append(w.Enums, CEnum{
+			Protect: p,
+			Name:    strings.TrimPrefix(e.VkName, "Vk"),
+			VkName:  e.VkName,
+			Values:  e.Values,
+		})
+	}
+	for _, s := range ctx.Structs {
+		if !s.HasSType || s.SType == "" || sTypes == nil {
+			continue
+		}
+		for _, v := range sTypes.Values {
+			if v.Name == s.SType {
+				w.Structs = append(w.Structs, CStruct{
+					Protect: s.Protect,
+					Name:    strings.TrimPrefix(s.VkName, "Vk"),
+					VkName:  s.VkName,
+					SType:   v.VkName,
+				})
+				break
+			}
+		}
+	}
+	return w
+}
+
+// cWrapperBackend is -lang c.
+type cWrapperBackend struct{}
+
+func init() {
+	RegisterBackend(cWrapperBackend{})
+}
+
+func (cWrapperBackend) Name() string { return "c" }
+
+func (cWrapperBackend) Generate(w io.Writer, ctx *Context, opts Options) error {
+	return ctx.execute(w, "cwrapper", newCWrapper(ctx.Params, ctx))
+}
@@ -0,0 +1,68 @@
+package cppgen
+
+import (
+	"os"
+	"strings"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// newBanner returns the lines of the comment emitted atop every generated
+// file, or nil if no banner option is set. It consists of the -banner file,
+// the -copyright holder and the -license SPDX identifier, followed by the
+// copyright notice of the registry the code is derived from and, with
+// -metadata, by what generated it (see metadataLines). With a license
+// of our own the registry's SPDX identifier is reworded, so that license
+// scanners see a single identifier per file.
+func newBanner(reg *registry.Registry, opts *Options) ([]string, error) {
+	if opts.BannerFile == "" && opts.Copyright == "" && opts.License == "" && !opts.Metadata {
+		return nil, nil
+	}
+	var lines []string
+	if opts.BannerFile != "" {
+		text, err := os.ReadFile(opts.BannerFile)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, splitLines(string(text))...)
+	}
+	if opts.Copyright != "" {
+		lines = append(lines, "Copyright "+strings.TrimPrefix(opts.Copyright, "Copyright "))
+	}
+	if opts.License != "" {
+		lines = append(lines, "SPDX-License-Identifier: "+opts.License)
+	}
+	if reg.Comment != "" {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "Generated by vulkangen from the Vulkan API Registry:")
+		for _, l := range splitLines(reg.Comment) {
+			if id := strings.TrimPrefix(l, "SPDX-License-Identifier:"); id != l && opts.License != "" {
+				l = "Licensed under " + strings.TrimSpace(id)
+			}
+			lines = append(lines, l)
+		}
+	}
+	if opts.Metadata {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		meta, err := metadataLines(reg, opts, os.Args)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, meta...)
+	}
+	return lines, nil
+}
+
+// splitLines splits s into lines, dropping leading and trailing blank lines
+// and trailing space
+func splitLines(s string) []string {
+	lines := strings.Split(strings.TrimSpace(strings.Replace(s, "\r\n", "\n", -1)), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t")
+	}
+	return lines
+}
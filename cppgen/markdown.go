@@ -0,0 +1,160 @@
+package cppgen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// specManPage is the URL of the man page of a Vulkan entity, %s is its C
+// name.
+const specManPage = "https://registry.khronos.org/vulkan/specs/latest/man/html/%s.html"
+
+// MarkdownDoc is the API reference of -markdown: every handle, enum,
+// bitmask, struct and command of the C++ header with the C entity it wraps,
+// linked to the man page of the latter.
+type MarkdownDoc struct {
+	Banner    []string
+	Namespace string
+	Sections  []MDSection
+}
+
+// MDSection is a section of the reference, Handles, Enums, etc.
+type MDSection struct {
+	Title    string
+	Entities []MDEntity
+}
+
+// MDEntity is an entity of the reference. Guard is the macro it's only
+// available with, Rows the rows of its table if it has one.
+type MDEntity struct {
+	Name      string
+	VkName    string
+	Link      string
+	Guard     string
+	Signature string
+	Notes     []string
+	Columns   []string
+	Rows      [][]string
+}
+
+// guardCondition is the condition of a guard, VK_USE_PLATFORM_XLIB_KHR.
+func guardCondition(p Protect) string {
+	c := strings.TrimPrefix(p.Begin, "#ifdef ")
+	return strings.TrimSpace(strings.TrimPrefix(c, "#if "))
+}
+
+// code quotes s as Markdown code, "" stays empty.
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + s + "`"
+}
+
+func newMarkdownDoc(params *HeaderParams, ctx *Context) *MarkdownDoc {
+	ns := ctx.Namespace + "::"
+	doc := &MarkdownDoc{Banner: params.Banner, Namespace: ctx.Namespace}
+	var handles, enums, bitMasks, structs, commands []MDEntity
+	entity := func(name, vkName string, p Protect) MDEntity {
+		return MDEntity{
+			Name:   ns + name,
+			VkName: vkName,
+			Link:   fmt.Sprintf(specManPage, vkName),
+			Guard:  guardCondition(p),
+		}
+	}
+	for _, h := range ctx.Handles {
+		e := entity(h.Name, h.VkName, h.Protect)
+		if len(h.Parents) > 0 {
+			var parents []string
+			for _, p := range h.Parents {
+				parents = append(parents, code(ns+p.Name))
+			}
+			e.Notes = append(e.Notes, "Created from "+strings.Join(parents, ", ")+".")
+		}
+		if len(h.Methods) > 0 {
+			var methods []string
+			for _, m := range h.Methods {
+				methods = append(methods, code(m.Name))
+			}
+			e.Notes = append(e.Notes, "Methods: "+strings.Join(methods, ", ")+".")
+		}
+		handles = append(handles, e)
+	}
+	enumRows := func(en *Enum) [][]string {
+		var rows [][]string
+		for _, v := range en.Values {
+			n := ""
+			if v.HasNumber {
+				n = fmt.Sprint(v.Number)
+			}
+			c := code(v.VkName)
+			if g := guardCondition(v.Protect); g != "" {
+				c += " (" + code(g) + ")"
+			}
+			rows = append(rows, []string{code(v.Name), c, n})
+		}
+		return rows
+	}
+	bitEnums := map[string]bool{}
+	for _, bm := range ctx.BitMasks {
+		e := entity(bm.Name, bm.VkName, bm.Protect)
+		if bm.Enum != nil && len(bm.Enum.Values) > 0 {
+			bitEnums[bm.Enum.VkName] = true
+			e.Notes = append(e.Notes, fmt.Sprintf("Flags of %s, the bits are:", code(ns+bm.Enum.Name)))
+			e.Columns = []string{"Bit", "C", "Value"}
+			e.Rows = enumRows(bm.Enum)
+		}
+		bitMasks = append(bitMasks, e)
+	}
+	for i := range ctx.Enums {
+		en := &ctx.Enums[i]
+		if bitEnums[en.VkName] {
+			continue
+		}
+		e := entity(en.Name, en.VkName, en.Protect)
+		e.Columns = []string{"Value", "C", "Number"}
+		e.Rows = enumRows(en)
+		enums = append(enums, e)
+	}
+	for _, s := range ctx.Structs {
+		e := entity(s.Name, s.VkName, s.Protect)
+		if s.Union {
+			e.Notes = append(e.Notes, "A union.")
+		}
+		if s.ReadOnly {
+			e.Notes = append(e.Notes, "Returned by the implementation only.")
+		}
+		var extends []string
+		for _, se := range ctx.StructExtensions {
+			if se.Name == s.Name {
+				extends = append(extends, code(ns+se.Base))
+			}
+		}
+		if len(extends) > 0 {
+			e.Notes = append(e.Notes, "Extends "+strings.Join(extends, ", ")+" through pNext.")
+		}
+		e.Columns = []string{"Member", "Type", "C type"}
+		for _, m := range s.Members {
+			e.Rows = append(e.Rows, []string{code(m.Accessor("")), code(strings.TrimSpace(m.Type)), code(strings.TrimSpace(m.VkType))})
+		}
+		structs = append(structs, e)
+	}
+	for _, c := range ctx.Commands {
+		e := entity(c.Name, c.VkName, c.Protect)
+		var params []string
+		for _, p := range c.Parameters {
+			params = append(params, strings.TrimSpace(p.Type)+" "+p.Name)
+		}
+		e.Signature = fmt.Sprintf("%s %s%s(%s)", c.RetType, ns, c.Name, strings.Join(params, ", "))
+		commands = append(commands, e)
+	}
+	doc.Sections = []MDSection{
+		{"Handles", handles},
+		{"Enums", enums},
+		{"Bitmasks", bitMasks},
+		{"Structs", structs},
+		{"Commands", commands},
+	}
+	return doc
+}
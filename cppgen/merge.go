@@ -0,0 +1,167 @@
+package cppgen
+
+import "github.com/nsf/vulkangen/registry"
+
+// mergeRegistry adds the types and enums of a companion registry (such as
+// video.xml, defining the StdVideo* types the video extensions use) to the
+// main one. They are only declared, so that references to them resolve, the
+// C headers of the companion spec define them and no C++ wrappers are
+// generated. Names the main registry already declares are left alone, other
+// parts of the companion registry are ignored. Registries which aren't
+// companions are layered with overlayRegistry instead.
+func mergeRegistry(dst, src *registry.Registry) {
+	types := map[string]bool{}
+	for i := range dst.Types.Type {
+		types[xmlTypeEntityName(&dst.Types.Type[i])] = true
+	}
+	for _, t := range src.Types.Type {
+		tname := xmlTypeEntityName(&t)
+		if tname == "" || types[tname] {
+			continue
+		}
+		types[tname] = true
+		t.External = true
+		dst.Types.Type = append(dst.Types.Type, t)
+	}
+	enums := map[string]bool{}
+	for _, e := range dst.Enums {
+		enums[e.Name] = true
+	}
+	for _, e := range src.Enums {
+		if enums[e.Name] && e.Name != "API Constants" {
+			continue
+		}
+		e.External = true
+		dst.Enums = append(dst.Enums, e)
+	}
+}
+
+// isCompanion reports whether a registry given after the main one is a
+// companion, declaring types for the main registry but nothing to generate:
+// it has no features, commands or numbered extensions.
+func isCompanion(r *registry.Registry) bool {
+	if len(r.Features) > 0 || len(r.Commands.Command) > 0 {
+		return false
+	}
+	for _, e := range r.Extensions.Extension {
+		if e.Number != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// overlayRegistry layers a registry on top of the main one, such as a
+// registry of vendor extensions not in the official spec. Its types,
+// commands, enums, features, extensions and platforms are generated as if
+// the main registry declared them, those with the name of one already
+// declared replace it in place. The values of its "API Constants" are added
+// to those of the main registry the same way. Returns the names of the
+// replaced entities.
+func overlayRegistry(dst, src *registry.Registry) []string {
+	var replaced []string
+	types := map[string]int{}
+	for i := range dst.Types.Type {
+		types[xmlTypeEntityName(&dst.Types.Type[i])] = i
+	}
+	for _, t := range src.Types.Type {
+		tname := xmlTypeEntityName(&t)
+		if i, ok := types[tname]; ok {
+			dst.Types.Type[i] = t
+			replaced = append(replaced, tname)
+			continue
+		}
+		types[tname] = len(dst.Types.Type)
+		dst.Types.Type = append(dst.Types.Type, t)
+	}
+	commands := map[string]int{}
+	for i := range dst.Commands.Command {
+		commands[xmlCommandEntityName(&dst.Commands.Command[i])] = i
+	}
+	for _, c := range src.Commands.Command {
+		cname := xmlCommandEntityName(&c)
+		if i, ok := commands[cname]; ok {
+			dst.Commands.Command[i] = c
+			replaced = append(replaced, cname)
+			continue
+		}
+		commands[cname] = len(dst.Commands.Command)
+		dst.Commands.Command = append(dst.Commands.Command, c)
+	}
+	enums := map[string]int{}
+	for i, e := range dst.Enums {
+		enums[e.Name] = i
+	}
+	for _, e := range src.Enums {
+		i, ok := enums[e.Name]
+		switch {
+		case !ok:
+			enums[e.Name] = len(dst.Enums)
+			dst.Enums = append(dst.Enums, e)
+		case e.Name == "API Constants":
+			replaced = append(replaced, overlayEnumValues(&dst.Enums[i], e.Values)...)
+		default:
+			dst.Enums[i] = e
+			replaced = append(replaced, e.Name)
+		}
+	}
+	features := map[string]int{}
+	for i, f := range dst.Features {
+		features[f.Name] = i
+	}
+	for _, f := range src.Features {
+		if i, ok := features[f.Name]; ok {
+			dst.Features[i] = f
+			replaced = append(replaced, f.Name)
+			continue
+		}
+		features[f.Name] = len(dst.Features)
+		dst.Features = append(dst.Features, f)
+	}
+	extensions := map[string]int{}
+	for i, e := range dst.Extensions.Extension {
+		extensions[e.Name] = i
+	}
+	for _, e := range src.Extensions.Extension {
+		if i, ok := extensions[e.Name]; ok {
+			dst.Extensions.Extension[i] = e
+			replaced = append(replaced, e.Name)
+			continue
+		}
+		extensions[e.Name] = len(dst.Extensions.Extension)
+		dst.Extensions.Extension = append(dst.Extensions.Extension, e)
+	}
+	platforms := map[string]int{}
+	for i, p := range dst.Platforms.Platform {
+		platforms[p.Name] = i
+	}
+	for _, p := range src.Platforms.Platform {
+		if i, ok := platforms[p.Name]; ok {
+			dst.Platforms.Platform[i] = p
+			continue
+		}
+		platforms[p.Name] = len(dst.Platforms.Platform)
+		dst.Platforms.Platform = append(dst.Platforms.Platform, p)
+	}
+	return replaced
+}
+
+// overlayEnumValues adds values to the enum, replacing those of the same
+// name. Returns the names of the replaced values.
+func overlayEnumValues(dst *registry.Enums, values []registry.Enum) []string {
+	var replaced []string
+	index := map[string]int{}
+	for i, v := range dst.Values {
+		index[v.Name] = i
+	}
+	for _, v := range values {
+		if i, ok := index[v.Name]; ok {
+			dst.Values[i] = v
+			replaced = append(replaced, v.Name)
+			continue
+		}
+		index[v.Name] = len(dst.Values)
+		dst.Values = append(dst.Values, v)
+	}
+	return replaced
+}
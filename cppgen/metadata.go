@@ -0,0 +1,97 @@
+package cppgen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// version is the version of vulkangen, set with -ldflags
+// "-X github.com/nsf/vulkangen/cppgen.version=..." by release builds.
+// Otherwise the module version or VCS revision Go records in the binary is
+// used.
+var version = ""
+
+// generatorVersion returns version or what the build info knows about it.
+func generatorVersion() string {
+	if version != "" {
+		return version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	v := info.Main.Version
+	var revision, modified string
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value
+		}
+	}
+	if (v == "" || v == "(devel)") && revision != "" {
+		if len(revision) > 12 {
+			revision = revision[:12]
+		}
+		v = revision
+		if modified == "true" {
+			v += "-dirty"
+		}
+	}
+	if v == "" {
+		return "(devel)"
+	}
+	return v
+}
+
+// metadataLines are the banner lines with -metadata: the version of
+// vulkangen, VK_HEADER_VERSION of the spec, the command line and, with
+// -timestamp, the time of generation. $SOURCE_DATE_EPOCH replaces the
+// current time for reproducible builds.
+func metadataLines(reg *registry.Registry, opts *Options, args []string) ([]string, error) {
+	spec := "unknown"
+	if v := headerVersion(reg); v >= 0 {
+		spec = strconv.Itoa(v)
+	}
+	lines := []string{
+		"vulkangen version: " + generatorVersion(),
+		"VK_HEADER_VERSION: " + spec,
+		"Command line: " + commandLine(args),
+	}
+	if opts.Timestamp {
+		t := time.Now()
+		if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+			sec, err := strconv.ParseInt(epoch, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("SOURCE_DATE_EPOCH %q isn't a number of seconds", epoch)
+			}
+			t = time.Unix(sec, 0)
+		}
+		lines = append(lines, "Generated at: "+t.UTC().Format(time.RFC3339))
+	}
+	return lines, nil
+}
+
+// commandLine quotes the arguments which need it for a shell, the program
+// is named without its directory.
+func commandLine(args []string) string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		if i == 0 {
+			a = filepath.Base(a)
+		}
+		if a == "" || strings.ContainsAny(a, " \t\n\"'\\$`*?;&|<>()#~") {
+			a = "'" + strings.Replace(a, "'", `'\''`, -1) + "'"
+		}
+		out[i] = a
+	}
+	return strings.Join(out, " ")
+}
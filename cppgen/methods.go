@@ -0,0 +1,161 @@
+package cppgen
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Method is a member function of a handle calling a command which takes the
+// handle as its first parameter, Device::createBuffer for vkCreateBuffer. It
+// has an overload for each overload of the command, all calling Call with
+// the handle in place of the first argument.
+type Method struct {
+	// guards the method if they differ from those of the handle
+	Protect Protect
+
+	Name      string
+	Call      string
+	Overloads []MethodOverload
+}
+
+type MethodOverload struct {
+	RetType    string
+	Parameters []MethodParameter
+	Arguments  []string
+	Noexcept   string
+
+	// Throwing and NoExceptions are the overloads of a command throwing on
+	// errors in the #ifndef VKGEN_NO_EXCEPTIONS and #else branches, see
+	// ValueReturn
+	Throwing     bool
+	NoExceptions bool
+}
+
+type MethodParameter struct {
+	Type string
+	Name string
+}
+
+// resolveMethods attaches the commands to the handles they are called on.
+// Methods whose name, after dropping the handle name, clashes with an
+// overload of another method keep the name of the command.
+func (ctx *Context) resolveMethods() {
+	signatures := map[*Handle]map[string]bool{}
+	for i := range ctx.Commands {
+		c := &ctx.Commands[i]
+		if len(c.Parameters) == 0 || !c.Parameters[0].AnalyzedType.IsBlank {
+			continue
+		}
+		h := ctx.Handle(c.Parameters[0].AnalyzedType.Type)
+		if h == nil {
+			continue
+		}
+		m := Method{Call: ctx.Namespace + "::" + c.Name}
+		if c.Protect != h.Protect {
+			m.Protect = c.Protect
+		}
+		m.Overloads = append(m.Overloads, newMethodOverload(c.RetType, c.Noexcept(), c.Parameters))
+		if v := c.Value; v != nil {
+			if v.Plain || v.Checked {
+				o := newMethodOverload(v.Type, v.Variant(false).Noexcept(), v.Parameters)
+				o.Throwing = v.Checked
+				m.Overloads = append(m.Overloads, o)
+			}
+			if !v.Plain {
+				vv := v.Variant(true)
+				o := newMethodOverload(vv.ReturnType(), vv.Noexcept(), v.Parameters)
+				o.NoExceptions = v.Checked
+				m.Overloads = append(m.Overloads, o)
+			}
+		}
+		if a := c.Arrays; a != nil {
+			params := make([]CommandParameter, len(a.Parameters))
+			for j, p := range a.Parameters {
+				params[j] = CommandParameter{Type: p.Type, Name: p.Name}
+			}
+			m.Overloads = append(m.Overloads, newMethodOverload(a.RetType, a.Noexcept, params))
+		}
+
+		if signatures[h] == nil {
+			signatures[h] = map[string]bool{}
+		}
+		m.Name = ctx.naming.Function(methodName(h, convertCommandName(c.VkName)))
+		for _, o := range m.Overloads {
+			if signatures[h][o.signature(m.Name)] {
+				m.Name = c.Name
+				break
+			}
+		}
+		for _, o := range m.Overloads {
+			signatures[h][o.signature(m.Name)] = true
+		}
+		h.Methods = append(h.Methods, m)
+	}
+}
+
+// newMethodOverload returns the overload calling a command overload taking
+// params, passing the handle as the first one.
+func newMethodOverload(retType, noexcept string, params []CommandParameter) MethodOverload {
+	o := MethodOverload{RetType: retType, Noexcept: noexcept, Arguments: []string{"*this"}}
+	for _, p := range params[1:] {
+		o.Parameters = append(o.Parameters, MethodParameter{Type: p.Type, Name: p.Name})
+		o.Arguments = append(o.Arguments, p.Name)
+	}
+	return o
+}
+
+func (o *MethodOverload) signature(name string) string {
+	types := make([]string, len(o.Parameters))
+	for i, p := range o.Parameters {
+		types[i] = p.Type
+	}
+	return name + "(" + strings.Join(types, ", ") + ")"
+}
+
+// methodName names the method of a command on the handle, dropping the cmd
+// prefix of command buffer commands (CommandBuffer::bindPipeline) and the
+// handle name (Queue::submit, PhysicalDevice::getProperties).
+func methodName(h *Handle, command string) string {
+	lowerFirst := func(s string) string {
+		return strings.ToLower(s[:1]) + s[1:]
+	}
+	if h.Name == "CommandBuffer" && len(command) > 3 && strings.HasPrefix(command, "cmd") && unicode.IsUpper(rune(command[3])) {
+		return lowerFirst(command[3:])
+	}
+	// the handle name is the first word or any of the others, followed by
+	// the next word or the end
+	for i := 0; i < len(command); i++ {
+		if i > 0 && !unicode.IsUpper(rune(command[i])) {
+			continue
+		}
+		word := h.Name
+		if i == 0 {
+			word = lowerFirst(word)
+		}
+		if !strings.HasPrefix(command[i:], word) {
+			continue
+		}
+		rest := command[i+len(word):]
+		if rest != "" && unicode.IsLower(rune(rest[0])) {
+			continue
+		}
+		if i == 0 {
+			if rest == "" {
+				return command
+			}
+			return lowerFirst(rest)
+		}
+		return command[:i] + rest
+	}
+	return command
+}
+
+// HasMethods reports whether any handle has member functions.
+func (ctx *Context) HasMethods() bool {
+	for _, h := range ctx.Handles {
+		if len(h.Methods) > 0 {
+			return true
+		}
+	}
+	return false
+}
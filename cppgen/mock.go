@@ -0,0 +1,121 @@
+package cppgen
+
+import (
+	"io"
+	"strings"
+)
+
+// MockSource is the C++ source of -mock, a null driver: every command is
+// defined as a stub which records the call, writes made up handles to its
+// handle outputs and returns VK_SUCCESS, or what vkmock::setResult says.
+// Tests link it instead of the Vulkan loader, define VKMOCK_API_ONLY and
+// include it for the declarations of vkmock.
+type MockSource struct {
+	Banner       []string
+	Defines      []Define
+	VulkanHeader string
+	Commands     []MockCommand
+}
+
+type MockCommand struct {
+	Protect    Protect
+	VkName     string
+	RetVkType  string
+	Parameters []CommandParameter
+
+	// the statements filling the outputs
+	Outputs []string
+}
+
+// Returns is what the stub returns, "" for void.
+func (c MockCommand) Returns() string {
+	switch c.RetVkType {
+	case "void":
+		return ""
+	case "VkResult":
+		return "result"
+	case "PFN_vkVoidFunction":
+		for _, p := range c.Parameters {
+			if p.Name == "pName" {
+				return "vkmock::detail::lookup(pName)"
+			}
+		}
+	}
+	return "{}"
+}
+
+// mockOutputs returns the statements of the stub of c filling its outputs:
+// handles are made up, one for each element of arrays of them and one when
+// asked for the number of them. Nothing else is returned by arrays with a
+// count to set, other outputs are left as they are.
+func mockOutputs(c *Command, handles map[string]bool) []string {
+	// the parameters through which numbers of elements are returned
+	counts := map[string]bool{}
+	for _, p := range c.Parameters {
+		if p.AnalyzedType.IsPointer && !p.AnalyzedType.IsConst && (p.AnalyzedType.Type == "uint32_t" || p.AnalyzedType.Type == "size_t") {
+			counts[p.Name] = true
+		}
+	}
+	var out []string
+	set := map[string]bool{}
+	for _, p := range c.Parameters {
+		at := p.AnalyzedType
+		if !at.IsPointer || at.IsConst || at.IsArray {
+			continue
+		}
+		length := p.Len
+		if strings.Contains(length, "latexmath") || strings.Contains(length, "null-terminated") {
+			length = ""
+		}
+		switch {
+		case counts[length] && handles[at.Type]:
+			out = append(out,
+				"if (!"+p.Name+") {",
+				"\t*"+length+" = 1;",
+				"} else {",
+				"\tfor (uint32_t i = 0; i < *"+length+"; i++) "+p.Name+"[i] = vkmock::detail::make<"+at.Type+">();",
+				"}")
+			set[length] = true
+		case counts[length] && !set[length]:
+			out = append(out, "*"+length+" = 0;")
+			set[length] = true
+		case !handles[at.Type]:
+		case length != "":
+			out = append(out, "for (uint32_t i = 0; i < "+length+"; i++) "+p.Name+"[i] = vkmock::detail::make<"+at.Type+">();")
+		default:
+			out = append(out, "if ("+p.Name+") *"+p.Name+" = vkmock::detail::make<"+at.Type+">();")
+		}
+	}
+	return out
+}
+
+func newMockSource(params *HeaderParams, ctx *Context) *MockSource {
+	m := &MockSource{Banner: params.Banner, Defines: params.Defines, VulkanHeader: params.VulkanHeader}
+	handles := map[string]bool{}
+	for _, h := range ctx.Handles {
+		handles[h.VkName] = true
+	}
+	for i := range ctx.Commands {
+		c := &ctx.Commands[i]
+		m.Commands = append(m.Commands, MockCommand{
+			Protect:    c.Protect,
+			VkName:     c.VkName,
+			RetVkType:  c.RetVkType,
+			Parameters: c.Parameters,
+			Outputs:    mockOutputs(c, handles),
+		})
+	}
+	return m
+}
+
+func mockBackend(file string, params *HeaderParams, ctx *Context) backend {
+	m := newMockSource(params, ctx)
+	return backend{
+		name: "mock driver",
+		file: file,
+		lang: "c++",
+		emit: func(w io.Writer) error {
+			return ctx.execute(w, "mock", m)
+		},
+	}
+}
@@ -0,0 +1,180 @@
+package cppgen
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// namingConversions are the name conversions the naming corpus covers.
+var namingConversions = map[string]func(string) string{
+	"toCamelCase":           toCamelCase,
+	"toSnakeCase":           toSnakeCase,
+	"toLowerSnakeCase":      toLowerSnakeCase,
+	"convertCommandName":    convertCommandName,
+	"bitMaskNameToEnumName": bitMaskNameToEnumName,
+	"enumValuePrefix":       enumValuePrefix,
+}
+
+// namingCorpus are names of the registry with what they convert to. Any
+// change to these renames the generated API.
+var namingCorpus = []struct {
+	conv, in, want string
+}{
+	{"toCamelCase", "PIPELINE_DEPTH_STENCIL_STATE_CREATE_INFO", "PipelineDepthStencilStateCreateInfo"},
+	{"toCamelCase", "R8G8B8A8_UNORM", "R8G8B8A8Unorm"},
+	{"toCamelCase", "ASTC_10x10_SRGB_BLOCK", "Astc10x10SrgbBlock"},
+	{"toCamelCase", "A2B10G10R10_UINT_PACK32", "A2B10G10R10UintPack32"},
+	{"toCamelCase", "TYPE_2D_ARRAY", "Type2DArray"},
+	{"toCamelCase", "16", "16"},
+	{"toSnakeCase", "PipelineDepthStencilStateCreateInfo", "PIPELINE_DEPTH_STENCIL_STATE_CREATE_INFO"},
+	{"toSnakeCase", "VkImageUsageFlagBits", "VK_IMAGE_USAGE_FLAG_BITS"},
+	{"toSnakeCase", "VkShaderFloatControlsIndependence", "VK_SHADER_FLOAT_CONTROLS_INDEPENDENCE"},
+	{"toSnakeCase", "VkPhysicalDeviceType", "VK_PHYSICAL_DEVICE_TYPE"},
+	{"toLowerSnakeCase", "getPhysicalDeviceSurfaceCapabilities2KHR", "get_physical_device_surface_capabilities2_khr"},
+	{"toLowerSnakeCase", "maxImageDimension2D", "max_image_dimension2d"},
+	{"toLowerSnakeCase", "deviceLUIDValid", "device_luid_valid"},
+	{"toLowerSnakeCase", "deviceUUID", "device_uuid"},
+	{"toLowerSnakeCase", "vendorID", "vendor_id"},
+	{"toLowerSnakeCase", "pNext", "p_next"},
+	{"toLowerSnakeCase", "ppEnabledLayerNames", "pp_enabled_layer_names"},
+	{"toLowerSnakeCase", "shaderInt64", "shader_int64"},
+	{"toLowerSnakeCase", "bufferDeviceAddressCaptureReplay", "buffer_device_address_capture_replay"},
+	{"toLowerSnakeCase", "cmdSetColorWriteMaskEXT", "cmd_set_color_write_mask_ext"},
+	{"toLowerSnakeCase", "createRGBA10X6Image", "create_rgba10x6_image"},
+	{"toLowerSnakeCase", "samplerYcbcrConversion", "sampler_ycbcr_conversion"},
+	{"convertCommandName", "vkCreateInstance", "createInstance"},
+	{"convertCommandName", "vkGetPhysicalDeviceSurfaceCapabilities2KHR", "getPhysicalDeviceSurfaceCapabilities2KHR"},
+	{"convertCommandName", "vkCmdSetColorWriteMaskEXT", "cmdSetColorWriteMaskEXT"},
+	{"bitMaskNameToEnumName", "VkImageUsageFlags", "VkImageUsageFlagBits"},
+	{"bitMaskNameToEnumName", "VkSurfaceTransformFlagsKHR", "VkSurfaceTransformFlagBitsKHR"},
+	{"bitMaskNameToEnumName", "VkDebugUtilsMessageSeverityFlagsEXT", "VkDebugUtilsMessageSeverityFlagBitsEXT"},
+	{"bitMaskNameToEnumName", "VkPipelineStageFlags2", "VkPipelineStageFlags2"},
+	{"enumValuePrefix", "VkImageUsageFlagBits", "VK_IMAGE_USAGE"},
+	{"enumValuePrefix", "VkSurfaceTransformFlagBitsKHR", "VK_SURFACE_TRANSFORM"},
+	{"enumValuePrefix", "VkDebugUtilsMessageSeverityFlagBitsEXT", "VK_DEBUG_UTILS_MESSAGE_SEVERITY"},
+	{"enumValuePrefix", "VkPipelineStageFlagBits2", "VK_PIPELINE_STAGE_2"},
+	{"enumValuePrefix", "VkAccessFlagBits2KHR", "VK_ACCESS_2"},
+	{"enumValuePrefix", "VkFormat", "VK_FORMAT"},
+}
+
+// enumValueCorpus are enum values of the registry with their names in the
+// camel and snake naming conventions.
+var enumValueCorpus = []struct {
+	enum, value, camel, snake string
+}{
+	{"VkFormat", "VK_FORMAT_R8G8B8A8_UNORM", "eR8G8B8A8Unorm", "e_r8g8b8a8_unorm"},
+	{"VkFormat", "VK_FORMAT_ASTC_4x4_SRGB_BLOCK", "eAstc4x4SrgbBlock", "e_astc_4x4_srgb_block"},
+	{"VkFormat", "VK_FORMAT_G8_B8R8_2PLANE_420_UNORM", "eG8B8R82Plane420Unorm", "e_g8_b8r8_2plane_420_unorm"},
+	{"VkImageType", "VK_IMAGE_TYPE_2D", "e2D", "e_2d"},
+	{"VkImageViewType", "VK_IMAGE_VIEW_TYPE_CUBE_ARRAY", "eCubeArray", "e_cube_array"},
+	{"VkImageUsageFlagBits", "VK_IMAGE_USAGE_TRANSFER_SRC_BIT", "eTransferSrc", "e_transfer_src"},
+	{"VkSampleCountFlagBits", "VK_SAMPLE_COUNT_16_BIT", "e16", "e_16"},
+	{"VkResult", "VK_SUCCESS", "eSuccess", "e_success"},
+	{"VkResult", "VK_ERROR_OUT_OF_DATE_KHR", "eErrorOutOfDate", "e_error_out_of_date"},
+	{"VkStructureType", "VK_STRUCTURE_TYPE_PHYSICAL_DEVICE_ID_PROPERTIES", "ePhysicalDeviceIdProperties", "e_physical_device_id_properties"},
+	{"VkStructureType", "VK_STRUCTURE_TYPE_PHYSICAL_DEVICE_VULKAN_1_2_FEATURES", "ePhysicalDeviceVulkan12Features", "e_physical_device_vulkan_1_2_features"},
+	{"VkStructureType", "VK_STRUCTURE_TYPE_PHYSICAL_DEVICE_RGBA10X6_FORMATS_FEATURES_EXT", "ePhysicalDeviceRgba10X6FormatsFeatures", "e_physical_device_rgba10x6_formats_features"},
+	{"VkSurfaceTransformFlagBitsKHR", "VK_SURFACE_TRANSFORM_ROTATE_90_BIT_KHR", "eRotate90", "e_rotate_90"},
+	{"VkColorSpaceKHR", "VK_COLOR_SPACE_SRGB_NONLINEAR_KHR", "eSrgbNonlinear", "e_srgb_nonlinear"},
+	{"VkDebugUtilsMessageSeverityFlagBitsEXT", "VK_DEBUG_UTILS_MESSAGE_SEVERITY_VERBOSE_BIT_EXT", "eVerbose", "e_verbose"},
+	{"VkPipelineStageFlagBits2", "VK_PIPELINE_STAGE_2_TOP_OF_PIPE_BIT", "eTopOfPipe", "e_top_of_pipe"},
+	{"VkAccessFlagBits2", "VK_ACCESS_2_SHADER_READ_BIT", "eShaderRead", "e_shader_read"},
+}
+
+func TestNamingCorpus(t *testing.T) {
+	for _, c := range namingCorpus {
+		if got := namingConversions[c.conv](c.in); got != c.want {
+			t.Errorf("%s(%q) = %q, want %q", c.conv, c.in, got, c.want)
+		}
+	}
+	camel, snake := namingPolicies["camel"], namingPolicies["snake"]
+	for _, c := range enumValueCorpus {
+		if got := convertEnumValueName(camel, "", c.enum, c.value); got != c.camel {
+			t.Errorf("camel name of %s.%s = %q, want %q", c.enum, c.value, got, c.camel)
+		}
+		if got := convertEnumValueName(snake, "", c.enum, c.value); got != c.snake {
+			t.Errorf("snake name of %s.%s = %q, want %q", c.enum, c.value, got, c.snake)
+		}
+	}
+}
+
+// namingWords are the words fuzzed names are made of, the round trips
+// don't hold for acronyms, which the corpus covers.
+var namingWords = []string{
+	"Image", "Buffer", "Depth", "Stencil", "Format", "Memory", "Queue",
+	"Shader", "Float", "Sample", "Count", "Device", "Host", "Coherent",
+	"Transfer", "Src", "Dst", "Color", "Attachment", "Vertex", "Index",
+	"Storage", "Uniform", "Texel", "Sparse", "Binding", "Residency",
+	"Protected", "Swapchain", "Present", "Mode", "View", "Layout",
+}
+
+// wordsOf returns the words picked by the bytes of b, at most max of them,
+// the ones with the high bit set end with digits.
+func wordsOf(b []byte, max int) []string {
+	if len(b) > max {
+		b = b[:max]
+	}
+	words := make([]string, len(b))
+	for i, c := range b {
+		words[i] = namingWords[int(c&0x7f)%len(namingWords)]
+		if c&0x80 != 0 {
+			words[i] += fmt.Sprint(int(c&0x7f)%64 + 1)
+		}
+	}
+	return words
+}
+
+// FuzzNaming checks that the name conversions don't panic on any name, and
+// that names made of registry words convert back and forth.
+func FuzzNaming(f *testing.F) {
+	for _, c := range namingCorpus {
+		f.Add(c.in, []byte{0, 1}, []byte{2}, uint8(0))
+	}
+	for _, c := range enumValueCorpus {
+		f.Add(c.value, []byte{3, 0x84, 5}, []byte{6, 0x87}, uint8(1))
+	}
+	f.Add("", []byte{0x80, 10, 20, 30, 0xa8}, []byte{0xff}, uint8(2))
+
+	camel, snake := namingPolicies["camel"], namingPolicies["snake"]
+	f.Fuzz(func(t *testing.T, raw string, nameWords, restWords []byte, tagIndex uint8) {
+		for _, conv := range namingConversions {
+			conv(raw)
+		}
+		convertEnumValueName(camel, "", raw, raw)
+		convertEnumValueName(snake, raw, raw, raw)
+
+		words, rest := wordsOf(nameWords, 5), wordsOf(restWords, 3)
+		if len(words) == 0 || len(rest) == 0 {
+			return
+		}
+		name := strings.Join(words, "")
+		upper := strings.ToUpper(strings.Join(words, "_"))
+
+		// CamelCase and SNAKE_CASE convert to each other
+		if got := toSnakeCase(name); got != upper {
+			t.Errorf("toSnakeCase(%q) = %q, want %q", name, got, upper)
+		}
+		if got := toCamelCase(upper); got != name {
+			t.Errorf("toCamelCase(%q) = %q, want %q", upper, got, name)
+		}
+
+		// members and commands are split into the same words
+		member := strings.ToLower(name[:1]) + name[1:]
+		if got, want := toLowerSnakeCase(member), strings.ToLower(upper); got != want {
+			t.Errorf("toLowerSnakeCase(%q) = %q, want %q", member, got, want)
+		}
+
+		// the values of an enum lose its name, tag and _BIT
+		tag := []string{"", "KHR", "EXT"}[tagIndex%3]
+		suffix := "_BIT"
+		if tag != "" {
+			suffix += "_" + tag
+		}
+		enum := "Vk" + name + "FlagBits" + tag
+		value := "VK_" + upper + "_" + strings.ToUpper(strings.Join(rest, "_")) + suffix
+		if got, want := convertEnumValueName(camel, "", enum, value), "e"+strings.Join(rest, ""); got != want {
+			t.Errorf("camel name of %s.%s = %q, want %q", enum, value, got, want)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package cppgen
+
+// interner keeps one copy of the strings the naming and converter layers
+// derive from the registry. The same inputs come up over and over: every
+// "const VkAllocationCallbacks*" parameter, every value of an enum needing
+// the snake case prefix of the enum name, every array of the same element
+// type. The interner builds each result once and hands out the shared copy.
+//
+// A nil *interner is valid and builds every result from scratch, the
+// benchmarks use it as the baseline.
+type interner struct {
+	derived map[derivedKey]string
+	arrays  map[string]*ArrayConverter
+}
+
+type derivedKey struct {
+	kind string
+	a, b string
+}
+
+func newInterner() *interner {
+	return &interner{
+		derived: map[derivedKey]string{},
+		arrays:  map[string]*ArrayConverter{},
+	}
+}
+
+// derive returns f(a, b), computed once per kind and arguments.
+func (in *interner) derive(kind, a, b string, f func(a, b string) string) string {
+	if in == nil {
+		return f(a, b)
+	}
+	k := derivedKey{kind, a, b}
+	if s, ok := in.derived[k]; ok {
+		return s
+	}
+	s := f(a, b)
+	in.derived[k] = s
+	return s
+}
+
+func (in *interner) enumValueName(naming namingPolicy, expand, enum, name string) string {
+	prefix := in.derive("enumprefix", enum, "", func(enum, _ string) string {
+		return enumValuePrefix(enum)
+	})
+	return trimEnumValueName(naming, expand, prefix, enum, name)
+}
+
+// typeName returns the memoized spelling of ct, cpp selects the C++ type
+// name.
+func (in *interner) typeName(ct CType, cpp bool) string {
+	kind, base := "vktype", ct.Type
+	if cpp {
+		kind, base = "cpptype", convertVkName(ct.Type)
+	}
+	return in.derive(kind, base, ct.Prefix()+ct.Suffix(), func(base, _ string) string {
+		return ct.Spell(base)
+	})
+}
+
+// arrayConverter returns the converter for arrays of typ, converters are
+// stateless and shared by all arrays of the same element type.
+func (in *interner) arrayConverter(typ string) *ArrayConverter {
+	if in == nil {
+		return &ArrayConverter{VkName: typ, CppName: convertVkName(typ)}
+	}
+	c, ok := in.arrays[typ]
+	if !ok {
+		c = &ArrayConverter{VkName: typ, CppName: convertVkName(typ)}
+		in.arrays[typ] = c
+	}
+	return c
+}
@@ -0,0 +1,82 @@
+package cppgen
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// namingPolicy turns the names derived from the registry into identifiers
+// of the generated code, see Options.Naming. Type names are the same for all
+// policies.
+type namingPolicy interface {
+	// Function names a command or a method given its camelCase name,
+	// createBuffer
+	Function(name string) string
+
+	// Member names the accessors of a struct member given its C name,
+	// queueFamilyIndex
+	Member(name string) string
+
+	// EnumValue names an enum value given the words of its C name left after
+	// the enum prefix and tag suffix, TRANSFER_SRC
+	EnumValue(words string) string
+}
+
+var namingPolicies = map[string]namingPolicy{
+	"camel": camelNaming{},
+	"snake": snakeNaming{},
+}
+
+func newNamingPolicy(name string) (namingPolicy, error) {
+	if name == "" {
+		name = "camel"
+	}
+	p, ok := namingPolicies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown naming convention %q, want camel or snake", name)
+	}
+	return p, nil
+}
+
+// camelNaming keeps the names of the C API: createBuffer, queueFamilyIndex,
+// eTransferSrc.
+type camelNaming struct{}
+
+func (camelNaming) Function(name string) string   { return name }
+func (camelNaming) Member(name string) string     { return name }
+func (camelNaming) EnumValue(words string) string { return "e" + toCamelCase(words) }
+
+// snakeNaming names everything but types in lowercase snake_case:
+// create_buffer, queue_family_index, e_transfer_src.
+type snakeNaming struct{}
+
+func (snakeNaming) Function(name string) string   { return toLowerSnakeCase(name) }
+func (snakeNaming) Member(name string) string     { return toLowerSnakeCase(name) }
+func (snakeNaming) EnumValue(words string) string { return "e_" + strings.ToLower(words) }
+
+// toLowerSnakeCase splits a camelCase name into lowercase words. Digits stay
+// with the word before them and so do single capitals following a digit,
+// tags are words of their own: getPhysicalDeviceSurfaceCapabilities2KHR ->
+// get_physical_device_surface_capabilities2_khr, maxImageDimension2D ->
+// max_image_dimension2d, deviceLUIDValid -> device_luid_valid.
+func toLowerSnakeCase(s string) string {
+	s, tag := trimTagSuffix(s)
+	rs := []rune(s)
+	var b strings.Builder
+	b.Grow(len(s) + len(s)/4 + len(tag) + 1)
+	for i, r := range rs {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := rs[i-1]
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if unicode.IsLower(prev) || (unicode.IsUpper(prev) || unicode.IsDigit(prev)) && nextLower {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	if tag != "" {
+		b.WriteString("_" + strings.ToLower(tag))
+	}
+	return b.String()
+}
@@ -0,0 +1,393 @@
+package cppgen
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// listFlag is a comma-separated list of strings, setting it replaces the
+// default value.
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ",") }
+
+func (l listFlag) contains(s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *listFlag) Set(s string) error {
+	*l = nil
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+// Options controls what gets generated and how.
+type Options struct {
+	// names of uint32_t struct members holding versions packed with
+	// VK_MAKE_API_VERSION, they get Version accessors
+	VersionMembers listFlag
+
+	// structs which get endian-safe serialize/deserialize functions, none
+	// by default, structs they contain are included automatically
+	SerializeStructs listFlag
+
+	// structs which get to_json/from_json functions, structs they contain
+	// are included automatically
+	JSONStructs listFlag
+
+	// exclude broken or unsupported registry entities and their dependents
+	// instead of failing
+	SkipBroken bool
+
+	// include provisional (beta) extensions, guarded by
+	// VK_ENABLE_BETA_EXTENSIONS
+	Provisional bool
+
+	// when a command or struct references a filtered out type, put the type
+	// back instead of excluding the referencing entity
+	PullInTypes bool
+
+	// fetch the spec instead of reading a local file: a Vulkan-Docs tag
+	// (v1.3.280) or any URL, downloads are cached in SpecCache (user cache
+	// directory by default)
+	SpecVersion string
+	SpecURL     string
+	SpecCache   string
+
+	// platforms (xlib, win32, ...) whose extensions are generated, all if
+	// empty
+	Platforms listFlag
+
+	// extensions to generate, all if empty. Extensions they depend on are
+	// added by selectExtensions.
+	Extensions listFlag
+
+	// extensions not to generate, whether selected or not. Extensions
+	// depending on them are excluded too by selectExtensions.
+	ExcludeExtensions listFlag
+
+	// how platform headers get included: "" leaves it to the user, "define"
+	// defines VK_USE_PLATFORM_* macros of selected platforms before vulkan.h,
+	// "include" includes the per-platform headers directly
+	PlatformSetup string
+
+	// enums whose values are dense get an array of names indexed by value in
+	// getEnumString, other enums with at least this many values get a sorted
+	// lookup table instead of a switch, 0 disables tables
+	EnumStringTable int
+
+	// name of the symbol visibility macro (VKGEN_API) for out-of-line
+	// symbols, its definition block is emitted if set
+	ExportMacro string
+
+	// macros (NAME or NAME=VALUE) defined before including vulkan.h
+	Defines listFlag
+
+	// extra headers included after vulkan.h and at the end of the header
+	Includes         listFlag
+	EpilogueIncludes listFlag
+
+	// generate move-only Unique* wrappers destroying their handle
+	UniqueHandles bool
+
+	// commands returning Result throw Error on error codes, unless
+	// VKGEN_NO_EXCEPTIONS is defined, which it is if the compiler has
+	// exceptions turned off
+	Exceptions bool
+
+	// structs are plain aggregates laid out like the C structs, for C++20
+	// designated initializers, rather than wrappers with setters
+	AggregateStructs bool
+
+	// generate vk::safe variants of the structs which own copies of the
+	// arrays, strings and pNext chains they point to, see SafeStruct
+	SafeStructs bool
+
+	// generate DispatchLoaderDynamic and overloads of the commands calling
+	// through it, see DispatchCommand
+	DynamicDispatch bool
+
+	// the language of the output, the name of one of languageBackends
+	// generated instead of the C++ header
+	Lang string
+
+	// write a C++20 module interface unit exporting the namespace instead
+	// of a header, vulkan.h and the standard headers are included in its
+	// global module fragment
+	Module bool
+
+	// macro of #ifndef include guards used instead of #pragma once, parts
+	// of a split header and the C and forward declaration headers append
+	// their name to it, see includeGuard
+	IncludeGuard string
+
+	// sections of the header to generate, all if Only is empty, see
+	// selectSections
+	Only listFlag
+	Skip listFlag
+
+	// directory to write the header split into parts to, instead of one
+	// file, see splitParts
+	SplitDir string
+
+	// put everything in an inline namespace named after the API and header
+	// version of the spec, see versionNamespace
+	VersionNamespace bool
+
+	// namespace the C++ header is generated in, nested ones (acme::vk)
+	// need C++17
+	Namespace string
+
+	// include file spec of the Vulkan C API, <vulkan/vulkan.h> by default
+	VulkanHeader string
+
+	// naming convention of functions, struct members and enum values:
+	// "camel" (createBuffer) or "snake" (create_buffer), see namingPolicy
+	Naming string
+
+	// the types are also declared with this prefix and suffix around their
+	// name, "Vk" keeps the names of the C API (vk::VkBuffer)
+	TypePrefix string
+	TypeSuffix string
+
+	// the C++ standard the header may assume: 11, 14, 17 or 20. Features of
+	// later standards are left out or, where a feature test macro exists,
+	// used if available.
+	CppStd int
+
+	// banner atop generated files: a text file, copyright holder and SPDX
+	// license identifier, see newBanner
+	BannerFile string
+	Copyright  string
+	License    string
+
+	// also name the generator and spec versions and the command line in
+	// the banner, and the time with Timestamp, see metadataLines
+	Metadata  bool
+	Timestamp bool
+
+	// directory of *.tmpl files replacing or adding to the built-in
+	// templates, see loadTemplates
+	TemplatesDir string
+
+	// directory keeping the analysis of the registry and the generated text
+	// of single entities across runs
+	CacheDir string
+
+	// files written besides the header, "" for none, and the header itself,
+	// to STDOUT if OutputFile is ""
+	OutputFile        string
+	CHeaderFile       string
+	FwdHeaderFile     string
+	ReflectHeaderFile string
+	MockFile          string
+	MarkdownFile      string
+
+	// dumps of the registry as resolved for generation, see writeIR, and
+	// of the dependency graph, see writeGraph
+	IRFile    string
+	GraphFile string
+
+	// JSON file of the diagnostics, see writeReport, and failing the run if
+	// anything was skipped or degraded
+	ReportFile string
+	Strict     bool
+
+	// generate everything, but write nothing
+	CheckOnly bool
+
+	// C++ compiler compiling a source including the header, see
+	// compileCheck
+	CompileCheck string
+
+	// compare the header with this file instead of writing it, see
+	// diffHeader
+	DiffFile string
+
+	// pass C and C++ output through clang-format, the executable run
+	Format      formatFlag
+	ClangFormat string
+
+	// JSON file of options, see loadConfig
+	ConfigFile string
+
+	// generate again whenever the inputs change, see watch
+	Watch bool
+}
+
+// NewOptions returns the options of the command line with nothing given.
+func NewOptions() *Options {
+	return &Options{
+		Provisional:     true,
+		Naming:          "camel",
+		Lang:            "c++",
+		Namespace:       "vk",
+		VulkanHeader:    "<vulkan/vulkan.h>",
+		CppStd:          11,
+		EnumStringTable: 64,
+		ClangFormat:     "clang-format",
+		VersionMembers: listFlag{
+			"apiVersion",
+			"driverVersion",
+			"applicationVersion",
+			"engineVersion",
+		},
+	}
+}
+
+// RegisterFlags defines the command line flags setting o in fs, their
+// defaults are the values of o.
+func (o *Options) RegisterFlags(fs *flag.FlagSet) {
+	fs.Var(&o.VersionMembers, "version-members", "Comma-separated list of struct members holding packed versions")
+	fs.BoolVar(&o.Provisional, "provisional", o.Provisional, "Include provisional extensions, guarded by VK_ENABLE_BETA_EXTENSIONS")
+	fs.BoolVar(&o.PullInTypes, "pull-in-types", false, "Pull filtered out types back in when something references them, instead of excluding the referencing entity")
+	fs.BoolVar(&o.SkipBroken, "skip-broken", false, "Skip malformed entities and everything depending on them, rather than failing (unsupported ones are always skipped)")
+	fs.StringVar(&o.SpecVersion, "spec-version", "", "Fetch vk.xml of this Vulkan-Docs tag (e.g. v1.3.280) instead of reading <spec_file>")
+	fs.StringVar(&o.SpecURL, "spec-url", "", "Fetch vk.xml from this URL instead of reading <spec_file>")
+	fs.StringVar(&o.SpecCache, "spec-cache", "", "Directory to cache fetched specs in, defaults to the user cache directory")
+	fs.Var(&o.Extensions, "extensions", "Comma-separated list of extensions to generate, all by default, extensions they depend on are added")
+	fs.Var(&o.ExcludeExtensions, "exclude-extensions", "Comma-separated list of extensions not to generate, extensions depending on them are left out too")
+	fs.Var(&o.Platforms, "platforms", "Comma-separated list of platforms (xlib, win32, ...) to generate extensions for, all by default")
+	fs.StringVar(&o.PlatformSetup, "platform-setup", "", "Set up platform headers of selected platforms: define (VK_USE_PLATFORM_* macros) or include (vulkan_*.h headers)")
+	fs.IntVar(&o.EnumStringTable, "enum-string-table", o.EnumStringTable, "Use a sorted lookup table in getEnumString for sparse enums with at least this many values, 0 to always use a switch (dense enums always index an array)")
+	fs.StringVar(&o.ExportMacro, "export-macro", "", "Emit a DLL export/import macro with this name (e.g. VKGEN_API), define <name>_EXPORTS when building the library and <name>_SHARED when using it")
+	fs.Var(&o.Defines, "define", "Comma-separated list of NAME or NAME=VALUE macros to define before including vulkan.h")
+	fs.Var(&o.Includes, "include", "Comma-separated list of extra headers to include after vulkan.h, <foo.h> or foo.h")
+	fs.Var(&o.EpilogueIncludes, "epilogue-include", "Comma-separated list of extra headers to include at the end of the generated header")
+	fs.BoolVar(&o.UniqueHandles, "unique-handles", false, "Generate move-only Unique* handle wrappers calling the destroy command in their destructor")
+	fs.BoolVar(&o.Exceptions, "exceptions", false, "Throw vk::Error from commands returning an error code, define VKGEN_NO_EXCEPTIONS or build with -fno-exceptions to turn it off at compile time")
+	fs.BoolVar(&o.AggregateStructs, "aggregate-structs", false, "Generate structs as aggregates with public members, to be filled with designated initializers (C++20), instead of setters")
+	fs.BoolVar(&o.SafeStructs, "safe-structs", false, "Generate vk::safe structs deep-copying the arrays, strings and pNext chains they point to, for keeping create infos beyond a call")
+	fs.BoolVar(&o.DynamicDispatch, "dynamic-dispatch", false, "Generate vk::DispatchLoaderDynamic loading the commands at runtime and command overloads taking it, define VK_NO_PROTOTYPES to leave out the statically linked commands")
+	fs.StringVar(&o.Lang, "lang", o.Lang, "Language to generate: c++, c for a C header of enum, struct and loader helpers, csharp for C# P/Invoke bindings, python for a Python ctypes module, rust for Rust bindings or zig for Zig bindings")
+	fs.BoolVar(&o.Module, "module", false, "Write a C++20 module interface unit (vk.cppm) exporting the vk namespace instead of a header")
+	fs.Var(&o.Only, "only", "Comma-separated list of header sections to generate: enums, handles, structs, commands, the sections they need are added")
+	fs.Var(&o.Skip, "skip", "Comma-separated list of header sections to leave out: enums, handles, structs, commands")
+	fs.StringVar(&o.SplitDir, "split", "", "Write the header split into vk_enums.hpp, vk_handles.hpp, vk_structs.hpp and vk_funcs.hpp to this directory, each including the one before it")
+	fs.StringVar(&o.IncludeGuard, "include-guard", "", "Guard the headers with #ifndef <macro> instead of #pragma once, split parts and the C and forward declaration headers append _ENUMS, _C, _FWD, etc. to it")
+	fs.BoolVar(&o.VersionNamespace, "version-namespace", o.VersionNamespace, "Generate into an inline namespace named after the spec version (vk::v1_3_280), so that code built against different specs doesn't link together")
+	fs.StringVar(&o.Namespace, "namespace", o.Namespace, "Namespace to generate the C++ header in, nested namespaces (acme::vk) need -cpp-std 17")
+	fs.StringVar(&o.VulkanHeader, "vulkan-include", o.VulkanHeader, "Header to include for the Vulkan C API instead of <vulkan/vulkan.h>, <foo.h> or foo.h")
+	fs.StringVar(&o.Naming, "naming", o.Naming, "Naming convention of functions, struct members and enum values: camel (createBuffer, eTransferSrc) or snake (create_buffer, e_transfer_src)")
+	fs.StringVar(&o.TypePrefix, "type-prefix", "", "Also declare the types with this prefix instead of the stripped Vk, Vk keeps the C names (vk::VkBuffer)")
+	fs.StringVar(&o.TypeSuffix, "type-suffix", "", "Also declare the types with this suffix (vk::BufferCpp)")
+	fs.IntVar(&o.CppStd, "cpp-std", o.CppStd, "C++ standard the header targets: 11, 14, 17 (adds [[nodiscard]]) or 20 (std::span, concepts), a module is always C++20")
+	fs.StringVar(&o.BannerFile, "banner", "", "Emit the text of this file as a comment atop every generated file, followed by the registry copyright")
+	fs.StringVar(&o.Copyright, "copyright", "", "Emit a copyright line for this holder (e.g. \"2024 ACME Inc.\") atop every generated file")
+	fs.StringVar(&o.License, "license", "", "Emit this SPDX license identifier (e.g. MIT) atop every generated file")
+	fs.BoolVar(&o.Metadata, "metadata", false, "Emit the generator version, VK_HEADER_VERSION of the spec and the command line atop every generated file")
+	fs.BoolVar(&o.Timestamp, "timestamp", false, "With -metadata also emit the time of generation, $SOURCE_DATE_EPOCH if it's set")
+	fs.StringVar(&o.TemplatesDir, "templates", "", "Directory of *.tmpl files defining templates which replace the built-in ones (handle, struct, command, ...) or fill in headerextra, handleextra and structextra")
+	fs.StringVar(&o.CacheDir, "cache", "", "Directory to cache the analysis of the spec and the generated text of structs, commands, etc. in, reused while they, the templates and the generator are unchanged")
+	fs.Var(&o.SerializeStructs, "serialize-structs", "Comma-separated list of structs to generate blob serialization for")
+	fs.Var(&o.JSONStructs, "json-structs", "Comma-separated list of structs to generate nlohmann::json style to_json/from_json functions for")
+	fs.StringVar(&o.OutputFile, "o", "", "Write output to file instead of STDOUT")
+	fs.StringVar(&o.CHeaderFile, "c-header", "", "Also write a C header (constants, loader table) to file, the C++ header includes it")
+	fs.StringVar(&o.FwdHeaderFile, "fwd-header", "", "Also write a header declaring the types (vk_fwd.hpp) to file, for headers which don't need their definitions")
+	fs.StringVar(&o.ReflectHeaderFile, "reflect-header", "", "Also write a header of runtime metadata (vk_reflect.hpp) to file: the structs with their members' names, types and offsets, the enums with their values and the commands' signatures")
+	fs.StringVar(&o.MockFile, "mock", "", "Also write a C++ source (vk_mock.cpp) defining every command as a stub recording its calls and fabricating handles, to link tests against instead of the Vulkan loader")
+	fs.StringVar(&o.MarkdownFile, "markdown", "", "Also write a Markdown reference of the generated types and commands to file, linking to the Vulkan man pages")
+	fs.StringVar(&o.IRFile, "dump-ir", "", "Write the registry as resolved for generation (handles, enums with their values, structs, commands, ...) to this JSON file")
+	fs.StringVar(&o.GraphFile, "dump-graph", "", "Write the dependency graph of the generated structs, handles and commands, grouped by the version or extension requiring them, to this GraphViz file")
+	fs.StringVar(&o.ReportFile, "report", "", "Write the registry entities which were skipped, degraded, excluded or ignored and why to this JSON file")
+	fs.BoolVar(&o.Strict, "strict", false, "Fail if a registry entity was skipped or generated in part, rather than only excluded by the options")
+	fs.BoolVar(&o.CheckOnly, "check", false, "Parse the spec and generate everything into the void to report problems, without writing any file")
+	fs.StringVar(&o.CompileCheck, "compile-check", "", "Also compile a source file including the generated header with this C++ compiler (clang++, g++) to check that it builds")
+	fs.StringVar(&o.DiffFile, "diff", "", "Compare the generated C++ header with this file instead of writing it, list the declarations which differ and exit with 1 if any do")
+	fs.Var(&o.Format, "format", "Pass the C and C++ headers through clang-format, with the .clang-format file given as -format=<file> or else the one found from the output file")
+	fs.StringVar(&o.ClangFormat, "clang-format", o.ClangFormat, "The clang-format executable -format runs, looked up on PATH if it has no directory")
+	fs.StringVar(&o.ConfigFile, "config", "", "Read options from this JSON file, options given on the command line override it")
+	fs.BoolVar(&o.Watch, "watch", false, "Keep running and generate again whenever the spec files, -config, -banner or the -templates directory change")
+}
+
+// check reports options which can't be generated, whatever the output. A
+// module is always C++20, check sets CppStd for it.
+func (o *Options) check() error {
+	if _, err := newNamingPolicy(o.Naming); err != nil {
+		return err
+	}
+	switch o.CppStd {
+	case 11, 14, 17, 20:
+	default:
+		return fmt.Errorf("-cpp-std %d isn't supported, want 11, 14, 17 or 20", o.CppStd)
+	}
+	if _, ok := languageBackends[o.Lang]; !ok {
+		return fmt.Errorf("-lang %s isn't supported, want one of %s", o.Lang, strings.Join(languages(), ", "))
+	}
+	if o.Module {
+		// modules are C++20 anyway
+		o.CppStd = 20
+	}
+	if _, err := selectSections(o.Only, o.Skip); err != nil {
+		return err
+	}
+	if !isNamespace(o.Namespace) {
+		return fmt.Errorf("-namespace %q isn't a valid C++ namespace", o.Namespace)
+	}
+	if strings.Contains(o.Namespace, "::") && o.CppStd < 17 {
+		return fmt.Errorf("-namespace %s needs -cpp-std 17, nested namespaces can't be opened at once before", o.Namespace)
+	}
+	if o.IncludeGuard != "" {
+		switch {
+		case o.Module:
+			return fmt.Errorf("-include-guard can't be used with -module, a module isn't included")
+		case !isIdentifier(o.IncludeGuard):
+			return fmt.Errorf("-include-guard %q isn't a valid macro name", o.IncludeGuard)
+		}
+	}
+	if o.Module && len(o.EpilogueIncludes) > 0 {
+		return fmt.Errorf("-epilogue-include can't be used with -module, nothing can be included after the module declaration")
+	}
+	return nil
+}
+
+// extensionExcluded returns why the extension is not generated, or "" if it
+// is.
+func (o *Options) extensionExcluded(e *registry.Extension) string {
+	if why := o.extensionUnavailable(e); why != "" {
+		return why
+	}
+	if len(o.Extensions) > 0 && !o.Extensions.contains(e.Name) {
+		return "unselected extension " + e.Name
+	}
+	return ""
+}
+
+// extensionUnavailable returns why the extension can't be generated
+// regardless of the -extensions whitelist, or "" if it can.
+func (o *Options) extensionUnavailable(e *registry.Extension) string {
+	switch {
+	case o.ExcludeExtensions.contains(e.Name):
+		return "excluded extension " + e.Name
+	case e.Supported == "disabled":
+		return "disabled extension " + e.Name
+	case !registry.HasAPI(e.Supported, generatedAPI):
+		return fmt.Sprintf("extension %s for %s", e.Name, e.Supported)
+	case e.Provisional && !o.Provisional:
+		return "provisional extension " + e.Name
+	case e.Platform != "" && !e.Provisional && len(o.Platforms) > 0 && !o.Platforms.contains(e.Platform):
+		return fmt.Sprintf("extension %s for platform %s", e.Name, e.Platform)
+	}
+	return ""
+}
+
+func (o *Options) isVersionMember(name string) bool {
+	for _, m := range o.VersionMembers {
+		if m == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package cppgen
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// TestOutputIndependentOfRegistryOrder checks that moving types, enums,
+// commands and extensions around in vk.xml, and the types and commands
+// features and extensions require, doesn't change the header. The values
+// an extension adds to enums keep their order.
+func TestOutputIndependentOfRegistryOrder(t *testing.T) {
+	want := generateTestHeader(t, testSpec, nil)
+	for seed := int64(1); seed <= 5; seed++ {
+		reg, err := registry.ReadFile(testSpec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := rand.New(rand.NewSource(seed))
+		r.Shuffle(len(reg.Types.Type), func(i, j int) {
+			reg.Types.Type[i], reg.Types.Type[j] = reg.Types.Type[j], reg.Types.Type[i]
+		})
+		r.Shuffle(len(reg.Enums), func(i, j int) {
+			reg.Enums[i], reg.Enums[j] = reg.Enums[j], reg.Enums[i]
+		})
+		r.Shuffle(len(reg.Commands.Command), func(i, j int) {
+			reg.Commands.Command[i], reg.Commands.Command[j] = reg.Commands.Command[j], reg.Commands.Command[i]
+		})
+		r.Shuffle(len(reg.Extensions.Extension), func(i, j int) {
+			reg.Extensions.Extension[i], reg.Extensions.Extension[j] = reg.Extensions.Extension[j], reg.Extensions.Extension[i]
+		})
+		shuffleRequire := func(req *registry.Require) {
+			r.Shuffle(len(req.Types), func(i, j int) { req.Types[i], req.Types[j] = req.Types[j], req.Types[i] })
+			r.Shuffle(len(req.Commands), func(i, j int) { req.Commands[i], req.Commands[j] = req.Commands[j], req.Commands[i] })
+		}
+		for i := range reg.Features {
+			shuffleRequire(&reg.Features[i].Require)
+		}
+		for i := range reg.Extensions.Extension {
+			shuffleRequire(&reg.Extensions.Extension[i].Require)
+		}
+		if got := generateHeader(t, reg, NewOptions()); !bytes.Equal(got, want) {
+			t.Errorf("the header changes with the registry shuffled with seed %d", seed)
+		}
+	}
+}
+
+// TestContextOrder checks the documented order of the generated entities:
+// by name, structs after the structs they contain.
+func TestContextOrder(t *testing.T) {
+	g, reg := readTestRegistry(t, NewOptions())
+	ctx := g.newContext(reg)
+
+	names := func(n int, name func(i int) string) []string {
+		s := make([]string, n)
+		for i := range s {
+			s[i] = name(i)
+		}
+		return s
+	}
+	for _, c := range []struct {
+		what  string
+		names []string
+	}{
+		{"enums", names(len(ctx.Enums), func(i int) string { return ctx.Enums[i].Name })},
+		{"bitmasks", names(len(ctx.BitMasks), func(i int) string { return ctx.BitMasks[i].Name })},
+		{"handles", names(len(ctx.Handles), func(i int) string { return ctx.Handles[i].Name })},
+		{"commands", names(len(ctx.Commands), func(i int) string { return ctx.Commands[i].Name })},
+	} {
+		if len(c.names) == 0 {
+			t.Errorf("no %s generated", c.what)
+		}
+		if !sort.StringsAreSorted(c.names) {
+			t.Errorf("%s aren't sorted by name: %q", c.what, c.names)
+		}
+	}
+
+	structs := map[string]bool{}
+	for _, s := range ctx.Structs {
+		structs[s.VkName] = true
+	}
+	seen := map[string]bool{}
+	for _, s := range ctx.Structs {
+		for _, m := range s.Members {
+			if dep := m.AnalyzedType.Type; dep != s.VkName && structs[dep] && !seen[dep] {
+				t.Errorf("struct %s comes before %s, which it refers to", s.VkName, dep)
+			}
+		}
+		seen[s.VkName] = true
+	}
+}
@@ -0,0 +1,121 @@
+package cppgen
+
+import "github.com/nsf/vulkangen/registry"
+
+// resolvePlatforms fills in protect macros of extensions which name their
+// platform instead of the macro, as newer specs do.
+func resolvePlatforms(reg *registry.Registry, diags *diagnostics) {
+	protect := map[string]string{}
+	for _, p := range reg.Platforms.Platform {
+		protect[p.Name] = p.Protect
+	}
+	for i := range reg.Extensions.Extension {
+		e := &reg.Extensions.Extension[i]
+		if e.Protect != "" || e.Platform == "" {
+			continue
+		}
+		if m, ok := protect[e.Platform]; ok {
+			e.Protect = m
+		} else {
+			diags.diagnose("degraded", e.Name, "unknown platform %s of extension %s", e.Platform, e.Name)
+		}
+	}
+}
+
+// platformHeader returns the vulkan.h sub-header declaring extensions of a
+// platform.
+func platformHeader(name string) string {
+	if name == "provisional" {
+		return "<vulkan/vulkan_beta.h>"
+	}
+	return "<vulkan/vulkan_" + name + ".h>"
+}
+
+// windowSystemHeaders returns headers declaring the native types (Display,
+// HWND, ...) the extension needs, vk.xml lists them in the "requires"
+// attribute of such types.
+func windowSystemHeaders(reg *registry.Registry, e *registry.Extension) []string {
+	native := map[string]string{}
+	members := map[string][]registry.TypeName{}
+	for _, t := range reg.Types.Type {
+		if t.Category == "" && t.Requires != "" && t.Requires != "vk_platform" {
+			native[t.Name] = t.Requires
+		}
+		if t.Category == "struct" || t.Category == "union" {
+			members[t.Name] = t.Members
+		}
+	}
+	params := map[string][]registry.TypeName{}
+	for _, c := range reg.Commands.Command {
+		params[c.Proto.Name] = c.Params
+	}
+
+	var headers []string
+	seen := map[string]bool{}
+	add := func(refs []registry.TypeName) {
+		for _, r := range refs {
+			h, ok := native[r.Type]
+			if !ok || seen[h] {
+				continue
+			}
+			seen[h] = true
+			headers = append(headers, "<"+h+">")
+		}
+	}
+	for _, t := range e.Require.Types {
+		add(members[t.Name])
+	}
+	for _, c := range e.Require.Commands {
+		add(params[c.Name])
+	}
+	return headers
+}
+
+// setupPlatforms adds whatever opts.PlatformSetup asks for to the header
+// prologue, based on the platforms of generated extensions. In "include" mode
+// the extensions become unguarded, their declarations come from the platform
+// headers included directly.
+func setupPlatforms(hp *HeaderParams, reg *registry.Registry, opts *Options) {
+	switch opts.PlatformSetup {
+	case "":
+		return
+	case "define", "include":
+	default:
+		fatalf(exitUsage, "unknown platform setup mode %q, expected define or include", opts.PlatformSetup)
+	}
+
+	var platforms []registry.Platform
+	selected := map[string][]*registry.Extension{}
+	for i := range reg.Extensions.Extension {
+		e := &reg.Extensions.Extension[i]
+		if e.Platform == "" || opts.extensionExcluded(e) != "" {
+			continue
+		}
+		selected[e.Platform] = append(selected[e.Platform], e)
+	}
+	for _, p := range reg.Platforms.Platform {
+		if len(selected[p.Name]) > 0 {
+			platforms = append(platforms, p)
+		}
+	}
+
+	var includes []string
+	seen := map[string]bool{}
+	for _, p := range platforms {
+		if opts.PlatformSetup == "define" {
+			hp.Defines = append(hp.Defines, Define{Name: p.Protect})
+			continue
+		}
+		for _, e := range selected[p.Name] {
+			e.Unguarded = true
+			for _, h := range windowSystemHeaders(reg, e) {
+				if !seen[h] {
+					seen[h] = true
+					includes = append(includes, h)
+				}
+			}
+		}
+		includes = append(includes, platformHeader(p.Name))
+	}
+	hp.Includes = append(hp.Includes, includes...)
+}
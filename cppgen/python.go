@@ -0,0 +1,297 @@
+package cppgen
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PythonModule is the Python module of -lang python: ctypes structures and
+// unions, IntEnums and IntFlags, the function prototypes of the commands
+// and a table loading them from the Vulkan loader. Names are those of the
+// C++ header, constants lose their VK_ prefix.
+type PythonModule struct {
+	Banner    []string
+	Constants []RustConstant
+	Handles   []PythonType
+	Enums     []PythonEnum
+	Structs   []PythonStruct
+	Aliases   []Alias
+	Commands  []PythonCommand
+
+	// C types of the registry which aren't generated, replaced with void
+	// behind pointers and leaving out what uses them by value
+	Missing []string
+}
+
+// PythonType is a name given to a ctypes type, Bits is the width of
+// bit-field members.
+type PythonType struct {
+	Name string
+	Type string
+	Bits int
+}
+
+// PythonEnum is an IntEnum or, if Flags, an IntFlag. FlagBits is the name
+// of the enum of bits of a bitmask.
+type PythonEnum struct {
+	Name     string
+	Flags    bool
+	FlagBits string
+	Values   []RustConstant
+}
+
+type PythonStruct struct {
+	Name   string
+	Union  bool
+	Fields []PythonType
+
+	// the StructureType value sType is set to by default, "" for structs
+	// without sType
+	SType string
+}
+
+type PythonCommand struct {
+	VkName string
+	Name   string
+	Params []PythonType
+	Ret    string
+}
+
+// pythonKeywords are the keywords of Python, names like them get a _
+// appended.
+var pythonKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true,
+	"assert": true, "async": true, "await": true, "break": true,
+	"class": true, "continue": true, "def": true, "del": true, "elif": true,
+	"else": true, "except": true, "finally": true, "for": true,
+	"from": true, "global": true, "if": true, "import": true, "in": true,
+	"is": true, "lambda": true, "nonlocal": true, "not": true, "or": true,
+	"pass": true, "raise": true, "return": true, "try": true,
+	"while": true, "with": true, "yield": true,
+}
+
+// pythonCTypes are the C types vk.xml uses and their ctypes equivalents,
+// void only behind pointers.
+var pythonCTypes = map[string]string{
+	"void":     "None",
+	"char":     "ctypes.c_char",
+	"int":      "ctypes.c_int",
+	"float":    "ctypes.c_float",
+	"double":   "ctypes.c_double",
+	"size_t":   "ctypes.c_size_t",
+	"int8_t":   "ctypes.c_int8",
+	"uint8_t":  "ctypes.c_uint8",
+	"int16_t":  "ctypes.c_int16",
+	"uint16_t": "ctypes.c_uint16",
+	"int32_t":  "ctypes.c_int32",
+	"uint32_t": "ctypes.c_uint32",
+	"int64_t":  "ctypes.c_int64",
+	"uint64_t": "ctypes.c_uint64",
+
+	// the basetypes of vk.xml
+	"VkBool32":        "Bool32",
+	"VkFlags":         "Flags",
+	"VkFlags64":       "Flags64",
+	"VkDeviceSize":    "DeviceSize",
+	"VkDeviceAddress": "DeviceAddress",
+	"VkSampleMask":    "SampleMask",
+
+	"PFN_vkVoidFunction": "ctypes.c_void_p",
+}
+
+// pythonIdent makes an identifier of name, which mustn't be a keyword.
+func pythonIdent(name string) string {
+	if pythonKeywords[name] {
+		return name + "_"
+	}
+	return name
+}
+
+// pythonTypes maps the C types to the ctypes types generated for them.
+type pythonTypes struct {
+	names   map[string]string
+	missing map[string]bool
+}
+
+// pythonType returns the ctypes type of a member or parameter of C type
+// ct, arrays of parameters are passed as pointers. ok is false if its type
+// is used by value but isn't generated.
+func (pt *pythonTypes) pythonType(ct CType, param bool) (string, bool) {
+	t, ok := pt.names[ct.Type]
+	switch {
+	case !ok && strings.HasPrefix(ct.Type, "PFN_"):
+		// all function pointers are passed the same way, their signatures
+		// aren't generated
+		t, ok = "ctypes.c_void_p", true
+	case !ok:
+		pt.missing[ct.Type] = true
+		t = "None"
+	}
+	dims, pointers := ct.Dims, len(ct.Pointers)
+	if len(dims) > 0 && param {
+		// decayed to a pointer in C
+		dims, pointers = nil, pointers+1
+	}
+	for i := 0; i < pointers; i++ {
+		switch {
+		case i == 0 && t == "None":
+			t = "ctypes.c_void_p"
+		case i == 0 && ct.Type == "char" && ct.Const:
+			t = "ctypes.c_char_p"
+		default:
+			t = "ctypes.POINTER(" + t + ")"
+		}
+	}
+	for i := len(dims) - 1; i >= 0; i-- {
+		t = fmt.Sprintf("(%s * %s)", t, strings.TrimPrefix(dims[i], "VK_"))
+	}
+	return t, ok || pointers > 0
+}
+
+// pythonConstant converts an API constant, ok is false if its value isn't
+// a C literal it knows.
+func pythonConstant(name, value string) (RustConstant, bool) {
+	c, ok := rustConstant(name, value)
+	if !ok {
+		return c, false
+	}
+	if strings.HasPrefix(c.Value, "!") {
+		n, _ := strconv.ParseUint(c.Value[1:], 0, 64)
+		if c.Type == "u64" {
+			c.Value = fmt.Sprintf("0x%x", ^n)
+		} else {
+			c.Value = fmt.Sprintf("0x%x", ^uint32(n))
+		}
+	}
+	return c, true
+}
+
+func newPythonModule(params *HeaderParams, ctx *Context) *PythonModule {
+	m := &PythonModule{Banner: params.Banner}
+	pt := &pythonTypes{names: map[string]string{}, missing: map[string]bool{}}
+	for k, v := range pythonCTypes {
+		pt.names[k] = v
+	}
+	for _, c := range ctx.Constants {
+		if pc, ok := pythonConstant(c.Name, c.Value); ok {
+			m.Constants = append(m.Constants, pc)
+		}
+	}
+	for _, h := range ctx.Handles {
+		pt.names[h.VkName] = h.Name
+		typ := "ctypes.c_uint64"
+		if h.TypeSafe {
+			typ = "ctypes.c_void_p"
+		}
+		m.Handles = append(m.Handles, PythonType{Name: h.Name, Type: typ})
+	}
+	values := func(e *Enum, bits bool) []RustConstant {
+		var out []RustConstant
+		for _, v := range e.Values {
+			if !v.HasNumber {
+				continue
+			}
+			value := fmt.Sprint(v.Number)
+			if bits {
+				value = fmt.Sprintf("0x%x", uint64(v.Number))
+			}
+			out = append(out, RustConstant{Name: pythonIdent(v.Name), Value: value})
+		}
+		return out
+	}
+	bitEnums := map[string]bool{}
+	for _, bm := range ctx.BitMasks {
+		// the fields are plain integers, the enums are for building and
+		// reading them
+		flags := "Flags"
+		if bm.BitWidth == 64 {
+			flags = "Flags64"
+		}
+		pt.names[bm.VkName] = flags
+		pe := PythonEnum{Name: bm.Name, Flags: true}
+		if bm.Enum != nil && bm.Enum.VkName != "" {
+			bitEnums[bm.Enum.VkName] = true
+			pt.names[bm.Enum.VkName] = flags
+			pe.FlagBits = bm.Enum.Name
+			pe.Values = values(bm.Enum, true)
+		}
+		m.Enums = append(m.Enums, pe)
+	}
+	for i := range ctx.Enums {
+		e := &ctx.Enums[i]
+		if bitEnums[e.VkName] {
+			continue
+		}
+		pt.names[e.VkName] = "ctypes.c_int32"
+		m.Enums = append(m.Enums, PythonEnum{Name: e.Name, Values: values(e, false)})
+	}
+	// the structs are declared before their fields are set, they may
+	// point to each other in any order
+	for _, s := range ctx.Structs {
+		ps := PythonStruct{Name: s.Name, Union: s.Union}
+		ok := true
+		for _, mem := range s.Members {
+			t, known := pt.pythonType(mem.AnalyzedType.CType, false)
+			if !known {
+				ok = false
+				break
+			}
+			ps.Fields = append(ps.Fields, PythonType{Name: mem.Name, Type: t, Bits: mem.AnalyzedType.BitWidth})
+		}
+		if !ok {
+			continue
+		}
+		if s.HasSType {
+			ps.SType = pythonIdent(s.SType)
+		}
+		pt.names[s.VkName] = ps.Name
+		m.Structs = append(m.Structs, ps)
+	}
+	for _, a := range ctx.TypeAliases {
+		if vk := "Vk" + a.Target; pt.names[vk] != "" {
+			pt.names["Vk"+a.Name] = pt.names[vk]
+			m.Aliases = append(m.Aliases, a)
+		}
+	}
+	for _, c := range ctx.Commands {
+		pc := PythonCommand{VkName: c.VkName, Name: c.Name, Ret: "None"}
+		ok := true
+		for _, p := range c.Parameters {
+			t, known := pt.pythonType(p.AnalyzedType.CType, true)
+			if !known {
+				ok = false
+				break
+			}
+			pc.Params = append(pc.Params, PythonType{Name: p.Name, Type: t})
+		}
+		if c.RetVkType != "void" {
+			t, known := pt.pythonType(CType{Type: c.RetVkType}, false)
+			ok = ok && known
+			pc.Ret = t
+		}
+		if ok {
+			m.Commands = append(m.Commands, pc)
+		}
+	}
+	for t := range pt.missing {
+		m.Missing = append(m.Missing, t)
+	}
+	sort.Strings(m.Missing)
+	return m
+}
+
+// pythonBackend is -lang python.
+type pythonBackend struct{}
+
+func init() {
+	RegisterBackend(pythonBackend{})
+}
+
+func (pythonBackend) Name() string { return "python" }
+
+func (pythonBackend) Generate(w io.Writer, ctx *Context, opts Options) error {
+	return ctx.execute(w, "python", newPythonModule(ctx.Params, ctx))
+}
@@ -0,0 +1,196 @@
+package cppgen
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// unsupportedEntities reports registry entities which are valid, but the
+// generator can't handle. They are excluded together with their dependents
+// with or without -skip-broken, which only decides about broken ones.
+func unsupportedEntities(reg *registry.Registry) []*ValidationError {
+	var v registryValidator
+	for _, e := range reg.Enums {
+		if e.BitWidth > 64 && !e.External {
+			v.errorf("enum", e.Name, "unsupported bit width %d", e.BitWidth)
+		}
+	}
+	for _, t := range reg.Types.Type {
+		if t.Alias != "" || t.External {
+			continue
+		}
+		switch t.Category {
+		case "bitmask":
+			if t.InnerType != "VkFlags" && t.InnerType != "VkFlags64" {
+				v.errorf("bitmask", t.InnerName, "unsupported backing type %s", t.InnerType)
+			}
+		}
+	}
+	return v.errs
+}
+
+func xmlTypeEntityName(t *registry.Type) string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.InnerName
+}
+
+func xmlCommandEntityName(c *registry.Command) string {
+	if c.Proto.Name != "" {
+		return c.Proto.Name
+	}
+	return c.Name
+}
+
+// Exclusion is a registry entity removed before generation. Kind is how it's
+// reported, skipped or excluded (see Diagnostic), entities removed because
+// they depend on another one take its kind.
+type Exclusion struct {
+	Entity string
+	Kind   string
+	Reason string
+}
+
+// skipBroken removes the entities reported by errs from the registry,
+// together with everything that depends on them, and returns them all as
+// skipped.
+func skipBroken(reg *registry.Registry, errs []*ValidationError) []Exclusion {
+	roots := map[string]Exclusion{}
+	for _, err := range errs {
+		if _, ok := roots[err.Entity]; !ok {
+			roots[err.Entity] = Exclusion{Entity: err.Entity, Kind: "skipped", Reason: err.Error()}
+		}
+	}
+	return excludeEntities(reg, roots)
+}
+
+// deselectedEntities returns everything required only by extensions opts
+// exclude (name -> exclusion), to be excluded from the registry.
+func deselectedEntities(reg *registry.Registry, opts *Options) map[string]Exclusion {
+	deselected := map[string]string{}
+	required := map[string]bool{}
+	for _, f := range reg.Features {
+		for _, t := range f.Require.Types {
+			required[t.Name] = true
+		}
+		for _, c := range f.Require.Commands {
+			required[c.Name] = true
+		}
+	}
+	for i := range reg.Extensions.Extension {
+		e := &reg.Extensions.Extension[i]
+		why := opts.extensionExcluded(e)
+		for _, t := range e.Require.Types {
+			if why != "" {
+				deselected[t.Name] = why
+			} else {
+				required[t.Name] = true
+			}
+		}
+		for _, c := range e.Require.Commands {
+			if why != "" {
+				deselected[c.Name] = why
+			} else {
+				required[c.Name] = true
+			}
+		}
+	}
+	reasons := map[string]Exclusion{}
+	for name, why := range deselected {
+		if !required[name] {
+			reasons[name] = Exclusion{Entity: name, Kind: "excluded", Reason: fmt.Sprintf("%s: required by %s", name, why)}
+		}
+	}
+	return reasons
+}
+
+// excludeEntities removes the entities of reasons (name -> exclusion) from
+// the registry, together with everything that depends on them (structs
+// containing them, commands using them). Returns all removed entities sorted
+// by name.
+func excludeEntities(reg *registry.Registry, reasons map[string]Exclusion) []Exclusion {
+	dependent := func(name, dep, format string, args ...interface{}) {
+		reasons[name] = Exclusion{Entity: name, Kind: reasons[dep].Kind, Reason: fmt.Sprintf(format, args...)}
+	}
+	// propagate to dependents until nothing changes
+	for changed := true; changed; {
+		changed = false
+		for i := range reg.Types.Type {
+			t := &reg.Types.Type[i]
+			name := xmlTypeEntityName(t)
+			if _, ok := reasons[name]; ok || name == "" {
+				continue
+			}
+			if _, ok := reasons[t.Alias]; ok && t.Alias != "" {
+				dependent(name, t.Alias, "%s %s: alias of skipped %s", t.Category, name, t.Alias)
+				changed = true
+				continue
+			}
+			if t.Category != "struct" && t.Category != "union" {
+				continue
+			}
+			for _, m := range t.Members {
+				if _, ok := reasons[m.Type]; ok {
+					dependent(name, m.Type, "%s %s: member %s depends on skipped %s", t.Category, name, m.Name, m.Type)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	for i := range reg.Commands.Command {
+		c := &reg.Commands.Command[i]
+		name := xmlCommandEntityName(c)
+		if _, ok := reasons[name]; ok || name == "" {
+			continue
+		}
+		if _, ok := reasons[c.Proto.Type]; ok {
+			dependent(name, c.Proto.Type, "command %s: return type depends on skipped %s", name, c.Proto.Type)
+			continue
+		}
+		for _, p := range c.Params {
+			if _, ok := reasons[p.Type]; ok {
+				dependent(name, p.Type, "command %s: parameter %s depends on skipped %s", name, p.Name, p.Type)
+				break
+			}
+		}
+	}
+	for i := range reg.Commands.Command {
+		c := &reg.Commands.Command[i]
+		if _, ok := reasons[c.Alias]; ok && c.Alias != "" {
+			if _, ok := reasons[c.Name]; !ok {
+				dependent(c.Name, c.Alias, "command %s: alias of skipped %s", c.Name, c.Alias)
+			}
+		}
+	}
+
+	types := reg.Types.Type[:0]
+	for _, t := range reg.Types.Type {
+		name := xmlTypeEntityName(&t)
+		if _, ok := reasons[name]; ok || name == "" && t.Category != "" {
+			continue
+		}
+		types = append(types, t)
+	}
+	reg.Types.Type = types
+
+	commands := reg.Commands.Command[:0]
+	for _, c := range reg.Commands.Command {
+		name := xmlCommandEntityName(&c)
+		if _, ok := reasons[name]; ok || c.Proto.Name == "" && c.Alias == "" {
+			continue
+		}
+		commands = append(commands, c)
+	}
+	reg.Commands.Command = commands
+
+	out := make([]Exclusion, 0, len(reasons))
+	for _, r := range reasons {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Entity < out[j].Entity })
+	return out
+}
@@ -0,0 +1,132 @@
+package cppgen
+
+import (
+	"io"
+)
+
+// ReflectHeader is the header of -reflect-header, tables describing the C
+// structs, enums and commands at runtime for tools which handle any of them
+// generically, like inspectors and serializers. It only needs vulkan.h,
+// offsets and sizes are those of the C structs, which the C++ structs share.
+type ReflectHeader struct {
+	GuardBegin   string
+	GuardEnd     string
+	Banner       []string
+	Defines      []Define
+	VulkanHeader string
+	Namespace    string
+
+	Structs  []ReflectStruct
+	Enums    []ReflectEnum
+	Commands []ReflectCommand
+}
+
+type ReflectStruct struct {
+	Protect Protect
+	Name    string
+	VkName  string
+	Union   bool
+	Members []ReflectMember
+
+	// the number of the StructureType value of sType, valid if HasSType
+	SType    int64
+	HasSType bool
+}
+
+// ReflectMember is a struct member or command parameter, Type is its C
+// type with array sizes, uint8_t[VK_UUID_SIZE].
+type ReflectMember struct {
+	Name string
+	Type string
+
+	// len attribute of pointer members
+	Len string
+
+	// width of bit-field members
+	BitWidth int
+}
+
+type ReflectEnum struct {
+	Protect Protect
+	Name    string
+	VkName  string
+	Bits    bool
+	Values  []EnumValue
+}
+
+type ReflectCommand struct {
+	Protect    Protect
+	Name       string
+	VkName     string
+	Return     string
+	Parameters []ReflectMember
+}
+
+func newReflectHeader(params *HeaderParams, ctx *Context) *ReflectHeader {
+	rh := &ReflectHeader{
+		Banner:       params.Banner,
+		Defines:      params.Defines,
+		VulkanHeader: params.VulkanHeader,
+		Namespace:    params.Namespace,
+	}
+	rh.GuardBegin, rh.GuardEnd = includeGuard(guardName(params.IncludeGuard, "REFLECT"), "//")
+	sTypes := map[string]EnumValue{}
+	for _, e := range ctx.Enums {
+		if e.VkName == "VkStructureType" {
+			for _, v := range e.Values {
+				sTypes[v.Name] = v
+			}
+		}
+	}
+	for _, s := range ctx.Structs {
+		rs := ReflectStruct{Protect: s.Protect, Name: s.Name, VkName: s.VkName, Union: s.Union}
+		if v, ok := sTypes[s.SType]; ok && s.HasSType && v.HasNumber {
+			rs.SType, rs.HasSType = v.Number, true
+		}
+		for _, m := range s.Members {
+			rs.Members = append(rs.Members, ReflectMember{
+				Name:     m.Name,
+				Type:     m.AnalyzedType.Declare(m.AnalyzedType.Type, ""),
+				Len:      m.Len,
+				BitWidth: m.AnalyzedType.BitWidth,
+			})
+		}
+		rh.Structs = append(rh.Structs, rs)
+	}
+	bitEnums := map[string]bool{}
+	for _, bm := range ctx.BitMasks {
+		if bm.Enum != nil {
+			bitEnums[bm.Enum.VkName] = true
+		}
+	}
+	for _, e := range ctx.Enums {
+		re := ReflectEnum{Protect: e.Protect, Name: e.Name, VkName: e.VkName, Bits: bitEnums[e.VkName]}
+		for _, v := range e.Values {
+			// values which can't be computed have no number to look up
+			if v.HasNumber {
+				re.Values = append(re.Values, v)
+			}
+		}
+		rh.Enums = append(rh.Enums, re)
+	}
+	for _, c := range ctx.Commands {
+		rc := ReflectCommand{Protect: c.Protect, Name: c.Name, VkName: c.VkName, Return: c.RetVkType}
+		for _, p := range c.Parameters {
+			rc.Parameters = append(rc.Parameters, ReflectMember{Name: p.Name, Type: p.AnalyzedType.Declare(p.AnalyzedType.Type, "")})
+		}
+		rh.Commands = append(rh.Commands, rc)
+	}
+	return rh
+}
+
+func reflectBackend(file string, params *HeaderParams, ctx *Context) backend {
+	rh := newReflectHeader(params, ctx)
+	return backend{
+		name: "reflection header",
+		file: file,
+		lang: "c++",
+		emit: func(w io.Writer) error {
+			return ctx.execute(w, "reflectheader", rh)
+		},
+	}
+}
@@ -0,0 +1,121 @@
+package cppgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// Diagnostic is a registry entity which isn't generated as it is declared.
+// Kind is one of:
+//
+//	skipped   it's broken or unsupported and left out
+//	degraded  it's generated without something it refers to
+//	excluded  the options leave it out
+//	ignored   vulkan.h declares it, the C++ header uses it as it is
+type Diagnostic struct {
+	Kind   string `json:"kind"`
+	Entity string `json:"entity"`
+	Reason string `json:"reason"`
+}
+
+// diagnostics are the Diagnostics of a generation, recorded while the
+// registry is prepared and the context built.
+type diagnostics struct {
+	mu   sync.Mutex
+	list []Diagnostic
+}
+
+// diagnose logs the message and records it for the report.
+func (d *diagnostics) diagnose(kind, entity, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	log.Print(msg)
+	d.record(kind, entity, msg)
+}
+
+func (d *diagnostics) record(kind, entity, reason string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.list = append(d.list, Diagnostic{Kind: kind, Entity: entity, Reason: reason})
+}
+
+func (d *diagnostics) len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.list)
+}
+
+// since returns the diagnostics recorded after the first n.
+func (d *diagnostics) since(n int) []Diagnostic {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Diagnostic(nil), d.list[n:]...)
+}
+
+// recordExclusions logs the entities removed from the registry and records
+// them for the report.
+func (d *diagnostics) recordExclusions(list []Exclusion) {
+	for _, e := range list {
+		log.Print(e.Kind, " ", e.Reason)
+		d.record(e.Kind, e.Entity, e.Reason)
+	}
+}
+
+// recordIgnored records the types the C++ header leaves to vulkan.h.
+func (d *diagnostics) recordIgnored(reg *registry.Registry) {
+	for _, t := range reg.Types.Type {
+		if t.External || t.Alias != "" {
+			continue
+		}
+		switch t.Category {
+		case "define", "include":
+			d.record("ignored", xmlTypeEntityName(&t), t.Category+" is left to vulkan.h")
+		case "basetype", "funcpointer":
+			d.record("ignored", xmlTypeEntityName(&t), t.Category+" is used as the C type")
+		}
+	}
+}
+
+// writeReport writes the diagnostics recorded so far to file as JSON,
+// sorted by kind and entity.
+func (d *diagnostics) writeReport(file string) error {
+	d.mu.Lock()
+	list := append([]Diagnostic(nil), d.list...)
+	d.mu.Unlock()
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Kind != list[j].Kind {
+			return list[i].Kind < list[j].Kind
+		}
+		return list[i].Entity < list[j].Entity
+	})
+	counts := map[string]int{}
+	for _, diag := range list {
+		counts[diag.Kind]++
+	}
+	data, err := json.MarshalIndent(struct {
+		Counts      map[string]int `json:"counts"`
+		Diagnostics []Diagnostic   `json:"diagnostics"`
+	}{counts, list}, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0644)
+}
+
+// strictFailures counts the skipped and degraded entities.
+func (d *diagnostics) strictFailures() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n := 0
+	for _, diag := range d.list {
+		if diag.Kind == "skipped" || diag.Kind == "degraded" {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,162 @@
+package cppgen
+
+import (
+	"strings"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// ValueReturn describes the overload of a command returning its trailing
+// output parameter instead of taking it. It is either a single value
+// (vkCreateBuffer's pBuffer) or, for commands following the two-call
+// enumeration idiom (vkEnumeratePhysicalDevices), a std::vector of the
+// elements, filled after querying their count. Parameters are the ones the
+// overload takes, Type is the C++ type it returns the output as, Count is the
+// type of the count parameter of enumerations.
+//
+// The overload returns ResultValue<Type> for commands returning Result. It
+// returns the value only for void commands (Plain) and, unless
+// VKGEN_NO_EXCEPTIONS is defined, for commands which throw on errors and
+// have no success code but VK_SUCCESS, or VK_INCOMPLETE for enumerations,
+// which are retried until complete (Checked).
+type ValueReturn struct {
+	Command    string
+	Type       string
+	Count      string
+	Parameters []CommandParameter
+	Plain      bool
+	Checked    bool
+	Throws     bool
+
+	// the Plain overload also returns Type chained with the structs
+	// extending it, see resolveChainQueries
+	Chain bool
+}
+
+// isOutPointer tells if at is a plain T *, the type commands return values
+// through.
+func isOutPointer(at *AnalyzedType) bool {
+	return len(at.Pointers) == 1 && !at.IsConst && !at.IsArray
+}
+
+// newValueReturn returns the value overload of the command, or nil if its
+// last parameter is not a plain output pointer: a non-const pointer to a
+// single value, not the length of another parameter, or to an array whose
+// length is given by the preceding non-const pointer to a count.
+func (ctx *Context) newValueReturn(cmd *Command, c *registry.Command) *ValueReturn {
+	if cmd.RetType != "void" && cmd.RetType != "Result" {
+		return nil
+	}
+	n := len(c.Params)
+	if n == 0 {
+		return nil
+	}
+	out := c.Params[n-1]
+	if !isOutPointer(&cmd.Parameters[n-1].AnalyzedType) || out.Type == "void" {
+		return nil
+	}
+	v := &ValueReturn{
+		Command: cmd.Name,
+		Type:    ctx.names.typeName(CType{Type: out.Type}, true),
+		Plain:   cmd.RetType == "void",
+	}
+	success := map[string]bool{"VK_SUCCESS": true}
+	switch {
+	case out.Len == "":
+		for _, p := range c.Params[:n-1] {
+			if p.Len == out.Name || strings.HasPrefix(p.Len, out.Name+",") {
+				return nil
+			}
+		}
+		v.Parameters = cmd.Parameters[:n-1]
+	case n >= 2 && out.Len == c.Params[n-2].Name:
+		count := c.Params[n-2]
+		if !isOutPointer(&cmd.Parameters[n-2].AnalyzedType) || count.Type != "uint32_t" && count.Type != "size_t" {
+			return nil
+		}
+		v.Type = "std::vector<" + v.Type + ">"
+		v.Count = count.Type
+		v.Parameters = cmd.Parameters[:n-2]
+		success["VK_INCOMPLETE"] = true
+	default:
+		return nil
+	}
+	v.Throws = cmd.Throws
+	v.Checked = cmd.Throws
+	for _, code := range strings.Split(c.SuccessCodes, ",") {
+		if code != "" && !success[code] {
+			v.Checked = false
+		}
+	}
+	return v
+}
+
+// ValueVariant is one of the overloads of a ValueReturn, returning the
+// value alone or a ResultValue.
+type ValueVariant struct {
+	*ValueReturn
+	Result bool
+}
+
+func (v *ValueReturn) Variant(result bool) ValueVariant {
+	return ValueVariant{v, result}
+}
+
+func (v ValueVariant) ReturnType() string {
+	if v.Result {
+		return "ResultValue<" + v.Type + ">"
+	}
+	return v.Type
+}
+
+// Noexcept is the exception specification of the overload. Enumerations
+// fill a std::vector, which may throw, and so does the overload of a
+// checked command returning the value alone. Its ResultValue counterpart is
+// only compiled without exceptions.
+func (v ValueVariant) Noexcept() string {
+	switch {
+	case v.Count != "":
+		return ""
+	case !v.Result:
+		if v.Plain {
+			return "noexcept"
+		}
+		return ""
+	case v.Checked:
+		return "noexcept"
+	}
+	return noexceptSpec(v.Throws)
+}
+
+// Ret, ValueVar and ResultVar name the returned variable, the value and the
+// result in the body of the overload.
+func (v ValueVariant) Ret() string {
+	if v.Result {
+		return "rv"
+	}
+	return "value"
+}
+
+func (v ValueVariant) ValueVar() string {
+	if v.Result {
+		return "rv.value"
+	}
+	return "value"
+}
+
+func (v ValueVariant) ResultVar() string {
+	if v.Result {
+		return "rv.result"
+	}
+	return "result"
+}
+
+// HasResultValues reports whether any value overload returns ResultValue.
+func (ctx *Context) HasResultValues() bool {
+	for _, c := range ctx.Commands {
+		if c.Value != nil && !c.Value.Plain {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,344 @@
+package cppgen
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RustModule is the Rust module of -lang rust: #[repr(C)] structs and
+// unions, enums and bitmasks as newtypes with associated constants, the
+// function pointer types and extern declarations of the commands and a
+// loader table. It uses core only. Names follow the C++ header, with Rust
+// casing: types without the Vk prefix, snake_case members and functions,
+// SCREAMING_SNAKE_CASE values.
+type RustModule struct {
+	Banner    []string
+	Constants []RustConstant
+	Handles   []RustHandle
+	Enums     []RustEnum
+	BitMasks  []RustEnum
+	Structs   []RustStruct
+	Aliases   []Alias
+	Commands  []RustCommand
+
+	// C types of the registry which aren't generated, replaced with c_void
+	// behind pointers and leaving out what uses them by value
+	Missing []string
+}
+
+type RustConstant struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+// RustHandle is a handle, a pointer if Dispatchable and a u64 otherwise.
+type RustHandle struct {
+	Name         string
+	Dispatchable bool
+}
+
+// RustEnum is an enum or a bitmask, the newtype Name over Repr. FlagBits is
+// the name of the enum of bits of a bitmask.
+type RustEnum struct {
+	Name     string
+	Repr     string
+	FlagBits string
+	Values   []RustConstant
+}
+
+type RustStruct struct {
+	Name   string
+	VkName string
+	Union  bool
+	Fields []RustField
+
+	// the StructureType value set by Default, "" for structs without sType
+	SType string
+}
+
+type RustField struct {
+	Name string
+	Type string
+}
+
+type RustCommand struct {
+	VkName string
+	Name   string
+	Params []RustField
+	Ret    string
+}
+
+// rustKeywords are the keywords which are also member or parameter names
+// of the registry, or might be, they are written as raw identifiers.
+var rustKeywords = map[string]bool{
+	"type": true, "ref": true, "fn": true, "mod": true, "impl": true,
+	"match": true, "move": true, "loop": true, "use": true, "where": true,
+	"box": true, "in": true, "self": true, "super": true, "crate": true,
+}
+
+// rustCTypes are the C types vk.xml uses and their Rust equivalents.
+var rustCTypes = map[string]string{
+	"void":     "c_void",
+	"char":     "c_char",
+	"int":      "c_int",
+	"float":    "f32",
+	"double":   "f64",
+	"size_t":   "usize",
+	"int8_t":   "i8",
+	"uint8_t":  "u8",
+	"int16_t":  "i16",
+	"uint16_t": "u16",
+	"int32_t":  "i32",
+	"uint32_t": "u32",
+	"int64_t":  "i64",
+	"uint64_t": "u64",
+
+	// the basetypes of vk.xml
+	"VkBool32":        "Bool32",
+	"VkFlags":         "Flags",
+	"VkFlags64":       "Flags64",
+	"VkDeviceSize":    "DeviceSize",
+	"VkDeviceAddress": "DeviceAddress",
+	"VkSampleMask":    "SampleMask",
+
+	"PFN_vkVoidFunction": "PFN_vkVoidFunction",
+}
+
+// rustEnumValueNaming keeps the words of enum values as they are,
+// TRANSFER_SRC.
+type rustEnumValueNaming struct{ snakeNaming }
+
+func (rustEnumValueNaming) EnumValue(words string) string { return words }
+
+// rustTypes maps the C types to the Rust types generated for them.
+type rustTypes struct {
+	names   map[string]string
+	missing map[string]bool
+}
+
+// rustType returns the Rust type of a member or parameter of C type ct.
+// ok is false if its type is used by value but isn't generated, or it's a
+// bit-field, which Rust has no equivalent of.
+func (rt *rustTypes) rustType(ct CType) (string, bool) {
+	base, ok := rt.names[ct.Type]
+	switch {
+	case !ok && strings.HasPrefix(ct.Type, "PFN_"):
+		// all function pointers are passed the same way, their signatures
+		// aren't generated
+		base, ok = "PFN_vkVoidFunction", true
+	case !ok:
+		rt.missing[ct.Type] = true
+		base = "c_void"
+	}
+	if ct.BitWidth > 0 {
+		return "", false
+	}
+	t := base
+	for _, isConst := range ct.Pointers {
+		if isConst {
+			t = "*const " + t
+		} else {
+			t = "*mut " + t
+		}
+	}
+	// arrays of arrays are declared outermost first
+	for i := len(ct.Dims) - 1; i >= 0; i-- {
+		t = fmt.Sprintf("[%s; %s]", t, strings.TrimPrefix(ct.Dims[i], "VK_"))
+	}
+	return t, ok || len(ct.Pointers) > 0
+}
+
+// rustIdent makes a snake_case member or parameter name of a C name.
+func rustIdent(name string) string {
+	name = toLowerSnakeCase(name)
+	if rustKeywords[name] {
+		return "r#" + name
+	}
+	return name
+}
+
+// rustConstant converts an API constant, ok is false if its value isn't a
+// C literal it knows. Array sizes are usize, as Rust wants them.
+func rustConstant(name, value string) (RustConstant, bool) {
+	c := RustConstant{Name: strings.TrimPrefix(name, "VK_")}
+	v := strings.TrimSuffix(strings.TrimPrefix(value, "("), ")")
+	not := strings.HasPrefix(v, "~")
+	v = strings.TrimPrefix(v, "~")
+	switch {
+	case name == "VK_TRUE" || name == "VK_FALSE":
+		c.Type = "Bool32"
+	case strings.HasSuffix(v, "ULL"):
+		c.Type, v = "u64", strings.TrimSuffix(v, "ULL")
+	case strings.HasSuffix(v, "U"):
+		c.Type, v = "u32", strings.TrimSuffix(v, "U")
+	case strings.HasSuffix(v, "F") || strings.HasSuffix(v, "f"):
+		if _, err := strconv.ParseFloat(v[:len(v)-1], 32); err != nil || not {
+			return c, false
+		}
+		c.Type, c.Value = "f32", v[:len(v)-1]
+		return c, true
+	default:
+		c.Type = "usize"
+	}
+	if _, err := strconv.ParseUint(v, 0, 64); err != nil {
+		return c, false
+	}
+	c.Value = v
+	if not {
+		c.Value = "!" + v
+	}
+	return c, true
+}
+
+// rustEnumValues converts the values of an enum, those without a number are
+// left blank.
+func rustEnumValues(e *Enum, bits bool) []RustConstant {
+	prefix := enumValuePrefix(e.VkName)
+	words := strings.Split(prefix, "_")
+	var out []RustConstant
+	seen := map[string]bool{}
+	for _, v := range e.Values {
+		if !v.HasNumber {
+			out = append(out, RustConstant{})
+			continue
+		}
+		name := trimEnumValueName(rustEnumValueNaming{}, "", prefix, e.VkName, v.VkName)
+		if name == "" || name[0] >= '0' && name[0] <= '9' {
+			// TYPE_2D
+			name = words[len(words)-1] + "_" + name
+		}
+		if seen[name] {
+			// the values differ in their tags only
+			name = strings.TrimPrefix(v.VkName, prefix+"_")
+		}
+		seen[name] = true
+		value := strconv.FormatInt(v.Number, 10)
+		if bits {
+			value = fmt.Sprintf("0x%x", uint64(v.Number))
+		}
+		out = append(out, RustConstant{Name: name, Value: value})
+	}
+	return out
+}
+
+func newRustModule(params *HeaderParams, ctx *Context) *RustModule {
+	m := &RustModule{Banner: params.Banner}
+	rt := &rustTypes{names: map[string]string{}, missing: map[string]bool{}}
+	for k, v := range rustCTypes {
+		rt.names[k] = v
+	}
+	for _, c := range ctx.Constants {
+		if rc, ok := rustConstant(c.Name, c.Value); ok {
+			m.Constants = append(m.Constants, rc)
+		}
+	}
+	for _, h := range ctx.Handles {
+		name := strings.TrimPrefix(h.VkName, "Vk")
+		rt.names[h.VkName] = name
+		m.Handles = append(m.Handles, RustHandle{Name: name, Dispatchable: h.TypeSafe})
+	}
+	bitEnums := map[string]bool{}
+	for _, bm := range ctx.BitMasks {
+		name := strings.TrimPrefix(bm.VkName, "Vk")
+		rt.names[bm.VkName] = name
+		r := RustEnum{Name: name, Repr: "Flags"}
+		if bm.BitWidth == 64 {
+			r.Repr = "Flags64"
+		}
+		if bm.Enum != nil && bm.Enum.VkName != "" {
+			bitEnums[bm.Enum.VkName] = true
+			r.FlagBits = strings.TrimPrefix(bm.Enum.VkName, "Vk")
+			rt.names[bm.Enum.VkName] = r.FlagBits
+			r.Values = rustEnumValues(bm.Enum, true)
+		}
+		m.BitMasks = append(m.BitMasks, r)
+	}
+	for i := range ctx.Enums {
+		e := &ctx.Enums[i]
+		if bitEnums[e.VkName] || len(e.Values) == 0 {
+			continue
+		}
+		name := strings.TrimPrefix(e.VkName, "Vk")
+		rt.names[e.VkName] = name
+		m.Enums = append(m.Enums, RustEnum{Name: name, Repr: "i32", Values: rustEnumValues(e, false)})
+	}
+	// C++ name -> Rust name of the StructureType values
+	sTypes := map[string]string{}
+	for i := range ctx.Enums {
+		if e := &ctx.Enums[i]; e.VkName == "VkStructureType" {
+			for j, v := range rustEnumValues(e, false) {
+				sTypes[e.Values[j].Name] = v.Name
+			}
+		}
+	}
+	// the structs come ordered by their dependencies, those using a struct
+	// left out by value are left out too
+	for _, s := range ctx.Structs {
+		rs := RustStruct{Name: strings.TrimPrefix(s.VkName, "Vk"), VkName: s.VkName, Union: s.Union}
+		ok := true
+		for _, mem := range s.Members {
+			t, known := rt.rustType(mem.AnalyzedType.CType)
+			if !known {
+				ok = false
+				break
+			}
+			rs.Fields = append(rs.Fields, RustField{Name: rustIdent(mem.Name), Type: t})
+		}
+		if !ok {
+			continue
+		}
+		if s.HasSType {
+			rs.SType = sTypes[s.SType]
+		}
+		rt.names[s.VkName] = rs.Name
+		m.Structs = append(m.Structs, rs)
+	}
+	for _, a := range ctx.TypeAliases {
+		if vk := "Vk" + a.Target; rt.names[vk] != "" {
+			rt.names["Vk"+a.Name] = a.Name
+			m.Aliases = append(m.Aliases, Alias{Name: a.Name, Target: a.Target})
+		}
+	}
+	for _, c := range ctx.Commands {
+		rc := RustCommand{VkName: c.VkName, Name: toLowerSnakeCase(strings.TrimPrefix(c.VkName, "vk"))}
+		ok := true
+		for _, p := range c.Parameters {
+			t, known := rt.rustType(p.AnalyzedType.CType)
+			if !known {
+				ok = false
+				break
+			}
+			rc.Params = append(rc.Params, RustField{Name: rustIdent(p.Name), Type: t})
+		}
+		if c.RetVkType != "void" {
+			t, known := rt.rustType(CType{Type: c.RetVkType})
+			ok = ok && known
+			rc.Ret = t
+		}
+		if ok {
+			m.Commands = append(m.Commands, rc)
+		}
+	}
+	for t := range rt.missing {
+		m.Missing = append(m.Missing, t)
+	}
+	sort.Strings(m.Missing)
+	return m
+}
+
+// rustBackend is -lang rust.
+type rustBackend struct{}
+
+func init() {
+	RegisterBackend(rustBackend{})
+}
+
+func (rustBackend) Name() string { return "rust" }
+
+func (rustBackend) Generate(w io.Writer, ctx *Context, opts Options) error {
+	return ctx.execute(w, "rust", newRustModule(ctx.Params, ctx))
+}
@@ -0,0 +1,218 @@
+package cppgen
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SafeStruct is the deep-copying variant of a struct in the safe namespace,
+// see Options.SafeStructs. It has the layout of the C struct and owns what
+// Members point to: copying it copies them, destroying it frees them.
+type SafeStruct struct {
+	Protect Protect
+	Name    string
+	VkName  string
+	Wrapper string
+
+	// sType value of structs which have it, they can be copied as part of
+	// a pNext chain
+	TypeName string
+
+	Members []SafeMember
+}
+
+type safeKind int
+
+const (
+	safeChain       safeKind = iota // pNext
+	safeString                      // null-terminated const char *
+	safeStrings                     // array of strings
+	safeArray                       // array of plain values, 1 if no len
+	safeBytes                       // void * with a size in bytes
+	safeStructArray                 // array of structs owning something
+	safeEmbedded                    // struct owning something, by value
+)
+
+// SafeMember is a member which a SafeStruct copies deeply. Count is the C++
+// expression of the number of elements, with "$." in place of the struct the
+// members are read from.
+type SafeMember struct {
+	Name  string
+	Kind  safeKind
+	Safe  string
+	Count string
+}
+
+// Copy is the statement copying the member from src into m_struct, after
+// the shallow copy of the whole struct.
+func (m *SafeMember) Copy() string {
+	count := strings.Replace(m.Count, "$.", "src.", -1)
+	switch m.Kind {
+	case safeChain:
+		return fmt.Sprintf("m_struct.%s = copyPNext(src.%s);", m.Name, m.Name)
+	case safeString:
+		return fmt.Sprintf("m_struct.%s = copyString(src.%s);", m.Name, m.Name)
+	case safeStrings:
+		return fmt.Sprintf("m_struct.%s = copyStrings(src.%s, %s);", m.Name, m.Name, count)
+	case safeArray:
+		return fmt.Sprintf("m_struct.%s = copyArray(src.%s, %s);", m.Name, m.Name, count)
+	case safeBytes:
+		return fmt.Sprintf("m_struct.%s = copyBytes(src.%s, %s);", m.Name, m.Name, count)
+	case safeStructArray:
+		return fmt.Sprintf("m_struct.%s = copyStructArray<%s>(src.%s, %s);", m.Name, m.Safe, m.Name, count)
+	case safeEmbedded:
+		return fmt.Sprintf("new (&m_struct.%s) %s(src.%s);", m.Name, m.Safe, m.Name)
+	}
+	panic("unknown safe member kind")
+}
+
+// Free is the statement freeing what the member of m_struct owns.
+func (m *SafeMember) Free() string {
+	count := strings.Replace(m.Count, "$.", "m_struct.", -1)
+	switch m.Kind {
+	case safeChain:
+		return fmt.Sprintf("freePNext(m_struct.%s);", m.Name)
+	case safeString, safeArray:
+		return fmt.Sprintf("delete[] m_struct.%s;", m.Name)
+	case safeStrings:
+		return fmt.Sprintf("freeStrings(m_struct.%s, %s);", m.Name, count)
+	case safeBytes:
+		return fmt.Sprintf("delete[] static_cast<const char *>(m_struct.%s);", m.Name)
+	case safeStructArray:
+		return fmt.Sprintf("delete[] reinterpret_cast<const %s *>(m_struct.%s);", m.Safe, m.Name)
+	case safeEmbedded:
+		return fmt.Sprintf("reinterpret_cast<%s *>(&m_struct.%s)->~%s();", m.Safe, m.Name, m.Safe)
+	}
+	panic("unknown safe member kind")
+}
+
+// newSafeStructs returns the safe variants of the structs which point to
+// something. Structs whose pointers can't be followed, arrays with a length
+// that isn't an expression of integer members or pointers to pointers other
+// than strings, are left out, along with the structs containing them.
+// void pointers without a size (pUserData) are copied as they are, and so
+// are types declared by other headers.
+func (ctx *Context) newSafeStructs() []SafeStruct {
+	structs := map[string]*Struct{}
+	for i := range ctx.Structs {
+		structs[ctx.Structs[i].VkName] = &ctx.Structs[i]
+	}
+	// structs are sorted by dependencies, so the structs one refers to have
+	// been decided on before it, those that haven't are refused
+	decided := map[string]bool{}
+	safe := map[string]bool{}
+	var out []SafeStruct
+	for _, s := range ctx.Structs {
+		if s.Union {
+			decided[s.VkName] = true
+			continue
+		}
+		members, ok := ctx.safeMembers(&s, structs, decided, safe)
+		decided[s.VkName] = true
+		if !ok || len(members) == 0 {
+			continue
+		}
+		safe[s.VkName] = true
+		ss := SafeStruct{
+			Protect:  s.Protect,
+			Name:     s.Name,
+			VkName:   s.VkName,
+			Wrapper:  ctx.Namespace + "::" + s.Name,
+			Members:  members,
+			TypeName: s.TypeName,
+		}
+		out = append(out, ss)
+	}
+	return out
+}
+
+func (ctx *Context) safeMembers(s *Struct, structs map[string]*Struct, decided, safe map[string]bool) ([]SafeMember, bool) {
+	integers := map[string]bool{}
+	for _, m := range s.Members {
+		if m.AnalyzedType.IsBlank {
+			integers[m.Name] = true
+		}
+	}
+	var out []SafeMember
+	for _, m := range s.Members {
+		at := &m.AnalyzedType
+		_, isStruct := structs[at.Type]
+		if isStruct && (at.Type == s.VkName || !decided[at.Type]) {
+			// VkBaseOutStructure and the like
+			return nil, false
+		}
+		owner := isStruct && safe[at.Type]
+		sm := SafeMember{Name: m.Name, Safe: convertStructName(at.Type)}
+		switch {
+		case at.IsArray:
+			if owner {
+				return nil, false
+			}
+			continue
+		case at.IsBlank:
+			if !owner {
+				continue
+			}
+			sm.Kind = safeEmbedded
+		case m.Name == "pNext" && at.Type == "void":
+			sm.Kind = safeChain
+		case at.Type == "char" && at.Suffix() == "*" && m.Len == "null-terminated":
+			sm.Kind = safeString
+		case at.Type == "char" && at.Suffix() == "* const*" && strings.HasSuffix(m.Len, ",null-terminated"):
+			count, ok := safeCount(strings.TrimSuffix(m.Len, ",null-terminated"), integers)
+			if !ok {
+				return nil, false
+			}
+			sm.Kind, sm.Count = safeStrings, count
+		case at.Suffix() != "*":
+			return nil, false
+		case at.Type == "void" && m.Len == "":
+			continue
+		default:
+			sm.Kind, sm.Count = safeArray, "1"
+			if m.Len != "" {
+				count, ok := safeCount(m.Len, integers)
+				if !ok {
+					return nil, false
+				}
+				sm.Count = count
+			}
+			if at.Type == "void" {
+				sm.Kind = safeBytes
+			} else if owner {
+				sm.Kind = safeStructArray
+			}
+		}
+		out = append(out, sm)
+	}
+	return out, true
+}
+
+// safeCount turns the len of an array member into a C++ expression, see
+// SafeMember.Count. The len may only name integer members of the struct,
+// numbers, arithmetic operators and parentheses.
+func safeCount(expr string, integers map[string]bool) (string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+		switch {
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(expr) && (unicode.IsLetter(rune(expr[j])) || unicode.IsDigit(rune(expr[j])) || expr[j] == '_') {
+				j++
+			}
+			if !integers[expr[i:j]] {
+				return "", false
+			}
+			b.WriteString("$." + expr[i:j])
+			i = j
+		case unicode.IsDigit(c) || strings.ContainsRune("+-*/() ", c):
+			b.WriteRune(c)
+			i++
+		default:
+			return "", false
+		}
+	}
+	return b.String(), true
+}
@@ -0,0 +1,48 @@
+package cppgen
+
+import "fmt"
+
+// headerSections are the sections of the header Options.Only and Skip choose
+// from, in the order they're emitted. Each one uses the types of those
+// before it, which are generated along with it.
+var headerSections = []string{"enums", "handles", "structs", "commands"}
+
+// Sections says which sections of the header are generated, the preamble
+// (Flags, ArrayProxy, ...) always is.
+type Sections struct {
+	Enums    bool
+	Handles  bool
+	Structs  bool
+	Commands bool
+}
+
+// selectSections resolves the sections named by -only, all by default, less
+// those named by -skip. The sections the remaining ones need are added, it's
+// an error if they're skipped.
+func selectSections(only, skip listFlag) (Sections, error) {
+	for _, name := range append(append(listFlag{}, only...), skip...) {
+		if !listFlag(headerSections).contains(name) {
+			return Sections{}, fmt.Errorf("unknown section %q, want one of %v", name, headerSections)
+		}
+	}
+	last := -1
+	for i, name := range headerSections {
+		if (len(only) == 0 || only.contains(name)) && !skip.contains(name) {
+			last = i
+		}
+	}
+	if last == -1 {
+		return Sections{}, fmt.Errorf("no section is left to generate")
+	}
+	for _, name := range headerSections[:last] {
+		if skip.contains(name) {
+			return Sections{}, fmt.Errorf("can't skip %s, %s need them", name, headerSections[last])
+		}
+	}
+	return Sections{
+		Enums:    last >= 0,
+		Handles:  last >= 1,
+		Structs:  last >= 2,
+		Commands: last >= 3,
+	}, nil
+}
@@ -0,0 +1,154 @@
+package cppgen
+
+// scalar types with a fixed size, mapped to the BlobWriter/BlobReader
+// method handling them
+var serialScalarOps = map[string]string{
+	"uint8_t":         "u8",
+	"int8_t":          "u8",
+	"char":            "u8",
+	"uint16_t":        "u16",
+	"int16_t":         "u16",
+	"uint32_t":        "u32",
+	"int32_t":         "u32",
+	"VkBool32":        "u32",
+	"VkFlags":         "u32",
+	"VkSampleMask":    "u32",
+	"uint64_t":        "u64",
+	"int64_t":         "u64",
+	"VkFlags64":       "u64",
+	"VkDeviceSize":    "u64",
+	"VkDeviceAddress": "u64",
+	"float":           "f32",
+	"double":          "f64",
+}
+
+var serialOpTypes = map[string]string{
+	"u8":  "uint8_t",
+	"u16": "uint16_t",
+	"u32": "uint32_t",
+	"u64": "uint64_t",
+	"f32": "float",
+	"f64": "double",
+}
+
+// SerialMember describes how a single struct member is written to and read
+// from a blob. Op is the BlobWriter/BlobReader method, or empty for nested
+// structs. Size is set for arrays.
+type SerialMember struct {
+	Name   string
+	VkType string
+	Op     string
+	OpType string
+	Size   string
+}
+
+func (m *SerialMember) IsBytes() bool  { return m.Size != "" && m.Op == "u8" }
+func (m *SerialMember) IsStruct() bool { return m.Op == "" }
+
+type SerialStruct struct {
+	Name     string
+	VkName   string
+	HasPNext bool
+	Members  []SerialMember
+}
+
+// resolveSerializers figures out which of the requested structs (and structs
+// they contain) can be serialized, i.e. contain only fixed-size data. The pNext
+// member is skipped, other pointers make the struct non-serializable.
+func (ctx *Context) resolveSerializers(names []string) {
+	structs := map[string]*Struct{}
+	for i, s := range ctx.Structs {
+		structs[s.VkName] = &ctx.Structs[i]
+	}
+	// bitmasks and enums are written with the width of their type, the bits
+	// of VkFlags64 don't fit in a u32
+	wide := map[string]bool{}
+	for _, bm := range ctx.BitMasks {
+		wide[bm.VkName] = bm.BitWidth == 64
+	}
+	for _, e := range ctx.Enums {
+		wide[e.VkName] = e.BitWidth == 64
+	}
+	intOp := func(vkType string) string {
+		if wide[vkType] {
+			return "u64"
+		}
+		return "u32"
+	}
+
+	// nil value means the struct is not serializable
+	resolved := map[string]*SerialStruct{}
+	var resolve func(name string) *SerialStruct
+	resolve = func(name string) *SerialStruct {
+		if ss, ok := resolved[name]; ok {
+			return ss
+		}
+		resolved[name] = nil
+		s, ok := structs[name]
+		if !ok {
+			return nil
+		}
+		ss := &SerialStruct{Name: s.Name, VkName: s.VkName}
+		for _, m := range s.Members {
+			at := &m.AnalyzedType
+			if m.Name == "pNext" {
+				ss.HasPNext = true
+				continue
+			}
+			if at.IsPointer && !at.IsArray || len(at.Dims) > 1 {
+				return nil
+			}
+			sm := SerialMember{
+				Name:   m.Name,
+				VkType: at.Type,
+				Size:   m.ArraySize,
+			}
+			switch conv := m.Converter.(type) {
+			case *StaticCastConverter, *BitMaskConverter:
+				sm.Op = intOp(at.Type)
+			case *ReinterpretCastConverter:
+				if resolve(conv.VkName) == nil {
+					return nil
+				}
+			case *ArrayConverter:
+				if _, ok := structs[at.Type]; ok {
+					if resolve(at.Type) == nil {
+						return nil
+					}
+				} else if op, ok := serialScalarOps[at.Type]; ok {
+					sm.Op = op
+				} else if _, ok := ctx.converters[at.Type].(*StaticCastConverter); ok {
+					sm.Op = intOp(at.Type)
+				} else {
+					return nil
+				}
+			default:
+				op, ok := serialScalarOps[at.Type]
+				if !ok {
+					return nil
+				}
+				sm.Op = op
+			}
+			sm.OpType = serialOpTypes[sm.Op]
+			ss.Members = append(ss.Members, sm)
+		}
+		resolved[name] = ss
+		return ss
+	}
+
+	for _, name := range names {
+		if resolve(name) == nil {
+			if _, ok := structs[name]; ok {
+				ctx.diags.diagnose("skipped", name, "struct %s contains pointers or unsupported types, not serializable", name)
+			}
+		}
+	}
+
+	// keep the dependency order of structs, nested ones come first
+	ctx.Serializers = nil
+	for _, s := range ctx.Structs {
+		if ss := resolved[s.VkName]; ss != nil {
+			ctx.Serializers = append(ctx.Serializers, *ss)
+		}
+	}
+}
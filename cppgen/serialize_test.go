@@ -0,0 +1,29 @@
+package cppgen
+
+import "testing"
+
+// TestSerializeWidth checks that members are written with the width of
+// their type, VkFlags64 bitmasks as u64.
+func TestSerializeWidth(t *testing.T) {
+	opts := NewOptions()
+	opts.SerializeStructs = listFlag{"VkMemoryBarrier2"}
+	g, reg := readTestRegistry(t, opts)
+	ctx := g.newContext(reg)
+	if len(ctx.Serializers) != 1 {
+		t.Fatalf("serializers = %+v, want VkMemoryBarrier2", ctx.Serializers)
+	}
+	want := map[string]string{
+		"sType":         "u32",
+		"srcStageMask":  "u64",
+		"srcAccessMask": "u64",
+	}
+	members := ctx.Serializers[0].Members
+	if len(members) != len(want) {
+		t.Errorf("members = %+v", members)
+	}
+	for _, m := range members {
+		if m.Op != want[m.Name] || m.OpType != serialOpTypes[want[m.Name]] {
+			t.Errorf("%s %s is written as %s %s, want %s", m.VkType, m.Name, m.Op, m.OpType, want[m.Name])
+		}
+	}
+}
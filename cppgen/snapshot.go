@@ -0,0 +1,303 @@
+package cppgen
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// snapshotTypes are the types stored in interfaces of a Context, a snapshot
+// names them to decode them.
+var snapshotTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, v := range []TypeConverter{
+		NopConverter{},
+		&BitMaskConverter{},
+		&StaticCastConverter{},
+		&ArrayConverter{},
+		&ReinterpretCastConverter{},
+		&HandleConverter{},
+		&NonDispatchableHandleConverter{},
+	} {
+		t := reflect.TypeOf(v)
+		snapshotTypes[t.String()] = t
+	}
+}
+
+// encodeSnapshot encodes the exported fields of what v points to, following
+// pointers and interfaces. Pointers to the same value are decoded as such,
+// the parents of handles stay the handles of the context.
+func encodeSnapshot(v interface{}) ([]byte, error) {
+	e := snapshotEncoder{ptrs: map[snapshotPtr]uint64{}}
+	if err := e.encode(reflect.ValueOf(v).Elem()); err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+// decodeSnapshot decodes data written by encodeSnapshot into what v points
+// to. Unexported fields are left alone.
+func decodeSnapshot(data []byte, v interface{}) error {
+	d := snapshotDecoder{r: bytes.NewReader(data)}
+	if err := d.decode(reflect.ValueOf(v).Elem()); err != nil {
+		return err
+	}
+	if d.r.Len() != 0 {
+		return errors.New("snapshot: trailing data")
+	}
+	return nil
+}
+
+// pointers to a struct and to its first field have the same address
+type snapshotPtr struct {
+	t    reflect.Type
+	addr uintptr
+}
+
+type snapshotEncoder struct {
+	buf  bytes.Buffer
+	ptrs map[snapshotPtr]uint64
+}
+
+func (e *snapshotEncoder) uint(n uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	e.buf.Write(buf[:binary.PutUvarint(buf[:], n)])
+}
+
+func (e *snapshotEncoder) string(s string) {
+	e.uint(uint64(len(s)))
+	e.buf.WriteString(s)
+}
+
+func (e *snapshotEncoder) encode(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		// 0 is nil, 1 a pointer seen for the first time followed by what it
+		// points to, others the number of a pointer seen before plus 2
+		if v.IsNil() {
+			e.uint(0)
+			return nil
+		}
+		key := snapshotPtr{v.Type(), v.Pointer()}
+		if n, ok := e.ptrs[key]; ok {
+			e.uint(n + 2)
+			return nil
+		}
+		e.ptrs[key] = uint64(len(e.ptrs))
+		e.uint(1)
+		return e.encode(v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			e.string("")
+			return nil
+		}
+		name := v.Elem().Type().String()
+		if _, ok := snapshotTypes[name]; !ok {
+			return fmt.Errorf("snapshot: unknown type %s in an interface", name)
+		}
+		e.string(name)
+		return e.encode(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			if err := e.encode(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Map:
+		// nil ones are kept apart from empty ones, the length is one more
+		if v.IsNil() {
+			e.uint(0)
+			return nil
+		}
+		e.uint(uint64(v.Len()) + 1)
+		if v.Kind() == reflect.Map {
+			for it := v.MapRange(); it.Next(); {
+				if err := e.encode(it.Key()); err != nil {
+					return err
+				}
+				if err := e.encode(it.Value()); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := e.encode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := e.encode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		e.string(v.String())
+	case reflect.Bool:
+		if v.Bool() {
+			e.uint(1)
+		} else {
+			e.uint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.uint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.uint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		e.uint(math.Float64bits(v.Float()))
+	default:
+		return fmt.Errorf("snapshot: can't encode %s", v.Type())
+	}
+	return nil
+}
+
+type snapshotDecoder struct {
+	r    *bytes.Reader
+	ptrs []reflect.Value
+}
+
+func (d *snapshotDecoder) uint() (uint64, error) {
+	n, err := binary.ReadUvarint(d.r)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (d *snapshotDecoder) string() (string, error) {
+	n, err := d.uint()
+	if err != nil {
+		return "", err
+	}
+	if n > uint64(d.r.Len()) {
+		return "", io.ErrUnexpectedEOF
+	}
+	b := make([]byte, n)
+	_, err = io.ReadFull(d.r, b)
+	return string(b), err
+}
+
+func (d *snapshotDecoder) decode(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		n, err := d.uint()
+		if err != nil {
+			return err
+		}
+		switch {
+		case n == 0:
+			v.Set(reflect.Zero(v.Type()))
+		case n == 1:
+			p := reflect.New(v.Type().Elem())
+			d.ptrs = append(d.ptrs, p)
+			v.Set(p)
+			return d.decode(p.Elem())
+		case n-2 < uint64(len(d.ptrs)) && d.ptrs[n-2].Type() == v.Type():
+			v.Set(d.ptrs[n-2])
+		default:
+			return errors.New("snapshot: invalid pointer")
+		}
+	case reflect.Interface:
+		name, err := d.string()
+		if err != nil || name == "" {
+			return err
+		}
+		t, ok := snapshotTypes[name]
+		if !ok || !t.Implements(v.Type()) {
+			return fmt.Errorf("snapshot: unknown type %s in an interface", name)
+		}
+		elem := reflect.New(t).Elem()
+		if err := d.decode(elem); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			if err := d.decode(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Map:
+		n, err := d.uint()
+		if err != nil || n == 0 {
+			return err
+		}
+		n--
+		// every element takes a byte at least
+		if n > uint64(d.r.Len()) {
+			return io.ErrUnexpectedEOF
+		}
+		if v.Kind() == reflect.Map {
+			v.Set(reflect.MakeMapWithSize(v.Type(), int(n)))
+			for i := uint64(0); i < n; i++ {
+				key := reflect.New(v.Type().Key()).Elem()
+				elem := reflect.New(v.Type().Elem()).Elem()
+				if err := d.decode(key); err != nil {
+					return err
+				}
+				if err := d.decode(elem); err != nil {
+					return err
+				}
+				v.SetMapIndex(key, elem)
+			}
+			return nil
+		}
+		v.Set(reflect.MakeSlice(v.Type(), int(n), int(n)))
+		for i := 0; i < int(n); i++ {
+			if err := d.decode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := d.decode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		s, err := d.string()
+		if err != nil {
+			return err
+		}
+		v.SetString(s)
+	case reflect.Bool:
+		n, err := d.uint()
+		if err != nil {
+			return err
+		}
+		v.SetBool(n != 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := d.uint()
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := d.uint()
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := d.uint()
+		if err != nil {
+			return err
+		}
+		v.SetFloat(math.Float64frombits(n))
+	default:
+		return fmt.Errorf("snapshot: can't decode %s", v.Type())
+	}
+	return nil
+}
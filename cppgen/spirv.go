@@ -0,0 +1,67 @@
+package cppgen
+
+import "github.com/nsf/vulkangen/registry"
+
+// SpirvEnable is a single way of enabling a SPIR-V extension or capability:
+// either a core version, a device extension, a feature struct member or a
+// property struct member with a specific value. Only the relevant fields are
+// set, the rest are empty.
+type SpirvEnable struct {
+	Version   string
+	Extension string
+	Struct    string
+	Feature   string
+	Property  string
+	Member    string
+	Value     string
+	Requires  string
+}
+
+// SpirvEntry is a SPIR-V extension or capability and the list of things that
+// enable it, any single one of them is sufficient. Offset is the index of the
+// first enable in the flattened table of all entries.
+type SpirvEntry struct {
+	Name    string
+	Enables []SpirvEnable
+	Offset  int
+}
+
+func newSpirvEntries(xentries []registry.SpirvEntry) []SpirvEntry {
+	var out []SpirvEntry
+	offset := 0
+	for _, xe := range xentries {
+		e := SpirvEntry{Name: xe.Name, Offset: offset}
+		offset += len(xe.Enables)
+		for _, xen := range xe.Enables {
+			e.Enables = append(e.Enables, SpirvEnable(xen))
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// SpirvTable is a named list of SPIR-V entries, used by the template to emit
+// the extension and capability tables the same way.
+type SpirvTable struct {
+	Name    string
+	Plural  string
+	Entries []SpirvEntry
+}
+
+func (ctx *Context) SpirvTables() []SpirvTable {
+	return []SpirvTable{
+		{Name: "Extension", Plural: "Extensions", Entries: ctx.SpirvExtensions},
+		{Name: "Capability", Plural: "Capabilities", Entries: ctx.SpirvCapabilities},
+	}
+}
+
+// EnableCount returns the number of enables of all entries, the size of the
+// flattened table. The table isn't emitted without any, C++ has no
+// zero-length arrays.
+func (t SpirvTable) EnableCount() int {
+	n := 0
+	for _, e := range t.Entries {
+		n += len(e.Enables)
+	}
+	return n
+}
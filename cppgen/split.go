@@ -0,0 +1,60 @@
+package cppgen
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// splitParts are the files the header is split into by Options.SplitDir, in
+// the order they include each other. The first one has the preamble, the
+// last one is everything the single header has.
+var splitParts = []struct {
+	file     string
+	template string
+}{
+	{"vk_enums.hpp", "enumspart"},
+	{"vk_handles.hpp", "handlespart"},
+	{"vk_structs.hpp", "structspart"},
+	{"vk_funcs.hpp", "funcspart"},
+}
+
+// splitBackends writes the parts of the header to dir. std::hash of handles
+// follows them in vk_handles.hpp, the epilogue includes end vk_funcs.hpp.
+func splitBackends(dir string, params *HeaderParams, ctx *Context) []backend {
+	var backends []backend
+	for i, part := range splitParts {
+		p := *params
+		header := "header"
+		if i > 0 {
+			header = "partheader"
+			p.Include = splitParts[i-1].file
+		}
+		if part.template != "handlespart" {
+			p.Handles = nil
+		}
+		if i != len(splitParts)-1 {
+			p.EpilogueIncludes = nil
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(part.file, "vk_"), ".hpp")
+		p.GuardBegin, p.GuardEnd = includeGuard(guardName(p.IncludeGuard, strings.ToUpper(name)), "//")
+		body := part.template
+		backends = append(backends, backend{
+			name: "C++ header " + part.file,
+			file: filepath.Join(dir, part.file),
+			lang: "c++",
+			emit: func(w io.Writer) error {
+				for _, t := range []struct {
+					name string
+					data interface{}
+				}{{header, &p}, {body, ctx}, {"footer", &p}} {
+					if err := ctx.execute(w, t.name, t.data); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		})
+	}
+	return backends
+}
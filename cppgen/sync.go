@@ -0,0 +1,106 @@
+package cppgen
+
+import (
+	"strings"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// SyncStage is a pipeline stage bit with the queue flag bits that support it
+// and the stage bits it is equivalent to (for umbrella stages like
+// ALL_GRAPHICS). All names are C names.
+type SyncStage struct {
+	Name       string
+	Queues     []string
+	Equivalent []string
+}
+
+// SyncAccess is an access flag bit with the pipeline stages it can be used
+// with. An empty Stages list means the access is not restricted.
+type SyncAccess struct {
+	Name       string
+	Stages     []string
+	Equivalent []string
+}
+
+// SyncPipeline is a logically ordered list of stages of a pipeline type.
+type SyncPipeline struct {
+	Name   string
+	Stages []string
+}
+
+type Sync struct {
+	Stages    []SyncStage
+	Accesses  []SyncAccess
+	Pipelines []SyncPipeline
+
+	// the tables are inline variables to be exported, see Options.Module
+	Module bool
+}
+
+func (s *Sync) Empty() bool {
+	return len(s.Stages) == 0 && len(s.Accesses) == 0 && len(s.Pipelines) == 0
+}
+
+var syncQueueBits = map[string]string{
+	"graphics":       "VK_QUEUE_GRAPHICS_BIT",
+	"compute":        "VK_QUEUE_COMPUTE_BIT",
+	"transfer":       "VK_QUEUE_TRANSFER_BIT",
+	"sparse_binding": "VK_QUEUE_SPARSE_BINDING_BIT",
+	"decode":         "VK_QUEUE_VIDEO_DECODE_BIT_KHR",
+	"encode":         "VK_QUEUE_VIDEO_ENCODE_BIT_KHR",
+	"opticalflow":    "VK_QUEUE_OPTICAL_FLOW_BIT_NV",
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func newSync(xs *registry.Sync) Sync {
+	var s Sync
+	for _, xst := range xs.Stages {
+		st := SyncStage{
+			Name:       xst.Name,
+			Equivalent: splitList(xst.Equivalent.Stage),
+		}
+		for _, q := range splitList(xst.Support.Queues) {
+			bit, ok := syncQueueBits[q]
+			if !ok {
+				bit = "VK_QUEUE_" + strings.ToUpper(q) + "_BIT"
+			}
+			st.Queues = append(st.Queues, bit)
+		}
+		s.Stages = append(s.Stages, st)
+	}
+	for _, xa := range xs.Accesses {
+		s.Accesses = append(s.Accesses, SyncAccess{
+			Name:       xa.Name,
+			Stages:     splitList(xa.Support.Stage),
+			Equivalent: splitList(xa.Equivalent.Access),
+		})
+	}
+	for _, xp := range xs.Pipelines {
+		p := SyncPipeline{Name: xp.Name}
+		for _, st := range xp.Stages {
+			p.Stages = append(p.Stages, strings.TrimSpace(st.Name))
+		}
+		s.Pipelines = append(s.Pipelines, p)
+	}
+	return s
+}
+
+// PipelineStageOffset returns the index of the first stage of the i-th
+// pipeline in the flattened table of all pipeline stages.
+func (s *Sync) PipelineStageOffset(i int) int {
+	offset := 0
+	for _, p := range s.Pipelines[:i] {
+		offset += len(p.Stages)
+	}
+	return offset
+}
@@ -0,0 +1,3700 @@
+package cppgen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+func line(s string) string {
+	if s != "" {
+		return s + "\n"
+	}
+	return ""
+}
+
+// orMask joins bit names into a C bitwise OR expression
+func orMask(bits []string) string {
+	if len(bits) == 0 {
+		return "0"
+	}
+	return strings.Join(bits, " | ")
+}
+
+// cstr returns a C string literal for s, or nullptr if s is empty
+func cstr(s string) string {
+	if s == "" {
+		return "nullptr"
+	}
+	return strconv.Quote(s)
+}
+
+// comment formats lines as a line comment block followed by an empty line,
+// or returns "" if there are none
+func comment(prefix string, lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(prefix)
+		if l != "" {
+			b.WriteString(" " + l)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// unboundRender is the render function the built-in templates are parsed
+// with, each generation executes a copy of them bound to its own.
+func unboundRender(name string, data interface{}) (string, error) {
+	return "", fmt.Errorf("template %s rendered outside of a generation", name)
+}
+
+// tpl are the built-in templates, see newGeneration.
+var tpl = template.Must(template.New("").Funcs(template.FuncMap{
+	"hasPrefix": strings.HasPrefix,
+	"hasSuffix": strings.HasSuffix,
+	"line":      line,
+	"cstr":      cstr,
+	"orMask":    orMask,
+	"comment":   comment,
+	"render":    unboundRender,
+	"lower":     strings.ToLower,
+	"code":      code,
+}).Parse(`
+
+
+
+
+
+
+
+
+{{ define "header" }}
+
+{{- comment "//" .Banner -}}
+{{- .GuardBegin }}
+{{- if .Defines }}
+{{ range .Defines }}
+#ifndef {{ .Name }}
+#define {{ .Name }}{{ with .Value }} {{ . }}{{ end }}
+#endif
+{{- end }}
+{{- end }}
+
+#include <array>
+#include <cassert>
+#include <cstdint>
+#include <cstddef>
+#include <cstdio>
+#include <cstring>
+#include <functional>
+#include <initializer_list>
+{{- if .SafeStructs }}
+#include <new>
+{{- end }}
+{{- if .Exceptions }}
+#include <stdexcept>
+{{- end }}
+#include <string>
+#include <tuple>
+#include <type_traits>
+#include <utility>
+#include <vector>
+#include {{ .VulkanHeader }}
+{{- if ge .CppStd 20 }}
+#include <compare>
+#include <span>
+{{- else }}
+#ifdef __cpp_impl_three_way_comparison
+#include <compare>
+#endif
+{{- end }}
+#ifdef VKGEN_OSTREAM
+#include <ostream>
+#endif
+{{- range .Includes }}
+#include {{ . }}
+{{- end }}
+{{- with .ExportMacro }}
+
+#ifndef {{ . }}
+#  if defined(_WIN32) || defined(__CYGWIN__)
+#    if defined({{ . }}_EXPORTS)
+#      define {{ . }} __declspec(dllexport)
+#    elif defined({{ . }}_SHARED)
+#      define {{ . }} __declspec(dllimport)
+#    else
+#      define {{ . }}
+#    endif
+#  elif defined({{ . }}_EXPORTS) || defined({{ . }}_SHARED)
+#    define {{ . }} __attribute__((visibility("default")))
+#  else
+#    define {{ . }}
+#  endif
+#endif
+{{- end }}
+{{- if .Exceptions }}
+
+// VKGEN_NO_EXCEPTIONS is defined if the compiler has exceptions turned off
+// (-fno-exceptions, /EHs-c-), so that the header compiles either way
+#if !defined(VKGEN_NO_EXCEPTIONS) && !defined(__cpp_exceptions) && !defined(__EXCEPTIONS) && !defined(_CPPUNWIND)
+#define VKGEN_NO_EXCEPTIONS
+#endif
+
+// VKGEN_CHECKED_NOEXCEPT marks the functions throwing Error on error codes,
+// they don't throw if VKGEN_NO_EXCEPTIONS is defined
+#ifdef VKGEN_NO_EXCEPTIONS
+#define VKGEN_CHECKED_NOEXCEPT noexcept
+#else
+#define VKGEN_CHECKED_NOEXCEPT
+#endif
+{{- end }}
+
+// VKGEN_CONSTEXPR14 marks the functions which are constexpr since C++14
+{{- if ge .CppStd 14 }}
+#define VKGEN_CONSTEXPR14 constexpr
+{{- else }}
+#if __cpp_constexpr >= 201304
+#define VKGEN_CONSTEXPR14 constexpr
+#else
+#define VKGEN_CONSTEXPR14
+#endif
+{{- end }}
+
+// VKGEN_NODISCARD marks the commands returning something, [[nodiscard]]
+// since C++17
+#define VKGEN_NODISCARD{{ if ge .CppStd 17 }} [[nodiscard]]{{ end }}
+{{- if .Module }}
+
+export module {{ .ModuleName }};
+{{- end }}
+
+{{ if .Module }}export {{ end }}namespace {{ .Namespace }} {
+{{- with .VersionNamespace }}
+inline namespace {{ . }} {
+{{- end }}
+
+template <typename EnumType, typename T = uint32_t>
+class Flags {
+	T m_mask;
+
+public:
+	constexpr Flags(): m_mask(0) {}
+	constexpr Flags(EnumType bit): m_mask(static_cast<T>(bit)) {}
+	explicit constexpr Flags(T mask): m_mask(mask) {}
+	constexpr Flags(const Flags &rhs): m_mask(rhs.m_mask) {}
+
+	Flags &operator=(const Flags &rhs) { m_mask = rhs.m_mask; return *this; }
+
+	Flags &operator|=(const Flags &rhs) { m_mask |= rhs.m_mask; return *this; }
+	Flags &operator&=(const Flags &rhs) { m_mask &= rhs.m_mask; return *this; }
+	Flags &operator^=(const Flags &rhs) { m_mask ^= rhs.m_mask; return *this; }
+
+	constexpr Flags operator|(const Flags &rhs) const { return Flags(m_mask | rhs.m_mask); }
+	constexpr Flags operator&(const Flags &rhs) const { return Flags(m_mask & rhs.m_mask); }
+	constexpr Flags operator^(const Flags &rhs) const { return Flags(m_mask ^ rhs.m_mask); }
+
+	constexpr Flags operator~() const { return Flags(~m_mask); }
+
+	constexpr bool operator==(const Flags &rhs) const { return m_mask == rhs.m_mask; }
+	constexpr bool operator!=(const Flags &rhs) const { return m_mask != rhs.m_mask; }
+
+	// contains reports whether all bits of flags are set, anyOf whether
+	// any is
+	constexpr bool contains(const Flags &flags) const { return (m_mask & flags.m_mask) == flags.m_mask; }
+	constexpr bool anyOf(const Flags &flags) const { return (m_mask & flags.m_mask) != 0; }
+
+	constexpr operator bool() const { return m_mask != 0; }
+	explicit constexpr operator T() const { return m_mask; }
+};
+
+// FlagTraits<Bits>::allFlags is the mask of all bits of Bits the registry
+// defines, ~flags & allFlags is the complement without undefined bits and
+// allFlags.contains(flags) whether flags are valid. A class template, like
+// EnumStrings, so that allFlags can be defined in the header before C++17.
+template <typename BitType, typename = void>
+struct FlagTraits;
+
+template <typename EnumType, typename T>
+inline Flags<EnumType, T> operator|(EnumType bit, const Flags<EnumType, T> &flags)
+{
+	return flags | bit;
+}
+template <typename EnumType, typename T>
+inline Flags<EnumType, T> operator&(EnumType bit, const Flags<EnumType, T> &flags)
+{
+	return flags & bit;
+}
+template <typename EnumType, typename T>
+inline Flags<EnumType, T> operator^(EnumType bit, const Flags<EnumType, T> &flags)
+{
+	return flags ^ bit;
+}
+
+// enumValueName returns the value part of a getEnumString() name,
+// "BufferUsageFlagBits::eTransferSrc" -> "eTransferSrc"
+inline const char *enumValueName(const char *s)
+{
+	const char *p = std::strstr(s, "::");
+	return p ? p + 2 : s;
+}
+
+// to_string decomposes a mask into its bits, "eTransferSrc | eTransferDst".
+// Bits without a name are written in hex, an empty mask is "0".
+template <typename EnumType, typename T>
+inline std::string to_string(const Flags<EnumType, T> &flags)
+{
+	T mask = static_cast<T>(flags);
+	if (mask == 0)
+		return "0";
+	std::string out;
+	for (size_t i = 0; i < sizeof(T) * 8; i++) {
+		T bit = T(1) << i;
+		if (!(mask & bit))
+			continue;
+		if (!out.empty())
+			out += " | ";
+		const char *s = getEnumString(static_cast<EnumType>(bit));
+		if (std::strstr(s, "::")) {
+			out += enumValueName(s);
+		} else {
+			char hex[2 + 16 + 1];
+			std::snprintf(hex, sizeof(hex), "0x%llx", static_cast<unsigned long long>(bit));
+			out += hex;
+		}
+	}
+	return out;
+}
+
+#ifdef VKGEN_OSTREAM
+// operator<< prints enums and flags like to_string, handles as their raw
+// value and structs member by member: pointers as addresses, but C strings,
+// and fixed arrays element by element.
+template <typename EnumType, typename T>
+inline std::ostream &operator<<(std::ostream &os, const Flags<EnumType, T> &flags)
+{
+	return os << to_string(flags);
+}
+
+inline std::ostream &printHandle(std::ostream &os, const char *name, uint64_t raw)
+{
+	char hex[2 + 16 + 1];
+	std::snprintf(hex, sizeof(hex), "0x%llx", static_cast<unsigned long long>(raw));
+	return os << name << '(' << hex << ')';
+}
+
+template <typename T>
+inline void printValue(std::ostream &os, const T &value)
+{
+	os << value;
+}
+
+inline void printValue(std::ostream &os, uint8_t value)
+{
+	os << static_cast<unsigned>(value);
+}
+
+inline void printValue(std::ostream &os, const char *s)
+{
+	if (s)
+		os << '"' << s << '"';
+	else
+		os << "nullptr";
+}
+
+template <typename T>
+inline void printArray(std::ostream &os, const T *array, size_t n);
+
+template <typename T, size_t N>
+inline void printValue(std::ostream &os, const T (&array)[N])
+{
+	printArray(os, array, N);
+}
+
+template <typename T>
+inline void printArray(std::ostream &os, const T *array, size_t n)
+{
+	os << '[';
+	for (size_t i = 0; i < n; i++) {
+		if (i > 0)
+			os << ", ";
+		printValue(os, array[i]);
+	}
+	os << ']';
+}
+#endif
+
+typedef uint32_t SampleMask;
+typedef uint32_t Bool32;
+typedef uint64_t DeviceSize;
+
+// vulkan_core.h defines the non-dispatchable handles as distinct pointer
+// types with VK_USE_64_BIT_PTR_DEFINES, as a shared uint64_t otherwise
+#if defined(VK_USE_64_BIT_PTR_DEFINES) && (VK_USE_64_BIT_PTR_DEFINES==1)
+#define VK_TYPESAFE_HANDLES 1
+#else
+#define VK_TYPESAFE_HANDLES 0
+#endif
+
+// EnumStrings<E>::strings are the names getEnumString looks the values of E
+// up in, a class template so that the arrays can be defined in the header
+// before C++17.
+// Densely numbered enums have an array of names indexed by value, holes are
+// nullptr, large sparse ones a table sorted by value. Other enums switch.
+template <typename E, typename = void>
+struct EnumStrings;
+
+constexpr const char *indexEnumString(const char *const *s, size_t n, int64_t i)
+{
+	return i >= 0 && static_cast<uint64_t>(i) < n && s[i] ? s[i] : "<invalid enum>";
+}
+
+// value to name mapping of large enums, sorted by value
+struct EnumString {
+	int64_t value;
+	const char *name;
+};
+
+// recursion depth is logarithmic, VkStructureType has over a thousand values
+constexpr bool enumStringsSorted(const EnumString *s, size_t n)
+{
+	return n < 2 || (enumStringsSorted(s, n / 2) && s[n / 2 - 1].value < s[n / 2].value &&
+		enumStringsSorted(s + n / 2, n - n / 2));
+}
+
+// binary search of [lo, hi), in a single return statement to be constexpr
+// in C++11
+constexpr const char *findEnumString(const EnumString *s, size_t lo, size_t hi, int64_t value)
+{
+	return lo >= hi ? "<invalid enum>"
+		: s[lo + (hi - lo) / 2].value < value ? findEnumString(s, lo + (hi - lo) / 2 + 1, hi, value)
+		: s[lo + (hi - lo) / 2].value > value ? findEnumString(s, lo, lo + (hi - lo) / 2, value)
+		: s[lo + (hi - lo) / 2].name;
+}
+
+struct NullHandle {};
+{{ if $.Module }}inline {{ end }}constexpr NullHandle nullHandle = {};
+
+struct Version {
+	uint32_t variant;
+	uint32_t major;
+	uint32_t minor;
+	uint32_t patch;
+
+	Version(): variant(0), major(0), minor(0), patch(0) {}
+	Version(uint32_t major, uint32_t minor, uint32_t patch, uint32_t variant = 0):
+		variant(variant), major(major), minor(minor), patch(patch) {}
+	explicit Version(uint32_t packed):
+		variant(packed >> 29), major((packed >> 22) & 0x7F),
+		minor((packed >> 12) & 0x3FF), patch(packed & 0xFFF) {}
+
+	uint32_t packed() const { return (variant << 29) | (major << 22) | (minor << 12) | patch; }
+
+	bool operator==(const Version &rhs) const { return packed() == rhs.packed(); }
+	bool operator!=(const Version &rhs) const { return packed() != rhs.packed(); }
+	bool operator<(const Version &rhs) const { return packed() < rhs.packed(); }
+};
+
+// ArrayProxy passes a contiguous range of elements to a command taking a
+// count and a pointer: nothing, a single element, an initializer list, an
+// array, a vector or, since C++20, a span of const or mutable elements. It
+// refers to the elements, which have to outlive it.
+template <typename T>
+class ArrayProxy {
+	uint32_t m_count;
+	const T *m_ptr;
+
+public:
+	ArrayProxy(): m_count(0), m_ptr(nullptr) {}
+	ArrayProxy(std::nullptr_t): m_count(0), m_ptr(nullptr) {}
+	ArrayProxy(const T &value): m_count(1), m_ptr(&value) {}
+	ArrayProxy(uint32_t count, const T *ptr): m_count(count), m_ptr(ptr) {}
+	// the list lives until the end of the full expression it's in, the call
+	ArrayProxy(const std::initializer_list<T> &list): m_count(static_cast<uint32_t>(list.size())), m_ptr(nullptr)
+	{
+		m_ptr = list.begin();
+	}
+	template <size_t N>
+	ArrayProxy(const std::array<T, N> &array): m_count(N), m_ptr(array.data()) {}
+	ArrayProxy(const std::vector<T> &vector): m_count(static_cast<uint32_t>(vector.size())), m_ptr(vector.data()) {}
+{{- if ge .CppStd 20 }}
+	template <typename U, size_t N>
+		requires std::is_same_v<std::remove_const_t<U>, T>
+	ArrayProxy(std::span<U, N> span): m_count(static_cast<uint32_t>(span.size())), m_ptr(span.data()) {}
+{{- end }}
+
+	uint32_t size() const { return m_count; }
+	bool empty() const { return m_count == 0; }
+	const T *data() const { return m_ptr; }
+	const T *begin() const { return m_ptr; }
+	const T *end() const { return m_ptr + m_count; }
+};
+
+// EnumValueCount<E>::value is the number of values of the enum E and
+// enumValues<E>() a constexpr std::array of them in declaration order,
+// aliases left out, to iterate over all formats, object types, etc.
+template <typename E>
+struct EnumValueCount;
+
+template <typename E>
+constexpr std::array<E, EnumValueCount<E>::value> enumValues();
+
+// TypeList<Ts...> is a list of types, like AllHandles and AllStructs.
+// TypeListContains<List, T>::value is true if T is in List, it compares T
+// with all of them at once instead of recursing over long lists.
+template <typename... Ts>
+struct TypeList {};
+
+template <bool...>
+struct BoolPack {};
+
+template <typename List, typename T>
+struct TypeListContains;
+template <typename... Ts, typename T>
+struct TypeListContains<TypeList<Ts...>, T>
+	: std::integral_constant<bool, !std::is_same<BoolPack<false, std::is_same<T, Ts>::value...>, BoolPack<std::is_same<T, Ts>::value..., false>>::value> {};
+
+// Formats a byte array as lowercase hex, UUIDs (16 bytes) use the canonical
+// 8-4-4-4-12 grouping.
+template <size_t N>
+inline std::string toHexString(const std::array<uint8_t, N> &bytes)
+{
+	static const char digits[] = "0123456789abcdef";
+	std::string out;
+	out.reserve(N * 2 + 4);
+	for (size_t i = 0; i < N; i++) {
+		if (N == 16 && (i == 4 || i == 6 || i == 8 || i == 10))
+			out += '-';
+		out += digits[bytes[i] >> 4];
+		out += digits[bytes[i] & 0xF];
+	}
+	return out;
+}
+{{- template "headerextra" . }}
+
+{{ end }}
+
+{{ define "headerextra" }}{{ end }}
+{{ define "handleextra" }}{{ end }}
+{{ define "structextra" }}{{ end }}
+
+
+
+
+
+
+
+
+
+
+{{ define "footer" }}
+
+{{ with .VersionNamespace }}} // inline namespace {{ . }}
+{{ end }}} // namespace {{ .Namespace }}
+{{- if .Handles }}{{ template "hashes" . }}{{ end }}
+{{ if .EpilogueIncludes }}
+{{ range .EpilogueIncludes }}#include {{ . }}
+{{ end }}
+{{- end }}
+{{- with .GuardEnd }}{{ . }}
+{{ end -}}
+
+{{ end }}
+
+
+
+
+
+{{ define "hashes" }}
+{{- "\n\n" -}}
+
+// handles hash their {{ .Namespace }}::X::handle(), to be usable as keys of
+// unordered containers
+namespace std {
+{{ range .Handles }}
+{{ line .Protect.Begin -}}
+template <> struct hash<{{ $.Namespace }}::{{ .Name }}> {
+	size_t operator()(const {{ $.Namespace }}::{{ .Name }} &h) const noexcept { return hash<{{ .VkName }}>()(h.handle()); }
+};
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{ end }}
+} // namespace std
+{{- end }}
+
+
+
+
+
+{{ define "ostream" -}}
+#ifdef VKGEN_OSTREAM
+inline std::ostream &operator<<(std::ostream &os, const {{ .Name }} &s)
+{
+	os << "{{ .Name }} {";
+{{- if .Union }}
+	os << ' ';
+	printArray(os, reinterpret_cast<const uint8_t*>(s.c_ptr()), sizeof({{ .VkName }}));
+{{- else }}
+{{- range $i, $m := .Members }}
+	os << "{{ if $i }},{{ end }} {{ $m.Accessor "" }}: ";
+	{{ $m.Print $.Aggregate }}
+{{- end }}
+{{- end }}
+	return os << " }";
+}
+#endif
+{{- end }}
+
+
+
+
+
+{{ define "reflect" -}}
+{{- /* reflect(visitor, s) calls visitor(name, value) for each member of the
+struct s in order, with the value its getter returns, but fixed arrays,
+which are passed as arrays. Unions aren't reflected. */ -}}
+{{ if not .Union -}}
+template <typename Visitor>
+inline void reflect(Visitor &&visitor, const {{ .Name }} &s)
+{
+{{- range .Members }}
+	visitor("{{ .Accessor "" }}", {{ .Reflect $.Aggregate }});
+{{- end }}
+}
+{{ end }}
+{{- end }}
+
+{{ define "layout" -}}
+static_assert(sizeof({{ .Name }}) == sizeof({{ .VkName }}), "{{ .Name }} doesn't match the layout of {{ .VkName }}");
+static_assert(std::is_standard_layout<{{ .Name }}>::value, "{{ .Name }} isn't standard-layout");
+{{- end }}
+
+
+
+
+
+{{ define "handle" -}}
+{{- "\n\n" -}}
+
+{{ line .Protect.Begin -}}
+{{ if .Parents -}}
+// {{ range .Ancestors }}{{ .Name }} > {{ end }}{{ .Name }}
+{{ range $i, $p := .Parents }}{{ if $i }}// also owned by {{ $p.Name }}
+{{ end }}{{ end -}}
+{{ end -}}
+class {{ .Name }} {
+	{{ .VkName }} m_handle;
+public:
+	{{ .Name }}() noexcept: m_handle(VK_NULL_HANDLE) {}
+	{{ .Name }}(NullHandle) noexcept: m_handle(VK_NULL_HANDLE) {}
+{{- if .TypeSafe }}
+	{{ .Name }}({{ .VkName }} handle) noexcept: m_handle(handle) {}
+	operator {{ .VkName }}() const noexcept { return m_handle; }
+{{- else }}
+#if VK_TYPESAFE_HANDLES
+	{{ .Name }}({{ .VkName }} handle) noexcept: m_handle(handle) {}
+	operator {{ .VkName }}() const noexcept { return m_handle; }
+#else
+	// {{ .VkName }} is a plain uint64_t here, shared by all non-dispatchable
+	// handles, use fromRaw() to construct
+	explicit operator {{ .VkName }}() const noexcept { return m_handle; }
+#endif
+	static {{ .Name }} fromRaw(uint64_t raw) noexcept { {{ .Name }} h; h.m_handle = reinterpret_cast<{{ .VkName }}>(raw); return h; }
+	uint64_t toRaw() const noexcept { return reinterpret_cast<uint64_t>(m_handle); }
+{{- end }}
+
+	{{ .VkName }} handle() const noexcept { return m_handle; }
+	{{ .VkName }} *c_ptr() noexcept { return &m_handle; }
+	const {{ .VkName }} *c_ptr() const noexcept { return &m_handle; }
+{{- if .Methods }}
+{{ range $m := .Methods }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+{{- range .Overloads }}
+{{- if .Throwing }}
+#ifndef VKGEN_NO_EXCEPTIONS
+{{- else if .NoExceptions }}
+#else
+{{- end }}
+	{{ if ne .RetType "void" }}VKGEN_NODISCARD {{ end }}{{ .RetType }} {{ $m.Name }}(
+		{{- range $i, $p := .Parameters }}{{ if $i }}, {{ end }}{{ $p.Type }} {{ $p.Name }}{{ end -}}
+	) const{{ with .Noexcept }} {{ . }}{{ end }};
+{{- if .NoExceptions }}
+#endif
+{{- end }}
+{{- end }}
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+{{- end }}
+{{- template "handleextra" . }}
+};
+{{ template "layout" . }}
+#ifdef VKGEN_OSTREAM
+inline std::ostream &operator<<(std::ostream &os, const {{ .Name }} &h)
+{
+	return printHandle(os, "{{ .Name }}", reinterpret_cast<uint64_t>(h.handle()));
+}
+#endif
+
+inline bool operator==(const {{ .Name }} &lhs, NullHandle) noexcept { return lhs.handle() == VK_NULL_HANDLE; }
+inline bool operator==(NullHandle, const {{ .Name }} &rhs) noexcept { return rhs.handle() == VK_NULL_HANDLE; }
+inline bool operator!=(const {{ .Name }} &lhs, NullHandle) noexcept { return lhs.handle() != VK_NULL_HANDLE; }
+inline bool operator!=(NullHandle, const {{ .Name }} &rhs) noexcept { return rhs.handle() != VK_NULL_HANDLE; }
+{{- if not .TypeSafe }}
+#if !VK_TYPESAFE_HANDLES
+// typesafe handles compare through the conversion to {{ .VkName }}
+inline bool operator==(const {{ .Name }} &lhs, const {{ .Name }} &rhs) noexcept { return lhs.handle() == rhs.handle(); }
+inline bool operator!=(const {{ .Name }} &lhs, const {{ .Name }} &rhs) noexcept { return lhs.handle() != rhs.handle(); }
+#endif
+{{- end }}
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+
+{{- end }}
+
+
+
+
+
+
+
+
+
+
+{{ define "enum" }}
+{{- "\n" -}}
+
+{{ line .Protect.Begin -}}
+enum class {{ .Name }}{{ with .Base }} : {{ . }}{{ end }} {
+{{- range .Values }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+	{{ .Name }} = {{ .VkName }},
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+{{- range .Aliases }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+	{{ .Name }} = {{ .Target }},
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+};
+
+{{ with $e := . -}}
+{{ if .StringArray -}}
+template <typename D>
+struct EnumStrings<{{ $e.Name }}, D> {
+	static constexpr const char *strings[] = {
+{{- range .StringArray }}
+		{{ if .Name }}"{{$e.Name}}::{{.Name}}"{{ else }}nullptr{{ end }},
+{{- end }}
+	};
+};
+#ifndef __cpp_inline_variables
+template <typename D>
+constexpr const char *EnumStrings<{{ $e.Name }}, D>::strings[];
+#endif
+
+constexpr const char *getEnumString({{ $e.Name }} e)
+{
+	return indexEnumString(EnumStrings<{{ $e.Name }}>::strings, sizeof(EnumStrings<{{ $e.Name }}>::strings) / sizeof(const char *), {{ .StringIndex }});
+}
+{{- else if .StringTable -}}
+template <typename D>
+struct EnumStrings<{{ $e.Name }}, D> {
+	static constexpr EnumString strings[] = {
+{{- range .StringTable }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+		{ {{ if $e.Base }}static_cast<int64_t>({{ .VkName }}){{ else }}{{ .VkName }}{{ end }}, "{{$e.Name}}::{{.Name}}" },
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+	};
+};
+#ifndef __cpp_inline_variables
+template <typename D>
+constexpr EnumString EnumStrings<{{ $e.Name }}, D>::strings[];
+#endif
+static_assert(enumStringsSorted(EnumStrings<{{ $e.Name }}>::strings, sizeof(EnumStrings<{{ $e.Name }}>::strings) / sizeof(EnumString)), "unsorted enum strings");
+
+constexpr const char *getEnumString({{ $e.Name }} e)
+{
+	return findEnumString(EnumStrings<{{ $e.Name }}>::strings, 0, sizeof(EnumStrings<{{ $e.Name }}>::strings) / sizeof(EnumString), static_cast<int64_t>(e));
+}
+{{- else -}}
+inline VKGEN_CONSTEXPR14 const char *getEnumString({{ $e.Name }} e)
+{
+	switch (e) {
+	{{ range .Values -}}
+	{{ line .Protect.Begin -}}
+	case {{$e.Name}}::{{.Name}}: return "{{$e.Name}}::{{.Name}}";
+	{{ line .Protect.End -}}
+	{{ end -}}
+	default: return "<invalid enum>";
+	}
+}
+{{- end }}
+{{- end }}
+
+inline std::string to_string({{ .Name }} e)
+{
+	return enumValueName(getEnumString(e));
+}
+#ifdef VKGEN_OSTREAM
+inline std::ostream &operator<<(std::ostream &os, {{ .Name }} e)
+{
+	return os << to_string(e);
+}
+#endif
+{{- if .HasUnguardedValue }}
+
+template <>
+struct EnumValueCount<{{ .Name }}> {
+	static constexpr size_t value =
+{{- range $i, $c := .ValueCounts }}
+{{- if $i }}
+{{ $c.Protect.Begin }}
+		+ {{ $c.Count }}
+{{ $c.Protect.End }}
+{{- else }} {{ $c.Count }}{{ end }}
+{{- end }}
+{{- if gt (len .ValueCounts) 1 }}
+		{{ end }};
+};
+
+template <>
+constexpr std::array<{{ .Name }}, EnumValueCount<{{ .Name }}>::value> enumValues<{{ .Name }}>()
+{
+	return {{ "{{" }}
+{{- range .Values }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+		{{ $.Name }}::{{ .Name }},
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+	{{ "}}" }};
+}
+{{- end }}
+
+{{ line .Protect.End -}}
+
+{{ end }}
+
+
+
+
+
+
+
+
+
+
+{{ define "bitmask" }}
+{{- "\n" -}}
+
+{{ line .Protect.Begin -}}
+{{ template "enum" .Enum }}
+using {{ .Name }} = Flags<{{ .Enum.Name }}, {{ .VkName }}>;
+
+template <typename D>
+struct FlagTraits<{{ .Enum.Name }}, D> {
+	static constexpr {{ .Name }} allFlags = {{ .Name }}()
+{{- $guarded := false }}
+{{- range .Enum.Values }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+		| {{ $.Enum.Name }}::{{ .Name }}
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- $guarded = ne .Protect.End "" }}
+{{- end }}
+{{- if $guarded }}
+		{{ end }};
+};
+#ifndef __cpp_inline_variables
+template <typename D>
+constexpr {{ .Name }} FlagTraits<{{ .Enum.Name }}, D>::allFlags;
+#endif
+
+inline {{ .Name }} operator|({{ .Enum.Name }} bit0, {{ .Enum.Name }} bit1)
+{
+	return {{ .Name }}(bit0) | bit1;
+}
+{{ line .Protect.End -}}
+
+{{ end }}
+
+
+
+
+
+
+
+
+{{ define "struct" }}
+{{- "\n" -}}
+
+{{ line .Protect.Begin -}}
+{{ with $s := . -}}
+class {{ .Name }} {
+	{{ .VkName }} m_struct;
+public:
+	{{- /* members are listed to initialize sType without leaving any out */}}
+	{{ if .TypeName -}}
+	constexpr {{ .Name }}() noexcept: m_struct{ {{ range $i, $m := .Members }}{{ if $i }}, {{ end }}{{ if eq $m.Name "sType" }}{{ $s.TypeName }}{{ else }}{}{{ end }}{{ end }} } {}
+	{{- else -}}
+	constexpr {{ .Name }}() noexcept: m_struct() {}
+	{{- end }}
+	constexpr {{ .Name }}(const {{ .VkName }} &r) noexcept: m_struct(r) {}
+	{{- with .Constructor }}
+	{{ if .Explicit }}explicit {{ end }}{{ $s.Name }}(
+		{{- range $i, $p := .Parameters -}}
+			{{ if $i }}, {{ end }}{{ $p.Type }} {{ $p.Name }}{{ with $p.Default }} = {{ . }}{{ end }}
+		{{- end -}}
+	) noexcept: {{ $s.Name }}()
+	{
+		{{- range .Parameters }}
+		{{ .Converter.CppToVk .AnalyzedType .Name (print "m_struct." .Name) }}
+		{{- end }}
+	}
+	{{- end }}
+
+	{{ range $m := .Members }}
+	{{ $constexpr := and $m.Constexpr (not $s.Union) -}}
+	{{ if $constexpr }}constexpr {{ end -}}
+	{{ if and (not $m.AnalyzedType.IsConst) $m.AnalyzedType.IsPointer }}const {{ end -}}
+	{{ $m.Type }} {{ $m.Accessor "" }}() const noexcept
+	{
+		{{ $m.Converter.VkToCpp $m.AnalyzedType (print "m_struct." $m.Name) }}
+	}
+	{{ if not $s.ReadOnly -}}
+	{{ if $constexpr }}VKGEN_CONSTEXPR14 {{ end }}{{ $s.Name }} &{{ $m.Accessor "" }}({{ $m.Type }} {{ $m.Name }}) noexcept
+	{
+		{{ $m.Converter.CppToVk $m.AnalyzedType $m.Name (print "m_struct." $m.Name) }}
+		return *this;
+	}
+	{{- if $m.IsString }}
+	// the struct points to the characters of the string, which has to
+	// outlive it, temporaries are rejected
+	{{ $s.Name }} &{{ $m.Accessor "" }}(const std::string &{{ $m.Name }}) noexcept
+	{
+		m_struct.{{ $m.Name }} = {{ $m.Name }}.c_str();
+		return *this;
+	}
+	{{ $s.Name }} &{{ $m.Accessor "" }}(std::string &&) = delete;
+	{{- end }}
+	{{- with $m.Span }}
+	{{ $s.Name }} &{{ .Name }}(std::span<{{ .Type }}> {{ .Name }}) noexcept
+	{
+		m_struct.{{ .Count }} = static_cast<uint32_t>({{ .Name }}.size());
+		{{ $m.Converter.CppToVk $m.AnalyzedType (print .Name ".data()") (print "m_struct." $m.Name) }}
+		return *this;
+	}
+	{{- end }}
+	{{- end -}}
+	{{ if $m.IsUUID }}
+	std::array<uint8_t, {{ $m.ArraySize }}> {{ $m.Accessor "Array" }}() const noexcept
+	{
+		std::array<uint8_t, {{ $m.ArraySize }}> out;
+		std::memcpy(out.data(), m_struct.{{ $m.Name }}, out.size());
+		return out;
+	}
+	std::string {{ $m.Accessor "String" }}() const
+	{
+		return toHexString({{ $m.Accessor "Array" }}());
+	}
+	{{ if not $s.ReadOnly -}}
+	{{ $s.Name }} &{{ $m.Accessor "" }}(const std::array<uint8_t, {{ $m.ArraySize }}> &{{ $m.Name }}) noexcept
+	{
+		std::memcpy(m_struct.{{ $m.Name }}, {{ $m.Name }}.data(), {{ $m.Name }}.size());
+		return *this;
+	}
+	{{- end -}}
+	{{ end }}
+	{{- if $m.IsVersion }}
+	Version {{ $m.Accessor "Unpacked" }}() const noexcept
+	{
+		return Version(m_struct.{{ $m.Name }});
+	}
+	{{ if not $s.ReadOnly -}}
+	{{ $s.Name }} &{{ $m.Accessor "" }}(Version {{ $m.Name }}) noexcept
+	{
+		m_struct.{{ $m.Name }} = {{ $m.Name }}.packed();
+		return *this;
+	}
+	{{- end -}}
+	{{ end }}
+	{{ end }}
+
+	{{ .VkName }} *c_ptr() noexcept { return &m_struct; }
+	constexpr const {{ .VkName }} *c_ptr() const noexcept { return &m_struct; }
+
+	constexpr operator const {{ .VkName }}&() const noexcept { return m_struct; }
+
+	{{ if .Union -}}
+	bool operator==(const {{ .Name }} &r) const noexcept
+	{
+		return std::memcmp(&m_struct, &r.m_struct, sizeof(m_struct)) == 0;
+	}
+	{{- else -}}
+	bool operator==(const {{ .Name }} &r) const noexcept
+	{
+		return {{ range $i, $m := .Members }}{{ if $i }} &&
+			{{ end }}{{ $m.Equal "m_struct." "r.m_struct." }}{{ end }};
+	}
+	{{- end }}
+	bool operator!=(const {{ .Name }} &r) const noexcept { return !(*this == r); }
+#ifdef __cpp_lib_three_way_comparison
+	{{ if .Union -}}
+	std::strong_ordering operator<=>(const {{ .Name }} &r) const noexcept
+	{
+		return std::memcmp(&m_struct, &r.m_struct, sizeof(m_struct)) <=> 0;
+	}
+	{{- else -}}
+	std::partial_ordering operator<=>(const {{ .Name }} &r) const noexcept
+	{
+		{{ range .Members -}}
+		if (auto c = {{ .ThreeWay "m_struct." "r.m_struct." }}; c != 0)
+			return c;
+		{{ end -}}
+		return std::partial_ordering::equivalent;
+	}
+	{{- end }}
+#endif
+{{- template "structextra" . }}
+};
+{{ template "layout" . }}
+{{ template "reflect" . }}
+{{ template "ostream" . }}
+{{- end }}
+{{ .Protect.End -}}
+
+{{ end }}
+
+
+
+
+
+{{ define "aggregate" }}
+{{- "\n" -}}
+
+{{ line .Protect.Begin -}}
+{{ with $s := . -}}
+struct {{ .Name }} {
+	{{- range .Members }}
+	{{ if and $s.TypeName (eq .Name "sType") -}}
+	StructureType {{ .Accessor "" }} = StructureType({{ $s.TypeName }});
+	{{- else -}}
+	{{ .Declaration }};
+	{{- end }}
+	{{- end }}
+
+	{{ .VkName }} *c_ptr() noexcept { return reinterpret_cast<{{ .VkName }}*>(this); }
+	const {{ .VkName }} *c_ptr() const noexcept { return reinterpret_cast<const {{ .VkName }}*>(this); }
+
+	operator const {{ .VkName }}&() const noexcept { return *c_ptr(); }
+
+	bool operator==(const {{ .Name }} &r) const noexcept
+	{
+		return {{ range $i, $m := .Members }}{{ if $i }} &&
+			{{ end }}{{ $m.Equal "c_ptr()->" "r.c_ptr()->" }}{{ end }};
+	}
+	bool operator!=(const {{ .Name }} &r) const noexcept { return !(*this == r); }
+#ifdef __cpp_lib_three_way_comparison
+	std::partial_ordering operator<=>(const {{ .Name }} &r) const noexcept
+	{
+		{{ range .Members -}}
+		if (auto c = {{ .ThreeWay "c_ptr()->" "r.c_ptr()->" }}; c != 0)
+			return c;
+		{{ end -}}
+		return std::partial_ordering::equivalent;
+	}
+#endif
+{{- template "structextra" . }}
+};
+{{ template "layout" . }}
+{{ template "reflect" . }}
+{{ template "ostream" . }}
+{{- end }}
+{{ .Protect.End -}}
+
+{{ end }}
+
+
+
+
+
+
+
+
+
+
+{{ define "command" }}
+{{- "\n" -}}
+
+{{ line .Protect.Begin -}}
+{{ if ne .RetType "void" }}VKGEN_NODISCARD {{ end }}inline {{ .RetType }} {{ .Name }}(
+	{{- range $i, $p := .Parameters -}}
+		{{if $i}}, {{end}}{{$p.Type}} {{$p.Name}}
+	{{- end -}}
+) {{ .Noexcept }}
+{
+	{{if ne .RetType "void"}}return {{end -}}
+	{{if .Throws}}checkResult({{end -}}
+	{{if eq .RetType "Result"}}Result({{end -}}
+	{{ .VkName }}(
+		{{- range $i, $p := .Parameters -}}
+		{{if $i}}, {{end}}{{ $p.Converter.CppToVkArg $p.AnalyzedType $p.Name }}
+		{{- end -}}
+	)
+	{{- if eq .RetType "Result"}}){{end -}}
+	{{- if .Throws}}, "{{ .VkName }}"){{end -}}
+	;
+}
+{{- with .Value }}
+{{- "\n" }}
+{{- if .Checked }}
+#ifndef VKGEN_NO_EXCEPTIONS
+{{- end }}
+{{- if or .Plain .Checked }}{{ template "valueoverload" .Variant false }}{{ end }}
+{{- if .Checked }}
+#else
+{{- end }}
+{{- if not .Plain }}{{ template "valueoverload" .Variant true }}{{ end }}
+{{- if .Checked }}
+#endif
+{{- end }}
+{{- if .Chain }}{{ "\n" }}{{ template "chainoverload" . }}{{ end }}
+{{- end }}
+{{- with .Arrays }}
+{{- "\n" }}
+{{ if ne .RetType "void" }}VKGEN_NODISCARD {{ end }}inline {{ .RetType }} {{ .Command }}(
+	{{- range $i, $p := .Parameters -}}
+		{{if $i}}, {{end}}{{$p.Type}} {{$p.Name}}
+	{{- end -}}
+) {{ .Noexcept }}
+{
+	{{- range .Asserts }}
+	assert({{ . }});
+	{{- end }}
+	{{ if ne .RetType "void" }}return {{ end }}{{ .Command }}(
+		{{- range $i, $a := .Arguments }}{{ if $i }}, {{ end }}{{ $a }}{{ end -}}
+	);
+}
+{{- end }}
+{{ line .Protect.End -}}
+
+{{ end }}
+
+
+
+
+
+
+
+
+
+
+
+
+{{ define "structchain" }}
+{{- "\n" -}}
+
+// StructExtends<X, Base>::value is true if X may be chained into the pNext
+// chain of Base.
+template <typename X, typename Base>
+struct StructExtends { static constexpr bool value = false; };
+{{ range .StructExtensions -}}
+{{ range .Protects }}{{ line .Begin }}{{ end -}}
+template <> struct StructExtends<{{ .Name }}, {{ .Base }}> { static constexpr bool value = true; };
+{{ range .Protects }}{{ line .End }}{{ end -}}
+{{ end }}
+template <typename Base, typename... Ts>
+struct StructsExtend { static constexpr bool value = true; };
+template <typename Base, typename T, typename... Ts>
+struct StructsExtend<Base, T, Ts...> {
+	static constexpr bool value = StructExtends<T, Base>::value && StructsExtend<Base, Ts...>::value;
+};
+
+template <typename T, typename... Ts>
+struct StructIndex;
+template <typename T, typename... Ts>
+struct StructIndex<T, T, Ts...> : std::integral_constant<size_t, 0> {};
+template <typename T, typename U, typename... Ts>
+struct StructIndex<T, U, Ts...> : std::integral_constant<size_t, 1 + StructIndex<T, Ts...>::value> {};
+
+// StructureChain holds Base and structs extending it, their pNext pointers
+// link them in order. The pNext of the last struct is left as is, copies
+// are relinked.
+template <typename Base, typename... Ts>
+{{- if ge .CppStd 20 }}
+	requires StructsExtend<Base, Ts...>::value
+class StructureChain {
+{{- else }}
+class StructureChain {
+	static_assert(StructsExtend<Base, Ts...>::value, "struct doesn't extend the chain's base struct");
+{{- end }}
+
+	std::tuple<Base, Ts...> m_structs;
+{{ if ge .CppStd 17 }}
+	template <size_t I = 0>
+	void link()
+	{
+		if constexpr (I < sizeof...(Ts)) {
+			std::get<I>(m_structs).c_ptr()->pNext = std::get<I + 1>(m_structs).c_ptr();
+			link<I + 1>();
+		}
+	}
+{{- else }}
+	template <size_t I = 0>
+	typename std::enable_if<I < sizeof...(Ts)>::type link()
+	{
+		std::get<I>(m_structs).c_ptr()->pNext = std::get<I + 1>(m_structs).c_ptr();
+		link<I + 1>();
+	}
+	template <size_t I = 0>
+	typename std::enable_if<I == sizeof...(Ts)>::type link() {}
+{{- end }}
+
+public:
+	StructureChain() { link(); }
+	StructureChain(const Base &base, const Ts &...structs): m_structs(base, structs...) { link(); }
+	StructureChain(const StructureChain &r): m_structs(r.m_structs) { link(); }
+
+	StructureChain &operator=(const StructureChain &r)
+	{
+		m_structs = r.m_structs;
+		link();
+		return *this;
+	}
+
+	template <typename T>
+	T &get() { return std::get<StructIndex<T, Base, Ts...>::value>(m_structs); }
+	template <typename T>
+	const T &get() const { return std::get<StructIndex<T, Base, Ts...>::value>(m_structs); }
+};
+{{ end }}
+
+{{ define "exceptions" }}
+{{- "\n" -}}
+
+#ifndef VKGEN_NO_EXCEPTIONS
+// Error is thrown by commands returning an error code (a negative Result),
+// unless VKGEN_NO_EXCEPTIONS is defined. Success codes (eIncomplete,
+// eNotReady, ...) are returned as usual.
+class Error : public std::runtime_error {
+	Result m_result;
+public:
+	Error(Result result, const char *command)
+		: std::runtime_error(std::string(command) + ": " + getEnumString(result)), m_result(result) {}
+
+	Result result() const noexcept { return m_result; }
+};
+#endif
+
+inline Result checkResult(Result result, const char *command) VKGEN_CHECKED_NOEXCEPT
+{
+#ifndef VKGEN_NO_EXCEPTIONS
+	if (static_cast<int32_t>(result) < 0)
+		throw Error(result, command);
+#else
+	(void)command;
+#endif
+	return result;
+}
+{{ end }}
+
+{{ define "valueoverload" }}
+VKGEN_NODISCARD inline {{ .ReturnType }} {{ .Command }}(
+	{{- range $i, $p := .Parameters -}}
+		{{if $i}}, {{end}}{{$p.Type}} {{$p.Name}}
+	{{- end -}}
+){{ with .Noexcept }} {{ . }}{{ end }}
+{
+	{{ .ReturnType }} {{ .Ret }};
+{{- if not .Count }}
+{{- if or .Result .Plain }}
+	{{ if .Result }}{{ .ResultVar }} = {{ end }}{{ .Command }}({{ range .Parameters }}{{ .Name }}, {{ end }}&{{ .ValueVar }});
+{{- else }}
+	// errors are thrown, VK_SUCCESS is the only other result
+	static_cast<void>({{ .Command }}({{ range .Parameters }}{{ .Name }}, {{ end }}&{{ .ValueVar }}));
+{{- end }}
+{{- else if .Plain }}
+	{{ .Count }} count = 0;
+	{{ .Command }}({{ range .Parameters }}{{ .Name }}, {{ end }}&count, nullptr);
+	value.resize(count);
+	{{ .Command }}({{ range .Parameters }}{{ .Name }}, {{ end }}&count, value.data());
+	value.resize(count);
+{{- else }}
+{{- if not .Result }}
+	Result result;
+{{- end }}
+	{{ .Count }} count;
+	do {
+		count = 0;
+		{{ .ResultVar }} = {{ .Command }}({{ range .Parameters }}{{ .Name }}, {{ end }}&count, nullptr);
+		if ({{ .ResultVar }} != Result(VK_SUCCESS))
+			break;
+		{{ .ValueVar }}.resize(count);
+		{{ .ResultVar }} = {{ .Command }}({{ range .Parameters }}{{ .Name }}, {{ end }}&count, {{ .ValueVar }}.data());
+	} while ({{ .ResultVar }} == Result(VK_INCOMPLETE));
+	{{ .ValueVar }}.resize(count);
+{{- end }}
+	return {{ .Ret }};
+}
+{{- end }}
+
+{{ define "chainoverload" }}
+template <typename... Ts>
+VKGEN_NODISCARD inline StructureChain<{{ .Type }}, Ts...> {{ .Command }}(
+	{{- range $i, $p := .Parameters -}}
+		{{if $i}}, {{end}}{{$p.Type}} {{$p.Name}}
+	{{- end -}}
+) noexcept
+{
+	StructureChain<{{ .Type }}, Ts...> chain;
+	{{ .Command }}({{ range .Parameters }}{{ .Name }}, {{ end }}&chain.template get<{{ .Type }}>());
+	return chain;
+}
+{{- end }}
+
+{{ define "methods" }}
+{{- with .Protect.Begin }}{{ "\n" }}{{ . }}{{ end }}
+{{- range $m := .Methods }}
+{{- with .Protect.Begin }}{{ "\n" }}{{ . }}{{ end }}
+{{- range .Overloads }}
+{{- if .NoExceptions }}#else{{ else if .Throwing }}{{ "\n" }}#ifndef VKGEN_NO_EXCEPTIONS{{ end }}
+inline {{ .RetType }} {{ $.Name }}::{{ $m.Name }}(
+	{{- range $i, $p := .Parameters }}{{ if $i }}, {{ end }}{{ $p.Type }} {{ $p.Name }}{{ end -}}
+) const{{ with .Noexcept }} {{ . }}{{ end }}
+{
+	{{ if ne .RetType "void" }}return {{ end }}{{ $m.Call }}(
+		{{- range $i, $a := .Arguments }}{{ if $i }}, {{ end }}{{ $a }}{{ end -}}
+	);
+}
+{{ if .NoExceptions }}#endif
+{{ end }}
+{{- end }}
+{{- with .Protect.End }}{{ . }}
+{{ end }}
+{{- end }}
+{{- with .Protect.End }}{{ . }}
+{{ end }}
+{{- end }}
+
+{{ define "forward" }}
+{{- "\n" -}}
+
+// declared ahead of the member functions of the handles taking them
+{{- if .HasResultValues }}
+template <typename T>
+struct ResultValue;
+{{- end }}
+{{- range .Handles }}
+class {{ .Name }};
+{{- end }}
+{{- range .Structs }}
+{{ if .Aggregate }}struct{{ else }}class{{ end }} {{ .Name }};
+{{- end }}
+{{- end }}
+
+{{ define "dispatch" }}
+{{- "\n" -}}
+
+// DispatchLoaderDynamic holds the commands loaded at runtime, for
+// applications which load the Vulkan library themselves or use commands the
+// loader doesn't export. It's initialized with vkGetInstanceProcAddr, which
+// loads the commands needing no instance, then with the instance, which
+// loads the others, and optionally with a device, whose commands then skip
+// the dispatch of the loader. The overloads of the commands taking it as the
+// last argument call through it.
+class DispatchLoaderDynamic {
+public:
+	PFN_vkGetInstanceProcAddr vkGetInstanceProcAddr = nullptr;
+	PFN_vkGetDeviceProcAddr vkGetDeviceProcAddr = nullptr;
+{{- range .Dispatch }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+	PFN_{{ .VkName }} {{ .VkName }} = nullptr;
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+
+	DispatchLoaderDynamic() {}
+	explicit DispatchLoaderDynamic(PFN_vkGetInstanceProcAddr getInstanceProcAddr) { init(getInstanceProcAddr); }
+
+	void init(PFN_vkGetInstanceProcAddr getInstanceProcAddr)
+	{
+		vkGetInstanceProcAddr = getInstanceProcAddr;
+{{- range .Dispatch }}{{ if .Global }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+		{{ .Load "vkGetInstanceProcAddr" "nullptr" }}
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}{{ end }}
+	}
+
+	void init(Instance instance)
+	{
+		VkInstance handle = instance.handle();
+		vkGetDeviceProcAddr = reinterpret_cast<PFN_vkGetDeviceProcAddr>(vkGetInstanceProcAddr(handle, "vkGetDeviceProcAddr"));
+{{- range .Dispatch }}{{ if not .Global }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+		{{ .Load "vkGetInstanceProcAddr" "handle" }}
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}{{ end }}
+	}
+
+	void init(Device device)
+	{
+		VkDevice handle = device.handle();
+{{- range .Dispatch }}{{ if .Device }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+		{{ .Load "vkGetDeviceProcAddr" "handle" }}
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}{{ end }}
+	}
+};
+{{ range .Commands -}}
+{{ render "dispatchcommand" . }}
+{{- end }}
+{{- end }}
+
+{{ define "dispatchcommand" }}
+{{- "\n" -}}
+
+{{ line .Protect.Begin -}}
+{{ if ne .RetType "void" }}VKGEN_NODISCARD {{ end }}inline {{ .RetType }} {{ .Name }}(
+	{{- range .Parameters -}}
+		{{ .Type }} {{ .Name }}, {{ end -}}
+	const DispatchLoaderDynamic &d) {{ .Noexcept }}
+{
+	{{if ne .RetType "void"}}return {{end -}}
+	{{if .Throws}}checkResult({{end -}}
+	{{if eq .RetType "Result"}}Result({{end -}}
+	d.{{ .VkName }}(
+		{{- range $i, $p := .Parameters -}}
+		{{if $i}}, {{end}}{{ $p.Converter.CppToVkArg $p.AnalyzedType $p.Name }}
+		{{- end -}}
+	)
+	{{- if eq .RetType "Result"}}){{end -}}
+	{{- if .Throws}}, "{{ .VkName }}"){{end -}}
+	;
+}
+{{ line .Protect.End -}}
+{{ end }}
+
+{{ define "debugname" }}
+{{- "\n" -}}
+
+{{ line .Protect.Begin -}}
+// {{ .Name }} names handle in validation messages and debuggers like
+// RenderDoc, handle is any handle class with an ObjectType.
+{{- if .Dispatch }}
+#ifndef VK_NO_PROTOTYPES
+{{- end }}
+template <typename T>
+inline Result {{ .Name }}(Device device, T handle, const char *name) {{ .Noexcept }}
+{
+	{{- template "debugnameinfo" }}
+	return {{ .Command }}(device, reinterpret_cast<const DebugUtilsObjectNameInfoEXT*>(&info));
+}
+{{- if .Dispatch }}
+#endif // VK_NO_PROTOTYPES
+
+template <typename T>
+inline Result {{ .Name }}(Device device, T handle, const char *name, const DispatchLoaderDynamic &d) {{ .Noexcept }}
+{
+	{{- template "debugnameinfo" }}
+	return {{ .Command }}(device, reinterpret_cast<const DebugUtilsObjectNameInfoEXT*>(&info), d);
+}
+{{- end }}
+{{ line .Protect.End -}}
+{{ end }}
+
+{{ define "debugnameinfo" }}
+	VkDebugUtilsObjectNameInfoEXT info = {};
+	info.sType = VK_STRUCTURE_TYPE_DEBUG_UTILS_OBJECT_NAME_INFO_EXT;
+	info.objectType = static_cast<VkObjectType>(ObjectTypeTraits<T>::value);
+	info.objectHandle = reinterpret_cast<uint64_t>(handle.handle());
+	info.pObjectName = name;
+{{- end }}
+
+{{ define "resultvalue" }}
+{{- "\n" -}}
+
+// ResultValue is returned by the overloads of commands returning their
+// output parameter, value is valid if result is a success code.
+template <typename T>
+struct ResultValue {
+	Result result;
+	T value;
+};
+{{ end }}
+
+{{ define "spirv" }}
+{{- "\n" -}}
+
+struct SpirvEnable {
+	const char *version;
+	const char *extension;
+	const char *featureStruct;
+	const char *feature;
+	const char *propertyStruct;
+	const char *member;
+	const char *value;
+	const char *requirements;
+};
+
+struct SpirvRequirement {
+	const char *name;
+	const SpirvEnable *enables;
+	size_t enableCount;
+};
+{{ range .SpirvTables }}{{ if .Entries }}{{ with $t := . }}
+{{- if .EnableCount }}
+{{ if $.Module }}inline {{ end }}constexpr SpirvEnable spirv{{ .Name }}Enables[] = {
+	{{- range .Entries }}{{ range .Enables }}
+	{ {{ cstr .Version }}, {{ cstr .Extension }}, {{ cstr .Struct }}, {{ cstr .Feature }}, {{ cstr .Property }}, {{ cstr .Member }}, {{ cstr .Value }}, {{ cstr .Requires }} },
+	{{- end }}{{ end }}
+};
+{{ end }}
+{{ if $.Module }}inline {{ end }}constexpr SpirvRequirement spirv{{ .Plural }}[] = {
+	{{- range .Entries }}
+	{ {{ cstr .Name }}, {{ if $t.EnableCount }}spirv{{ $t.Name }}Enables + {{ .Offset }}{{ else }}nullptr{{ end }}, {{ len .Enables }} },
+	{{- end }}
+};
+
+inline const SpirvRequirement *findSpirv{{ .Name }}(const char *name)
+{
+	for (const SpirvRequirement &r : spirv{{ .Plural }}) {
+		if (std::strcmp(r.name, name) == 0)
+			return &r;
+	}
+	return nullptr;
+}
+{{ end }}{{ end }}{{ end }}
+{{ end }}
+
+
+
+
+
+
+
+
+
+
+
+
+{{ define "unique" }}
+{{- "\n" -}}
+
+{{ line .Protect.Begin -}}
+{{ with $u := . -}}
+// {{ .Name }} destroys the {{ .Handle.Name }} it owns with {{ .Destroy }}.
+class {{ .Name }} {
+	{{ .Handle.Name }} m_handle;
+{{- range .Owners }}
+	{{ .Type }} m_{{ .Name }};
+{{- end }}
+{{- if .Allocator }}
+	const AllocationCallbacks *m_allocator;
+{{- end }}
+public:
+	{{ .Name }}(): m_handle(){{ range .Owners }}, m_{{ .Name }}(){{ end }}{{ if .Allocator }}, m_allocator(nullptr){{ end }} {}
+	explicit {{ .Name }}({{ .Handle.Name }} handle{{ range .Owners }}, {{ .Type }} {{ .Name }}{{ end }}{{ if .Allocator }}, const AllocationCallbacks *allocator = nullptr{{ end }})
+		: m_handle(handle){{ range .Owners }}, m_{{ .Name }}({{ .Name }}){{ end }}{{ if .Allocator }}, m_allocator(allocator){{ end }} {}
+	{{ .Name }}(const {{ .Name }} &) = delete;
+	{{ .Name }}({{ .Name }} &&r) noexcept
+		: m_handle(r.release()){{ range .Owners }}, m_{{ .Name }}(r.m_{{ .Name }}){{ end }}{{ if .Allocator }}, m_allocator(r.m_allocator){{ end }} {}
+	~{{ .Name }}() { reset(); }
+
+	{{ .Name }} &operator=(const {{ .Name }} &) = delete;
+	{{ .Name }} &operator=({{ .Name }} &&r) noexcept
+	{
+		if (this != &r) {
+			reset();
+{{- range .Owners }}
+			m_{{ .Name }} = r.m_{{ .Name }};
+{{- end }}
+{{- if .Allocator }}
+			m_allocator = r.m_allocator;
+{{- end }}
+			m_handle = r.release();
+		}
+		return *this;
+	}
+
+	{{ .Handle.Name }} get() const { return m_handle; }
+	{{ .Handle.Name }} operator*() const { return m_handle; }
+	const {{ .Handle.Name }} *operator->() const { return &m_handle; }
+	explicit operator bool() const { return m_handle != nullHandle; }
+{{- range .Owners }}
+	{{ .Type }} {{ .Name }}() const { return m_{{ .Name }}; }
+{{- end }}
+
+	// gives up ownership without destroying the handle
+	{{ .Handle.Name }} release()
+	{
+		{{ .Handle.Name }} handle = m_handle;
+		m_handle = nullHandle;
+		return handle;
+	}
+
+	void reset({{ .Handle.Name }} handle = nullHandle)
+	{
+		if (m_handle != nullHandle)
+			{{ .Destroy }}({{ range .Owners }}m_{{ .Name }}, {{ end }}
+			{{- if .Array }}1, &m_handle{{ else }}m_handle{{ if .Allocator }}, m_allocator{{ end }}{{ end }});
+		m_handle = handle;
+	}
+};
+{{ end -}}
+{{ line .Protect.End -}}
+
+{{ end }}
+
+
+
+
+
+
+
+
+
+
+
+
+{{ define "affixedtypes" }}
+{{- "\n" -}}
+
+// Names of the types with the chosen prefix and suffix. They come last, the
+// code above refers to the C types they may hide.
+{{ range .AffixedTypes -}}
+{{ line .Protect.Begin -}}
+using {{ .Name }} = {{ .Target }};
+{{ line .Protect.End -}}
+{{ end }}
+{{- end }}
+
+
+
+
+
+{{ define "aliases" }}
+{{- "\n" -}}
+
+// Other names of types and commands, mostly the extension names of things
+// promoted to core.
+{{ range .TypeAliases -}}
+{{ line .Protect.Begin -}}
+using {{ .Name }} = {{ .Target }};
+{{ line .Protect.End -}}
+{{ end }}
+{{- range .CommandAliases -}}
+{{ line .Protect.Begin -}}
+// forwards to all overloads of {{ .Target }}
+template <typename... Args>
+inline auto {{ .Name }}(Args&&... args) -> decltype({{ .Target }}(std::forward<Args>(args)...))
+{
+	return {{ .Target }}(std::forward<Args>(args)...);
+}
+{{ line .Protect.End -}}
+{{ end }}
+{{- end }}
+
+
+
+
+
+
+
+
+
+
+
+
+{{ define "extensions" }}
+{{- "\n" -}}
+
+// Generated extensions and their dependencies. depends is the dependency
+// expression of the registry ('+' is AND, ',' is OR, parentheses group),
+// dependencies are the extensions and versions it mentions.
+struct ExtensionInfo {
+	const char *name;
+	uint32_t number;
+	const char *depends;
+	const char *const *dependencies;
+	size_t dependencyCount;
+};
+
+{{ if $.Module }}inline {{ end }}constexpr const char *extensionDependencies[] = {
+	{{- range .Extensions }}{{ range .Dependencies }}
+	{{ cstr . }},
+	{{- end }}{{ end }}
+	nullptr,
+};
+
+{{ if $.Module }}inline {{ end }}constexpr ExtensionInfo extensions[] = {
+	{{- range .Extensions }}
+	{ {{ cstr .Name }}, {{ .Number }}, {{ cstr .Depends }}, extensionDependencies + {{ .Offset }}, {{ len .Dependencies }} },
+	{{- end }}
+};
+
+inline const ExtensionInfo *findExtension(const char *name)
+{
+	for (const ExtensionInfo &e : extensions) {
+		if (std::strcmp(e.name, name) == 0)
+			return &e;
+	}
+	return nullptr;
+}
+{{ end }}
+
+
+
+
+
+
+
+
+
+
+
+
+{{ define "sync" }}
+{{- "\n" -}}
+
+// Synchronization tables, generated from the <sync> section of the registry.
+// Queues of 0 means the stage is supported on all queues.
+struct SyncStageInfo {
+	VkPipelineStageFlags2 stage;
+	VkQueueFlags queues;
+	VkPipelineStageFlags2 equivalent;
+};
+
+struct SyncAccessInfo {
+	VkAccessFlags2 access;
+	VkPipelineStageFlags2 stages;
+	VkAccessFlags2 equivalent;
+};
+
+struct SyncPipelineInfo {
+	const char *name;
+	const VkPipelineStageFlags2 *stages;
+	size_t stageCount;
+};
+{{ if .Stages }}
+{{ if $.Module }}inline {{ end }}constexpr SyncStageInfo syncStages[] = {
+	{{- range .Stages }}
+	{ {{ .Name }}, {{ orMask .Queues }}, {{ orMask .Equivalent }} },
+	{{- end }}
+};
+
+inline VkPipelineStageFlags2 expandPipelineStages(VkPipelineStageFlags2 stages)
+{
+	for (const SyncStageInfo &s : syncStages) {
+		if (stages & s.stage)
+			stages |= s.equivalent;
+	}
+	return stages;
+}
+
+inline VkQueueFlags supportedQueuesForStages(VkPipelineStageFlags2 stages)
+{
+	VkQueueFlags queues = ~VkQueueFlags(0);
+	for (const SyncStageInfo &s : syncStages) {
+		if ((stages & s.stage) && s.queues != 0)
+			queues &= s.queues;
+	}
+	return queues;
+}
+{{ end }}
+{{- if .Accesses }}
+{{ if $.Module }}inline {{ end }}constexpr SyncAccessInfo syncAccesses[] = {
+	{{- range .Accesses }}
+	{ {{ .Name }}, {{ if .Stages }}{{ orMask .Stages }}{{ else }}VK_PIPELINE_STAGE_2_ALL_COMMANDS_BIT{{ end }}, {{ orMask .Equivalent }} },
+	{{- end }}
+};
+
+inline VkAccessFlags2 expandAccesses(VkAccessFlags2 accesses)
+{
+	for (const SyncAccessInfo &a : syncAccesses) {
+		if (accesses & a.access)
+			accesses |= a.equivalent;
+	}
+	return accesses;
+}
+
+inline VkAccessFlags2 allowedAccessesForStage(VkPipelineStageFlags2 stages)
+{
+	{{ if .Stages }}stages = expandPipelineStages(stages);
+	{{ end -}}
+	VkAccessFlags2 accesses = 0;
+	for (const SyncAccessInfo &a : syncAccesses) {
+		if (a.stages & stages)
+			accesses |= a.access;
+	}
+	return accesses;
+}
+
+inline VkPipelineStageFlags2 allowedStagesForAccess(VkAccessFlags2 accesses)
+{
+	accesses = expandAccesses(accesses);
+	VkPipelineStageFlags2 stages = 0;
+	for (const SyncAccessInfo &a : syncAccesses) {
+		if (a.access & accesses)
+			stages |= a.stages;
+	}
+	return stages;
+}
+{{ end }}
+{{- if .Pipelines }}
+{{ if $.Module }}inline {{ end }}constexpr VkPipelineStageFlags2 syncPipelineStages[] = {
+	{{- range .Pipelines }}{{ range .Stages }}
+	{{ . }},
+	{{- end }}{{ end }}
+};
+
+{{ if $.Module }}inline {{ end }}constexpr SyncPipelineInfo syncPipelines[] = {
+	{{- range $i, $p := .Pipelines }}
+	{ {{ cstr $p.Name }}, syncPipelineStages + {{ $.PipelineStageOffset $i }}, {{ len $p.Stages }} },
+	{{- end }}
+};
+{{ end }}
+{{ end }}
+
+
+
+
+
+
+
+
+
+
+
+
+{{ define "serialize" }}
+{{- "\n" -}}
+
+enum class ByteOrder {
+	eLittleEndian,
+	eBigEndian,
+};
+
+// Writes values to a byte vector in an explicitly specified byte order,
+// independent of the host one.
+class BlobWriter {
+	std::vector<uint8_t> &m_out;
+	ByteOrder m_order;
+
+	void put(uint64_t v, size_t n)
+	{
+		for (size_t i = 0; i < n; i++) {
+			size_t shift = m_order == ByteOrder::eLittleEndian ? i : n - 1 - i;
+			m_out.push_back(static_cast<uint8_t>(v >> (shift * 8)));
+		}
+	}
+public:
+	BlobWriter(std::vector<uint8_t> &out, ByteOrder order = ByteOrder::eLittleEndian):
+		m_out(out), m_order(order) {}
+
+	void u8(uint8_t v) { m_out.push_back(v); }
+	void u16(uint16_t v) { put(v, 2); }
+	void u32(uint32_t v) { put(v, 4); }
+	void u64(uint64_t v) { put(v, 8); }
+	void f32(float v) { uint32_t u; std::memcpy(&u, &v, sizeof(u)); put(u, 4); }
+	void f64(double v) { uint64_t u; std::memcpy(&u, &v, sizeof(u)); put(u, 8); }
+	void bytes(const void *data, size_t n)
+	{
+		const uint8_t *p = static_cast<const uint8_t*>(data);
+		m_out.insert(m_out.end(), p, p + n);
+	}
+};
+
+// Reads values written by BlobWriter, reading past the end of the data sets
+// the error state and yields zeroes.
+class BlobReader {
+	const uint8_t *m_data;
+	size_t m_size;
+	size_t m_pos;
+	ByteOrder m_order;
+	bool m_ok;
+
+	bool check(size_t n)
+	{
+		if (m_size - m_pos < n) {
+			m_ok = false;
+			m_pos = m_size;
+			return false;
+		}
+		return true;
+	}
+
+	uint64_t get(size_t n)
+	{
+		if (!check(n))
+			return 0;
+		uint64_t v = 0;
+		for (size_t i = 0; i < n; i++) {
+			size_t shift = m_order == ByteOrder::eLittleEndian ? i : n - 1 - i;
+			v |= uint64_t(m_data[m_pos + i]) << (shift * 8);
+		}
+		m_pos += n;
+		return v;
+	}
+public:
+	BlobReader(const void *data, size_t size, ByteOrder order = ByteOrder::eLittleEndian):
+		m_data(static_cast<const uint8_t*>(data)), m_size(size), m_pos(0), m_order(order), m_ok(true) {}
+
+	uint8_t u8() { return static_cast<uint8_t>(get(1)); }
+	uint16_t u16() { return static_cast<uint16_t>(get(2)); }
+	uint32_t u32() { return static_cast<uint32_t>(get(4)); }
+	uint64_t u64() { return get(8); }
+	float f32() { uint32_t u = u32(); float v; std::memcpy(&v, &u, sizeof(v)); return v; }
+	double f64() { uint64_t u = u64(); double v; std::memcpy(&v, &u, sizeof(v)); return v; }
+	void bytes(void *data, size_t n)
+	{
+		if (!check(n)) {
+			std::memset(data, 0, n);
+			return;
+		}
+		std::memcpy(data, m_data + m_pos, n);
+		m_pos += n;
+	}
+
+	bool ok() const { return m_ok; }
+	size_t position() const { return m_pos; }
+};
+{{ range .Serializers }}
+inline void serialize(BlobWriter &w, const {{ .VkName }} &s)
+{
+	{{- range .Members }}
+	{{ if .IsBytes -}}
+	w.bytes(s.{{ .Name }}, {{ .Size }});
+	{{- else if .Size -}}
+	for (size_t i = 0; i < {{ .Size }}; i++)
+		{{ if .IsStruct }}serialize(w, s.{{ .Name }}[i]);{{ else }}w.{{ .Op }}(static_cast<{{ .OpType }}>(s.{{ .Name }}[i]));{{ end }}
+	{{- else if .IsStruct -}}
+	serialize(w, s.{{ .Name }});
+	{{- else -}}
+	w.{{ .Op }}(static_cast<{{ .OpType }}>(s.{{ .Name }}));
+	{{- end }}
+	{{- end }}
+}
+
+inline bool deserialize(BlobReader &r, {{ .VkName }} &s)
+{
+	{{- if .HasPNext }}
+	s.pNext = nullptr;
+	{{- end }}
+	{{- range .Members }}
+	{{ if .IsBytes -}}
+	r.bytes(s.{{ .Name }}, {{ .Size }});
+	{{- else if .Size -}}
+	for (size_t i = 0; i < {{ .Size }}; i++)
+		{{ if .IsStruct }}deserialize(r, s.{{ .Name }}[i]);{{ else }}s.{{ .Name }}[i] = static_cast<{{ .VkType }}>(r.{{ .Op }}());{{ end }}
+	{{- else if .IsStruct -}}
+	deserialize(r, s.{{ .Name }});
+	{{- else -}}
+	s.{{ .Name }} = static_cast<{{ .VkType }}>(r.{{ .Op }}());
+	{{- end }}
+	{{- end }}
+	return r.ok();
+}
+
+inline bool deserialize(BlobReader &r, {{ .Name }} &s)
+{
+	return deserialize(r, *s.c_ptr());
+}
+{{ end }}
+{{ end }}
+
+
+
+
+
+
+
+
+
+
+
+
+{{ define "json" }}
+{{- "\n" -}}
+
+// to_json(j, s) and from_json(j, s) convert the structs of -json-structs to
+// and from JSON, keyed by their C member names. They are templates of the
+// JSON type and nlohmann::json finds them through ADL (json j = info;
+// info = j.get<{{ .Namespace }}::SamplerCreateInfo>()), other types work if
+// they have its object(), array(), operator[], find(), end(), push_back(),
+// size(), is_number() and get<T>(). Enums are written by name, flags as
+// arrays of the names of their bits, values and bits without a name as
+// numbers, handles as their raw value and char arrays as strings. sType and
+// pNext aren't written, pNext is read as nullptr. Members missing from the
+// JSON keep their value, so do enums and bits of unknown names.
+
+template <typename Json, typename E>
+inline Json enumToJson(E e)
+{
+	const char *s = getEnumString(e);
+	if (std::strstr(s, "::"))
+		return Json(enumValueName(s));
+	return Json(static_cast<int64_t>(e));
+}
+
+template <typename Json, typename E>
+inline bool enumFromJson(const Json &j, E &e)
+{
+	if (j.is_number()) {
+		e = static_cast<E>(j.template get<int64_t>());
+		return true;
+	}
+	std::string s = j.template get<std::string>();
+	for (E v : enumValues<E>()) {
+		if (s == enumValueName(getEnumString(v))) {
+			e = v;
+			return true;
+		}
+	}
+	return false;
+}
+
+template <typename Json, typename EnumType, typename T>
+inline Json flagsToJson(const Flags<EnumType, T> &flags)
+{
+	Json j = Json::array();
+	T mask = static_cast<T>(flags);
+	for (size_t i = 0; i < sizeof(T) * 8; i++) {
+		T bit = T(1) << i;
+		if (!(mask & bit))
+			continue;
+		const char *s = getEnumString(static_cast<EnumType>(bit));
+		if (std::strstr(s, "::"))
+			j.push_back(Json(enumValueName(s)));
+		else
+			j.push_back(Json(bit));
+	}
+	return j;
+}
+
+template <typename Json, typename EnumType, typename T>
+inline void flagsFromJson(const Json &j, Flags<EnumType, T> &flags)
+{
+	if (j.is_number()) {
+		flags = Flags<EnumType, T>(j.template get<T>());
+		return;
+	}
+	Flags<EnumType, T> out;
+	for (size_t i = 0; i < j.size(); i++) {
+		EnumType bit = EnumType();
+		if (enumFromJson(j[i], bit))
+			out |= bit;
+	}
+	flags = out;
+}
+
+template <typename Json, size_t N>
+inline Json charsToJson(const char (&s)[N])
+{
+	const char *end = static_cast<const char*>(std::memchr(s, 0, N));
+	return Json(std::string(s, end ? end : s + N));
+}
+
+template <typename Json, size_t N>
+inline void charsFromJson(const Json &j, char (&s)[N])
+{
+	std::string v = j.template get<std::string>();
+	size_t n = v.size() < N ? v.size() : N - 1;
+	std::memcpy(s, v.data(), n);
+	s[n] = '\0';
+}
+
+// jsonMember returns the member of the object j called name, nullptr if it
+// has none.
+template <typename Json>
+inline const Json *jsonMember(const Json &j, const char *name)
+{
+	auto it = j.find(name);
+	return it != j.end() ? &*it : nullptr;
+}
+{{ range .JSONStructs }}
+{{ line .Protect.Begin -}}
+template <typename Json>
+inline void to_json(Json &j, const {{ .Name }} &s)
+{
+	j = Json::object();
+	{{- if .Members }}
+	const {{ .VkName }} &c = *s.c_ptr();
+	{{- else }}
+	(void)s;
+	{{- end }}
+	{{- range .Members }}
+	{{- range .ToJSON }}
+	{{ . }}
+	{{- end }}
+	{{- end }}
+}
+
+template <typename Json>
+inline void from_json(const Json &j, {{ .Name }} &s)
+{
+	{{- if or .Members .HasPNext }}
+	{{ .VkName }} &c = *s.c_ptr();
+	{{- else }}
+	(void)s;
+	{{- end }}
+	{{- if not .Members }}
+	(void)j;
+	{{- end }}
+	{{- if .HasPNext }}
+	c.pNext = nullptr;
+	{{- end }}
+	{{- range .Members }}
+	if (const Json *v = jsonMember(j, "{{ .Name }}")) {
+	{{- range .FromJSON }}
+		{{ . }}
+	{{- end }}
+	}
+	{{- end }}
+}
+{{ line .Protect.End -}}
+{{ end }}
+{{ end }}
+
+
+
+
+
+
+
+
+
+
+
+
+{{ define "handleparents" }}
+{{- "\n\n" -}}
+
+// ParentHandle<T>::type is the handle T is created from and owned by, or void
+// for root handles.
+template <typename T>
+struct ParentHandle { using type = void; };
+{{ range $h := . }}{{ with $h.Parent }}
+{{- with $h.Protect.Begin }}
+{{ . }}{{ end }}
+template <> struct ParentHandle<{{ $h.Name }}> { using type = {{ .Name }}; };
+{{- with $h.Protect.End }}
+{{ . }}{{ end }}
+{{- end }}{{ end }}
+{{ end }}
+
+
+
+
+
+
+
+
+
+
+
+
+{{ define "typelist" -}}
+TypeList<
+{{- range $i, $t := . }}
+{{- with $t.Protect.Begin }}
+{{ . }}{{ end }}
+	{{ if $i }}, {{ end }}{{ $t.Name }}
+{{- with $t.Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+>
+{{- end }}
+
+{{ define "handletraits" -}}
+using AllHandles = {{ template "typelist" .HandleList }};
+
+// IsHandle<T>::value is true if T is a handle class.
+template <typename T>
+struct IsHandle : TypeListContains<AllHandles, T> {};
+{{- if ge .CppStd 14 }}
+template <typename T>
+{{ if ge .CppStd 17 }}inline {{ end }}constexpr bool isHandle = IsHandle<T>::value;
+{{- end }}
+{{ end }}
+
+{{ define "structtraits" }}
+using AllStructs = {{ template "typelist" .StructList }};
+
+// IsStruct<T>::value is true if T is a struct (or union) class,
+// HasSType<T>::value if it has an sType.
+template <typename T>
+struct IsStruct : TypeListContains<AllStructs, T> {};
+
+template <typename T>
+struct HasSType : std::false_type {};
+{{ range .Structs }}{{ if .HasSType }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+template <> struct HasSType<{{ .Name }}> : std::true_type {};
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}{{ end }}
+
+// CppType<StructureType::eX>::Type is the struct whose sType is eX,
+// getStructureType<T>() the sType of struct T.
+template <StructureType S>
+struct CppType;
+
+template <typename T>
+constexpr StructureType getStructureType() = delete;
+{{ range .Structs }}{{ if .SType }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+template <> struct CppType<StructureType::{{ .SType }}> { using Type = {{ .Name }}; };
+template <> constexpr StructureType getStructureType<{{ .Name }}>() { return StructureType::{{ .SType }}; }
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}{{ end }}
+{{- if ge .CppStd 14 }}
+
+template <typename T>
+{{ if ge .CppStd 17 }}inline {{ end }}constexpr bool isStruct = IsStruct<T>::value;
+template <typename T>
+{{ if ge .CppStd 17 }}inline {{ end }}constexpr bool hasSType = HasSType<T>::value;
+{{- end }}
+{{ end }}
+
+{{ define "safestructs" }}
+namespace safe {
+
+// The structs of this namespace are deep copies of the structs of the same
+// name, for keeping create infos and the like beyond the call they were
+// passed to. They have the layout of the C struct and own the arrays,
+// strings and pNext chains it points to: copying a struct copies them,
+// destroying it frees them. Chained structs without a safe variant are left
+// out of the copy, void pointers without a size (pUserData) and types of
+// other headers are copied as they are.
+
+// ChainHeader starts every struct of a pNext chain.
+struct ChainHeader {
+	VkStructureType sType;
+	const void *pNext;
+};
+
+inline void *copyPNext(const void *pNext);
+inline void freePNext(const void *pNext);
+
+template <typename T>
+inline T *copyArray(const T *src, size_t n)
+{
+	if (!src || n == 0)
+		return nullptr;
+	T *out = new T[n];
+	std::memcpy(out, src, n * sizeof(T));
+	return out;
+}
+
+inline void *copyBytes(const void *src, size_t n)
+{
+	return copyArray(static_cast<const char *>(src), n);
+}
+
+inline char *copyString(const char *src)
+{
+	return src ? copyArray(src, std::strlen(src) + 1) : nullptr;
+}
+
+inline const char **copyStrings(const char *const *src, size_t n)
+{
+	if (!src || n == 0)
+		return nullptr;
+	const char **out = new const char *[n];
+	for (size_t i = 0; i < n; i++)
+		out[i] = copyString(src[i]);
+	return out;
+}
+
+inline void freeStrings(const char *const *strings, size_t n)
+{
+	if (!strings)
+		return;
+	for (size_t i = 0; i < n; i++)
+		delete[] strings[i];
+	delete[] strings;
+}
+
+// copyStructArray copies an array of C structs into an array of their
+// safe variants, which is returned as the C array.
+template <typename Safe, typename T>
+inline T *copyStructArray(const T *src, size_t n)
+{
+	if (!src || n == 0)
+		return nullptr;
+	Safe *out = new Safe[n];
+	for (size_t i = 0; i < n; i++)
+		out[i] = src[i];
+	return out->c_ptr();
+}
+{{ range .SafeStructs -}}
+{{ render "safestruct" . }}
+{{- end }}
+
+inline void *copyPNext(const void *pNext)
+{
+	if (!pNext)
+		return nullptr;
+	const ChainHeader *header = static_cast<const ChainHeader *>(pNext);
+	switch (header->sType) {
+	{{ range .SafeStructs }}{{ if .TypeName -}}
+	{{ line .Protect.Begin -}}
+	case {{ .TypeName }}: return new {{ .Name }}(*static_cast<const {{ .VkName }} *>(pNext));
+	{{ line .Protect.End -}}
+	{{ end }}{{ end -}}
+	default: return copyPNext(header->pNext);
+	}
+}
+
+inline void freePNext(const void *pNext)
+{
+	if (!pNext)
+		return;
+	switch (static_cast<const ChainHeader *>(pNext)->sType) {
+	{{ range .SafeStructs }}{{ if .TypeName -}}
+	{{ line .Protect.Begin -}}
+	case {{ .TypeName }}: delete static_cast<const {{ .Name }} *>(pNext); break;
+	{{ line .Protect.End -}}
+	{{ end }}{{ end -}}
+	default: break;
+	}
+}
+
+} // namespace safe
+{{ end }}
+
+{{ define "safestruct" }}
+{{- "\n" -}}
+
+{{ line .Protect.Begin -}}
+class {{ .Name }} {
+	{{ .VkName }} m_struct;
+
+	void copy(const {{ .VkName }} &src)
+	{
+		m_struct = src;
+		{{- range .Members }}
+		{{ .Copy }}
+		{{- end }}
+	}
+
+public:
+	{{- if .TypeName }}
+	{{ .Name }}() noexcept: m_struct() { m_struct.sType = {{ .TypeName }}; }
+	{{- else }}
+	{{ .Name }}() noexcept: m_struct() {}
+	{{- end }}
+	{{ .Name }}(const {{ .VkName }} &src) { copy(src); }
+	{{ .Name }}(const {{ .Wrapper }} &src) { copy(src); }
+	{{ .Name }}(const {{ .Name }} &rhs) { copy(rhs.m_struct); }
+	{{ .Name }}({{ .Name }} &&rhs) noexcept: m_struct(rhs.m_struct) { rhs.m_struct = {{ .VkName }}(); }
+	~{{ .Name }}()
+	{
+		{{- range .Members }}
+		{{ .Free }}
+		{{- end }}
+	}
+
+	{{ .Name }} &operator=({{ .Name }} rhs) noexcept
+	{
+		std::swap(m_struct, rhs.m_struct);
+		return *this;
+	}
+
+	{{ .VkName }} *c_ptr() noexcept { return &m_struct; }
+	const {{ .VkName }} *c_ptr() const noexcept { return &m_struct; }
+
+	operator const {{ .VkName }}&() const noexcept { return m_struct; }
+	operator const {{ .Wrapper }}&() const noexcept { return reinterpret_cast<const {{ .Wrapper }}&>(m_struct); }
+};
+static_assert(sizeof({{ .Name }}) == sizeof({{ .VkName }}), "safe struct and C struct layout mismatch");
+{{ line .Protect.End -}}
+
+{{ end }}
+
+{{ define "objecttypes" }}
+{{- "\n\n" -}}
+
+// ObjectTypeTraits<T>::value is the ObjectType of handle T,
+// HandleForObjectType<ObjectType::eX>::type is the handle class.
+template <typename T>
+struct ObjectTypeTraits;
+
+template <ObjectType T>
+struct HandleForObjectType;
+{{ range . }}{{ if .ObjectType }}
+{{ line .ObjectType.Protect.Begin -}}
+template <> struct ObjectTypeTraits<{{ .Name }}> { static constexpr ObjectType value = ObjectType::{{ .ObjectType.Name }}; };
+template <> struct HandleForObjectType<ObjectType::{{ .ObjectType.Name }}> { using type = {{ .Name }}; };
+{{ line .ObjectType.Protect.End -}}
+{{ end }}{{ end }}
+{{ end }}
+
+
+
+
+
+
+
+
+
+
+
+
+{{ define "fwdheader" -}}
+{{ comment "//" .Banner -}}
+// Declarations of the types of the C++ header, include it to define them.
+{{ .GuardBegin }}
+
+#include <cstdint>
+
+namespace {{ .Namespace }} {
+{{- with .VersionNamespace }}
+inline namespace {{ . }} {
+{{- end }}
+
+template <typename EnumType, typename T>
+class Flags;
+{{ range .Enums }}
+enum class {{ .Name }};
+{{- end }}
+{{ range .BitMasks }}
+{{- if eq .Enum.BitWidth 64 }}
+enum class {{ .Enum.Name }} : uint64_t;
+using {{ .Name }} = Flags<{{ .Enum.Name }}, uint64_t>;
+{{- else }}
+enum class {{ .Enum.Name }};
+using {{ .Name }} = Flags<{{ .Enum.Name }}, uint32_t>;
+{{- end }}
+{{- end }}
+{{ range .Handles }}
+class {{ .Name }};
+{{- end }}
+{{ range .Structs }}
+{{ if .Aggregate }}struct{{ else }}class{{ end }} {{ .Name }};
+{{- end }}
+{{- if .TypeAliases }}
+{{ range .TypeAliases }}
+using {{ .Name }} = {{ .Target }};
+{{- end }}
+{{- end }}
+
+{{ with .VersionNamespace }}} // inline namespace {{ . }}
+{{ end }}} // namespace {{ .Namespace }}
+{{- .GuardEnd }}
+{{ end }}
+
+
+
+
+
+{{ define "reflectheader" -}}
+{{ comment "//" .Banner -}}
+// Runtime metadata of the Vulkan structs, enums and commands, for code which
+// handles them generically. Offsets and sizes are those of the C structs,
+// which the C++ ones share.
+{{ .GuardBegin }}
+{{- if .Defines }}
+{{ range .Defines }}
+#ifndef {{ .Name }}
+#define {{ .Name }}{{ with .Value }} {{ . }}{{ end }}
+#endif
+{{- end }}
+{{- end }}
+
+#include <cstddef>
+#include <cstdint>
+#include <cstring>
+
+#include {{ .VulkanHeader }}
+
+namespace {{ .Namespace }} {
+namespace meta {
+
+template <typename T>
+struct Range {
+	const T *data;
+	size_t size;
+
+	const T *begin() const { return data; }
+	const T *end() const { return data + size; }
+	const T &operator[](size_t i) const { return data[i]; }
+};
+
+struct MemberInfo {
+	const char *name;
+	// C type, with the size of arrays: uint8_t[VK_UUID_SIZE]
+	const char *type;
+	size_t offset;
+	size_t size;
+	// member holding the number of elements of a pointer member, or nullptr
+	const char *len;
+	// width of bit-fields, which have no offset or size
+	unsigned bitWidth;
+};
+
+struct StructInfo {
+	const char *name;
+	const char *vkName;
+	size_t size;
+	size_t alignment;
+	bool isUnion;
+	bool hasSType;
+	int32_t sType;
+	Range<MemberInfo> members;
+};
+
+struct EnumValueInfo {
+	const char *name;
+	const char *vkName;
+	int64_t value;
+};
+
+struct EnumInfo {
+	const char *name;
+	const char *vkName;
+	bool isFlagBits;
+	Range<EnumValueInfo> values;
+};
+
+struct ParameterInfo {
+	const char *name;
+	const char *type;
+};
+
+struct CommandInfo {
+	const char *name;
+	const char *vkName;
+	const char *returnType;
+	Range<ParameterInfo> parameters;
+};
+
+// the tables end with an empty entry, which isn't counted: entries of
+// platforms not enabled leave the tables empty otherwise
+
+inline Range<StructInfo> structs() {
+{{- range $i, $s := .Structs }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+	static const MemberInfo members{{ $i }}[] = {
+{{- range .Members }}
+		{"{{ .Name }}", "{{ .Type }}", {{ if .BitWidth }}0, 0{{ else }}offsetof({{ $s.VkName }}, {{ .Name }}), sizeof({{ $s.VkName }}::{{ .Name }}){{ end }}, {{ with .Len }}"{{ . }}"{{ else }}nullptr{{ end }}, {{ .BitWidth }}},
+{{- end }}
+	};
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+	static const StructInfo table[] = {
+{{- range $i, $s := .Structs }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+		{"{{ .Name }}", "{{ .VkName }}", sizeof({{ .VkName }}), alignof({{ .VkName }}), {{ .Union }}, {{ .HasSType }}, {{ .SType }}, {members{{ $i }}, {{ len .Members }}}},
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+		{},
+	};
+	return {table, sizeof(table) / sizeof(table[0]) - 1};
+}
+
+inline Range<EnumInfo> enums() {
+{{- range $i, $e := .Enums }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+	static const EnumValueInfo values{{ $i }}[] = {
+{{- range .Values }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+		{"{{ .Name }}", "{{ .VkName }}", {{ .Number }}},
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+		{},
+	};
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+	static const EnumInfo table[] = {
+{{- range $i, $e := .Enums }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+		{"{{ .Name }}", "{{ .VkName }}", {{ .Bits }}, {values{{ $i }}, sizeof(values{{ $i }}) / sizeof(values{{ $i }}[0]) - 1}},
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+		{},
+	};
+	return {table, sizeof(table) / sizeof(table[0]) - 1};
+}
+
+inline Range<CommandInfo> commands() {
+{{- range $i, $c := .Commands }}
+{{- if .Parameters }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+	static const ParameterInfo parameters{{ $i }}[] = {
+{{- range .Parameters }}
+		{"{{ .Name }}", "{{ .Type }}"},
+{{- end }}
+	};
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+{{- end }}
+	static const CommandInfo table[] = {
+{{- range $i, $c := .Commands }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+		{"{{ .Name }}", "{{ .VkName }}", "{{ .Return }}", {{ if .Parameters }}{parameters{{ $i }}, {{ len .Parameters }}}{{ else }}{nullptr, 0}{{ end }}},
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+		{},
+	};
+	return {table, sizeof(table) / sizeof(table[0]) - 1};
+}
+
+// findStruct, findEnum and findCommand look up an entry by its C or C++
+// name, they return nullptr if there is none.
+
+template <typename T>
+const T *findByName(Range<T> r, const char *name) {
+	for (const T &e : r) {
+		if (std::strcmp(e.name, name) == 0 || std::strcmp(e.vkName, name) == 0) {
+			return &e;
+		}
+	}
+	return nullptr;
+}
+
+inline const StructInfo *findStruct(const char *name) { return findByName(structs(), name); }
+inline const EnumInfo *findEnum(const char *name) { return findByName(enums(), name); }
+inline const CommandInfo *findCommand(const char *name) { return findByName(commands(), name); }
+
+// valueName returns the C name of the value of an enum, nullptr if it has
+// no such value.
+inline const char *valueName(const EnumInfo &e, int64_t value) {
+	for (const EnumValueInfo &v : e.values) {
+		if (v.value == value) {
+			return v.vkName;
+		}
+	}
+	return nullptr;
+}
+
+} // namespace meta
+} // namespace {{ .Namespace }}
+{{- .GuardEnd }}
+{{ end }}
+
+
+
+
+
+{{ define "cheader" -}}
+{{ comment "//" .Banner -}}
+/* C part of the generated Vulkan wrapper, the C++ header builds on it */
+{{ .GuardBegin }}
+{{- if .Defines }}
+{{ range .Defines }}
+#ifndef {{ .Name }}
+#define {{ .Name }}{{ with .Value }} {{ . }}{{ end }}
+#endif
+{{- end }}
+{{- end }}
+
+#include {{ .VulkanHeader }}
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+{{- if .Constants }}
+
+/* API constants, in case vulkan.h is older than the registry */
+{{- range .Constants }}
+#ifndef {{ .Name }}
+#define {{ .Name }} {{ .Value }}
+#endif
+{{- end }}
+{{- end }}
+
+{{ template "cdispatch" .Commands }}
+
+#ifdef __cplusplus
+}
+#endif
+{{- .GuardEnd }}
+{{ end }}
+
+{{ define "cdispatch" -}}
+typedef struct VkgenDispatchTable {
+{{- range . }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+	PFN_{{ .VkName }} {{ .VkName }};
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+} VkgenDispatchTable;
+
+/* Loads every command of the table, instance may be NULL for global
+   commands. Commands not exposed by the implementation are left NULL. */
+static inline void vkgenLoadDispatchTable(VkgenDispatchTable *table, VkInstance instance, PFN_vkGetInstanceProcAddr getInstanceProcAddr)
+{
+{{- range . }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+	table->{{ .VkName }} = (PFN_{{ .VkName }})getInstanceProcAddr(instance, "{{ .VkName }}");
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+}
+{{- end }}
+
+{{ define "cwrapper" -}}
+{{ comment "//" .Banner -}}
+/* C helpers for the Vulkan API: enum names, struct initializers and a loader
+   table */
+{{ .GuardBegin }}
+{{- if .Defines }}
+{{ range .Defines }}
+#ifndef {{ .Name }}
+#define {{ .Name }}{{ with .Value }} {{ . }}{{ end }}
+#endif
+{{- end }}
+{{- end }}
+
+#include <string.h>
+#include {{ .VulkanHeader }}
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+{{- if .Constants }}
+
+/* API constants, in case vulkan.h is older than the registry */
+{{- range .Constants }}
+#ifndef {{ .Name }}
+#define {{ .Name }} {{ .Value }}
+#endif
+{{- end }}
+{{- end }}
+{{ range .Enums }}
+{{ line .Protect.Begin -}}
+/* Returns the name of the value, NULL if it isn't one of {{ .VkName }}. */
+static inline const char *vkString_{{ .Name }}({{ .VkName }} value)
+{
+	switch (value) {
+{{- range .Values }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+	case {{ .VkName }}: return "{{ .VkName }}";
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+	default: return NULL;
+	}
+}
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{ end }}
+{{- range .Structs }}
+{{ line .Protect.Begin -}}
+/* Returns a {{ .VkName }} with sType set and everything else zero, to
+   assign the members to. */
+static inline {{ .VkName }} vkInit_{{ .Name }}(void)
+{
+	{{ .VkName }} s;
+	memset(&s, 0, sizeof(s));
+	s.sType = {{ .SType }};
+	return s;
+}
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{ end }}
+{{ template "cdispatch" .Commands }}
+
+#ifdef __cplusplus
+}
+#endif
+{{- .GuardEnd }}
+{{ end }}
+
+{{ define "rust" -}}
+{{ comment "//" .Banner -}}
+//! Vulkan API bindings: #[repr(C)] types, function pointer types and extern
+//! declarations of the commands, and a table loading them.
+{{- with .Missing }}
+//!
+//! These C types aren't generated, pointers to them are *c_void and what
+//! uses them by value is left out:
+//! {{ range $i, $t := . }}{{ if $i }}, {{ end }}{{ $t }}{{ end }}
+{{- end }}
+#![allow(non_camel_case_types, non_snake_case, non_upper_case_globals, dead_code, unused_imports)]
+
+pub use core::ffi::{c_char, c_int, c_void};
+
+pub type Bool32 = u32;
+pub type Flags = u32;
+pub type Flags64 = u64;
+pub type DeviceSize = u64;
+pub type DeviceAddress = u64;
+pub type SampleMask = u32;
+
+/// Any function pointer, cast to the right type before calling it.
+pub type PFN_vkVoidFunction = Option<unsafe extern "system" fn()>;
+{{ range .Constants }}
+pub const {{ .Name }}: {{ .Type }} = {{ .Value }};
+{{- end }}
+{{ range .Handles }}
+#[repr(transparent)]
+#[derive(Copy, Clone, PartialEq, Eq, Hash, Debug)]
+{{- if .Dispatchable }}
+pub struct {{ .Name }}(pub *mut c_void);
+impl {{ .Name }} {
+	pub const NULL: Self = Self(core::ptr::null_mut());
+}
+{{- else }}
+pub struct {{ .Name }}(pub u64);
+impl {{ .Name }} {
+	pub const NULL: Self = Self(0);
+}
+{{- end }}
+{{ end }}
+{{- range .Enums }}
+#[repr(transparent)]
+#[derive(Copy, Clone, PartialEq, Eq, Hash, Default, Debug)]
+pub struct {{ .Name }}(pub {{ .Repr }});
+impl {{ .Name }} {
+{{- range .Values }}{{ if .Name }}
+	pub const {{ .Name }}: Self = Self({{ .Value }});
+{{- end }}{{ end }}
+}
+{{ end }}
+macro_rules! vk_bitflags {
+	($name:ident) => {
+		impl $name {
+			pub const fn empty() -> Self { Self(0) }
+			pub const fn is_empty(self) -> bool { self.0 == 0 }
+			pub const fn contains(self, other: Self) -> bool { self.0 & other.0 == other.0 }
+			pub const fn intersects(self, other: Self) -> bool { self.0 & other.0 != 0 }
+		}
+		impl core::ops::BitOr for $name {
+			type Output = Self;
+			fn bitor(self, rhs: Self) -> Self { Self(self.0 | rhs.0) }
+		}
+		impl core::ops::BitOrAssign for $name {
+			fn bitor_assign(&mut self, rhs: Self) { self.0 |= rhs.0 }
+		}
+		impl core::ops::BitAnd for $name {
+			type Output = Self;
+			fn bitand(self, rhs: Self) -> Self { Self(self.0 & rhs.0) }
+		}
+		impl core::ops::BitAndAssign for $name {
+			fn bitand_assign(&mut self, rhs: Self) { self.0 &= rhs.0 }
+		}
+		impl core::ops::Not for $name {
+			type Output = Self;
+			fn not(self) -> Self { Self(!self.0) }
+		}
+	};
+}
+{{ range $bm := .BitMasks }}
+#[repr(transparent)]
+#[derive(Copy, Clone, PartialEq, Eq, Hash, Default, Debug)]
+pub struct {{ .Name }}(pub {{ .Repr }});
+vk_bitflags!({{ .Name }});
+{{- if .Values }}
+impl {{ .Name }} {
+{{- range .Values }}{{ if .Name }}
+	pub const {{ .Name }}: Self = Self({{ .Value }});
+{{- end }}{{ end }}
+}
+{{- end }}
+{{- with .FlagBits }}
+pub type {{ . }} = {{ $bm.Name }};
+{{- end }}
+{{ end }}
+{{- range .Structs }}
+#[repr(C)]
+#[derive(Copy, Clone)]
+pub {{ if .Union }}union{{ else }}struct{{ end }} {{ .Name }} {
+{{- range .Fields }}
+	pub {{ .Name }}: {{ .Type }},
+{{- end }}
+}
+impl Default for {{ .Name }} {
+	/// All zero{{ with .SType }} but s_type{{ end }}.
+	fn default() -> Self {
+{{- if .SType }}
+		let mut s: Self = unsafe { core::mem::zeroed() };
+		s.s_type = StructureType::{{ .SType }};
+		s
+{{- else }}
+		unsafe { core::mem::zeroed() }
+{{- end }}
+	}
+}
+{{ end }}
+{{- range .Aliases }}
+pub type {{ .Name }} = {{ .Target }};
+{{- end }}
+{{ range .Commands }}
+pub type PFN_{{ .VkName }} = unsafe extern "system" fn(
+{{- range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ $p.Name }}: {{ $p.Type }}{{ end -}}
+){{ with .Ret }} -> {{ . }}{{ end }};
+{{- end }}
+
+extern "system" {
+{{- range .Commands }}
+	pub fn {{ .VkName }}(
+{{- range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ $p.Name }}: {{ $p.Type }}{{ end -}}
+){{ with .Ret }} -> {{ . }}{{ end }};
+{{- end }}
+}
+
+/// The commands loaded through vkGetInstanceProcAddr, None where the
+/// implementation doesn't expose them.
+#[derive(Copy, Clone, Default)]
+pub struct DispatchTable {
+{{- range .Commands }}
+	pub {{ .Name }}: Option<PFN_{{ .VkName }}>,
+{{- end }}
+}
+
+impl DispatchTable {
+	/// Loads every command of the table, instance may be NULL for global
+	/// commands.
+	pub unsafe fn load(instance: Instance, get_instance_proc_addr: unsafe extern "system" fn(Instance, *const c_char) -> PFN_vkVoidFunction) -> Self {
+		Self {
+{{- range .Commands }}
+			{{ .Name }}: core::mem::transmute(get_instance_proc_addr(instance, b"{{ .VkName }}\0".as_ptr() as *const c_char)),
+{{- end }}
+		}
+	}
+}
+{{ end }}
+
+{{ define "zig" -}}
+{{ comment "//" .Banner -}}
+//! Vulkan API bindings: extern types, function pointer types and extern
+//! declarations of the commands, and a table loading them.
+{{- with .Missing }}
+//!
+//! These C types aren't generated, pointers to them are *anyopaque and what
+//! uses them by value is left out:
+//! {{ range $i, $t := . }}{{ if $i }}, {{ end }}{{ $t }}{{ end }}
+{{- end }}
+
+const std = @import("std");
+const builtin = @import("builtin");
+
+/// The calling convention of the commands, stdcall on 32-bit Windows.
+pub const call_conv: std.builtin.CallingConvention = if (builtin.os.tag == .windows and builtin.cpu.arch == .x86)
+    .{ .x86_stdcall = .{} }
+else
+    .c;
+
+pub const Bool32 = u32;
+pub const Flags = u32;
+pub const Flags64 = u64;
+pub const DeviceSize = u64;
+pub const DeviceAddress = u64;
+pub const SampleMask = u32;
+
+/// Any function pointer, cast to the right type before calling it.
+pub const PfnVoidFunction = ?*const fn () callconv(call_conv) void;
+{{ range .Constants }}
+pub const {{ .Name }}: {{ .Type }} = {{ .Value }};
+{{- end }}
+{{ range .Handles }}
+pub const {{ .Name }} = enum({{ if .Dispatchable }}usize{{ else }}u64{{ end }}) { null_handle = 0, _ };
+{{- end }}
+{{ range .Enums }}
+pub const {{ .Name }} = enum(i32) {
+{{- range .Values }}
+    {{ .Name }} = {{ .Value }},
+{{- end }}
+    _,
+{{- range .Aliases }}
+
+    pub const {{ .Name }}: @This() = .{{ .Value }};
+{{- end }}
+};
+{{ end }}
+{{- range $bm := .BitMasks }}
+pub const {{ .Name }} = packed struct({{ .Repr }}) {
+{{- range .Bits }}
+    {{ . }}: bool = false,
+{{- end }}
+{{ range .Values }}
+    pub const {{ .Name }}: {{ $bm.Name }} = @bitCast(@as({{ $bm.Repr }}, {{ .Value }}));
+{{- end }}
+    pub const empty: {{ .Name }} = .{};
+
+    pub fn toInt(self: {{ .Name }}) {{ .Repr }} {
+        return @bitCast(self);
+    }
+    pub fn fromInt(flags: {{ .Repr }}) {{ .Name }} {
+        return @bitCast(flags);
+    }
+    pub fn merge(a: {{ .Name }}, b: {{ .Name }}) {{ .Name }} {
+        return fromInt(a.toInt() | b.toInt());
+    }
+    pub fn intersect(a: {{ .Name }}, b: {{ .Name }}) {{ .Name }} {
+        return fromInt(a.toInt() & b.toInt());
+    }
+    pub fn contains(a: {{ .Name }}, b: {{ .Name }}) bool {
+        return a.toInt() & b.toInt() == b.toInt();
+    }
+};
+{{- with .FlagBits }}
+pub const {{ . }} = {{ $bm.Name }};
+{{- end }}
+{{ end }}
+{{- range .Structs }}
+pub const {{ .Name }} = extern {{ if .Union }}union{{ else }}struct{{ end }} {
+{{- range .Fields }}
+    {{ .Name }}: {{ .Type }}{{ with .Default }} = {{ . }}{{ end }},
+{{- end }}
+};
+{{ end }}
+{{- range .Aliases }}
+pub const {{ .Name }} = {{ .Target }};
+{{- end }}
+{{ range .Commands }}
+pub const {{ .Type }} = *const fn (
+{{- range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ $p.Name }}: {{ $p.Type }}{{ end -}}
+) callconv(call_conv) {{ .Ret }};
+{{- end }}
+{{ range .Commands }}
+pub extern fn {{ .VkName }}(
+{{- range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ $p.Name }}: {{ $p.Type }}{{ end -}}
+) callconv(call_conv) {{ .Ret }};
+{{- end }}
+
+/// The commands loaded through vkGetInstanceProcAddr, null where the
+/// implementation doesn't expose them.
+pub const DispatchTable = struct {
+{{- range .Commands }}
+    {{ .Name }}: ?{{ .Type }} = null,
+{{- end }}
+
+    /// Loads every command of the table, instance may be .null_handle for
+    /// global commands.
+    pub fn load(instance: Instance, get_instance_proc_addr: *const fn (Instance, [*:0]const u8) callconv(call_conv) PfnVoidFunction) DispatchTable {
+        return .{
+{{- range .Commands }}
+            .{{ .Name }} = @ptrCast(get_instance_proc_addr(instance, "{{ .VkName }}")),
+{{- end }}
+        };
+    }
+};
+{{ end }}
+
+{{ define "csharp" -}}
+{{ comment "//" .Banner -}}
+// Vulkan API bindings: blittable types, the commands as [DllImport] methods
+// of Vk and a table of function pointers loading them. Needs C# 9 and
+// unsafe code.
+{{- with .Missing }}
+//
+// These C types aren't generated, pointers to them are void* and what uses
+// them by value is left out:
+// {{ range $i, $t := . }}{{ if $i }}, {{ end }}{{ $t }}{{ end }}
+{{- end }}
+
+using System;
+using System.Runtime.InteropServices;
+
+namespace {{ .Namespace }}
+{
+{{- range .Handles }}
+    [StructLayout(LayoutKind.Sequential)]
+    public readonly struct {{ .Name }} : IEquatable<{{ .Name }}>
+    {
+        public readonly {{ .Type }} Handle;
+
+        public {{ .Name }}({{ .Type }} handle) { Handle = handle; }
+
+        public static readonly {{ .Name }} Null = default;
+        public bool IsNull => Handle == default;
+
+        public bool Equals({{ .Name }} other) => Handle == other.Handle;
+        public override bool Equals(object obj) => obj is {{ .Name }} other && Equals(other);
+        public override int GetHashCode() => Handle.GetHashCode();
+        public static bool operator ==({{ .Name }} a, {{ .Name }} b) => a.Handle == b.Handle;
+        public static bool operator !=({{ .Name }} a, {{ .Name }} b) => a.Handle != b.Handle;
+    }
+{{ end }}
+{{- range .Enums }}
+{{- if .Flags }}
+    [Flags]
+{{- end }}
+    public enum {{ .Name }} : {{ .Type }}
+    {
+{{- range .Values }}
+        {{ .Name }} = {{ .Value }},
+{{- end }}
+    }
+{{ end }}
+{{- range $s := .Structs }}
+{{- if .Union }}
+    [StructLayout(LayoutKind.Explicit)]
+{{- else }}
+    [StructLayout(LayoutKind.Sequential)]
+{{- end }}
+    public unsafe struct {{ .Name }}
+    {
+{{- range .Fields }}
+        {{ if $s.Union }}[FieldOffset(0)] {{ end }}{{ if .Fixed }}public fixed {{ .Type }} {{ .Name }}[{{ .Fixed }}];{{ else }}public {{ .Type }} {{ .Name }};{{ end }}
+{{- end }}
+{{- with .SType }}
+
+        /// <summary>A {{ $s.Name }} with SType set and the rest zeroed.</summary>
+        public static {{ $s.Name }} New() => new {{ $s.Name }} { SType = StructureType.{{ . }} };
+{{- end }}
+    }
+{{ end }}
+    public static unsafe class Vk
+    {
+        /// <summary>
+        /// The Vulkan loader, vulkan-1.dll on Windows. Elsewhere map it to
+        /// libvulkan.so.1 or libvulkan.1.dylib with
+        /// NativeLibrary.SetDllImportResolver.
+        /// </summary>
+        public const string LibraryName = "vulkan-1";
+{{ range .Constants }}
+        public const {{ .Type }} {{ .Name }} = {{ .Value }};
+{{- end }}
+{{ range .Commands }}
+        [DllImport(LibraryName, EntryPoint = "{{ .VkName }}", ExactSpelling = true)]
+        public static extern {{ .Ret }} {{ .Name }}(
+{{- range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ $p.Type }} {{ $p.Name }}{{ end -}}
+);
+{{- end }}
+    }
+
+    /// <summary>
+    /// The commands loaded through vkGetInstanceProcAddr, null where the
+    /// implementation doesn't expose them.
+    /// </summary>
+    public unsafe struct DispatchTable
+    {
+{{- range .Commands }}
+        public {{ .FunctionPointer }} {{ .Name }};
+{{- end }}
+
+        /// <summary>
+        /// Loads every command of the table, instance may be Instance.Null
+        /// for global commands.
+        /// </summary>
+        public static DispatchTable Load(Instance instance, delegate* unmanaged<Instance, byte*, IntPtr> getInstanceProcAddr)
+        {
+            DispatchTable t = default;
+{{- range .Commands }}
+            t.{{ .Name }} = ({{ .FunctionPointer }})Load(instance, getInstanceProcAddr, "{{ .VkName }}");
+{{- end }}
+            return t;
+        }
+
+        static IntPtr Load(Instance instance, delegate* unmanaged<Instance, byte*, IntPtr> getInstanceProcAddr, string name)
+        {
+            IntPtr s = Marshal.StringToHGlobalAnsi(name);
+            try
+            {
+                return getInstanceProcAddr(instance, (byte*)s);
+            }
+            finally
+            {
+                Marshal.FreeHGlobal(s);
+            }
+        }
+    }
+}
+{{ end }}
+
+{{ define "python" -}}
+{{ comment "#" .Banner -}}
+"""Vulkan API bindings: ctypes structures and unions, IntEnums and IntFlags,
+the function prototypes of the commands and a table loading them.
+
+Fields of enum and bitmask types are plain integers, build and read them
+with the enums. load_library() opens the Vulkan loader and
+DispatchTable.load() resolves the commands through it.
+{{- with .Missing }}
+
+These C types aren't generated, pointers to them are void pointers and what
+uses them by value is left out:
+{{ range $i, $t := . }}{{ if $i }}, {{ end }}{{ $t }}{{ end }}
+{{- end }}
+"""
+
+import ctypes
+import enum
+import sys
+
+if sys.platform == "win32":
+    FUNCTYPE = ctypes.WINFUNCTYPE
+else:
+    FUNCTYPE = ctypes.CFUNCTYPE
+
+Bool32 = ctypes.c_uint32
+Flags = ctypes.c_uint32
+Flags64 = ctypes.c_uint64
+DeviceSize = ctypes.c_uint64
+DeviceAddress = ctypes.c_uint64
+SampleMask = ctypes.c_uint32
+{{ range .Constants }}
+{{ .Name }} = {{ .Value }}
+{{- end }}
+{{ range .Handles }}
+{{ .Name }} = {{ .Type }}
+{{- end }}
+{{- range $e := .Enums }}
+
+
+class {{ .Name }}({{ if .Flags }}enum.IntFlag{{ else }}enum.IntEnum{{ end }}):
+{{- range .Values }}
+    {{ .Name }} = {{ .Value }}
+{{- else }}
+    pass
+{{- end }}
+{{- with .FlagBits }}
+
+
+{{ . }} = {{ $e.Name }}
+{{- end }}
+{{- end }}
+{{- range .Structs }}
+
+
+class {{ .Name }}({{ if .Union }}ctypes.Union{{ else }}ctypes.Structure{{ end }}):
+{{- with .SType }}
+    def __init__(self, *args, **kwargs):
+        if not args:
+            kwargs.setdefault("sType", StructureType.{{ . }})
+        super().__init__(*args, **kwargs)
+{{- else }}
+    pass
+{{- end }}
+{{- end }}
+
+{{ range .Structs }}
+{{ .Name }}._fields_ = [
+{{- range .Fields }}
+    ("{{ .Name }}", {{ .Type }}{{ with .Bits }}, {{ . }}{{ end }}),
+{{- end }}
+]
+{{- end }}
+{{- if .Aliases }}
+{{ range .Aliases }}
+{{ .Name }} = {{ .Target }}
+{{- end }}
+{{- end }}
+{{ range .Commands }}
+PFN_{{ .VkName }} = FUNCTYPE({{ .Ret }}{{ range .Params }}, {{ .Type }}{{ end }})
+{{- end }}
+
+# the commands of DispatchTable: attribute, C name and prototype
+COMMANDS = [
+{{- range .Commands }}
+    ("{{ .Name }}", "{{ .VkName }}", PFN_{{ .VkName }}),
+{{- end }}
+]
+
+
+def load_library(name=None):
+    """Opens the Vulkan loader, or the library name."""
+    if name is None:
+        if sys.platform == "win32":
+            name = "vulkan-1.dll"
+        elif sys.platform == "darwin":
+            name = "libvulkan.1.dylib"
+        else:
+            name = "libvulkan.so.1"
+    if sys.platform == "win32":
+        return ctypes.WinDLL(name)
+    return ctypes.CDLL(name)
+
+
+class DispatchTable:
+    """The commands loaded through vkGetInstanceProcAddr, None where the
+    implementation doesn't expose them."""
+
+    def __init__(self, get_instance_proc_addr, instance=None):
+        for attr, name, proto in COMMANDS:
+            address = get_instance_proc_addr(instance, name.encode())
+            setattr(self, attr, proto(address) if address else None)
+
+    @classmethod
+    def load(cls, library=None, instance=None):
+        """Loads every command of the table from library, load_library() by
+        default. instance is None for global commands."""
+        if library is None:
+            library = load_library()
+        get_instance_proc_addr = library.vkGetInstanceProcAddr
+        get_instance_proc_addr.restype = ctypes.c_void_p
+        get_instance_proc_addr.argtypes = [ctypes.c_void_p, ctypes.c_char_p]
+        return cls(get_instance_proc_addr, instance)
+{{ end }}
+
+{{ define "mock" -}}
+{{ comment "//" .Banner -}}
+// Null Vulkan driver for tests: every command records its call, makes up
+// the handles it returns and returns VK_SUCCESS unless told otherwise. Link
+// it instead of the Vulkan loader. For the vkmock API define VKMOCK_API_ONLY
+// and include this file.
+{{- if .Defines }}
+{{ range .Defines }}
+#ifndef {{ .Name }}
+#define {{ .Name }}{{ with .Value }} {{ . }}{{ end }}
+#endif
+{{- end }}
+{{- end }}
+
+#include {{ .VulkanHeader }}
+
+#include <cstddef>
+#include <string>
+#include <vector>
+
+namespace vkmock {
+
+// the names of the commands called since the last reset, in order
+std::vector<std::string> calls();
+
+// the number of calls of command since the last reset
+size_t count(const char *command);
+
+// makes command return result until the next reset
+void setResult(const char *command, VkResult result);
+
+// forgets the calls and results
+void reset();
+
+} // namespace vkmock
+
+#ifndef VKMOCK_API_ONLY
+
+#include <cstdint>
+#include <cstring>
+#include <map>
+#include <mutex>
+
+namespace vkmock {
+namespace detail {
+
+struct State {
+	std::mutex mutex;
+	std::vector<std::string> calls;
+	std::map<std::string, VkResult> results;
+	uint64_t handles = 0;
+};
+
+inline State &state() {
+	static State s;
+	return s;
+}
+
+// records a call of command, returns what it's to return
+inline VkResult call(const char *command) {
+	State &s = state();
+	std::lock_guard<std::mutex> lock(s.mutex);
+	s.calls.push_back(command);
+	auto it = s.results.find(command);
+	return it == s.results.end() ? VK_SUCCESS : it->second;
+}
+
+// makes up a handle, none is ever VK_NULL_HANDLE
+template <typename T>
+T make() {
+	State &s = state();
+	std::lock_guard<std::mutex> lock(s.mutex);
+	return (T)(uintptr_t)++s.handles;
+}
+
+PFN_vkVoidFunction lookup(const char *name);
+
+} // namespace detail
+
+std::vector<std::string> calls() {
+	detail::State &s = detail::state();
+	std::lock_guard<std::mutex> lock(s.mutex);
+	return s.calls;
+}
+
+size_t count(const char *command) {
+	detail::State &s = detail::state();
+	std::lock_guard<std::mutex> lock(s.mutex);
+	size_t n = 0;
+	for (const std::string &c : s.calls) {
+		if (c == command) {
+			n++;
+		}
+	}
+	return n;
+}
+
+void setResult(const char *command, VkResult result) {
+	detail::State &s = detail::state();
+	std::lock_guard<std::mutex> lock(s.mutex);
+	s.results[command] = result;
+}
+
+void reset() {
+	detail::State &s = detail::state();
+	std::lock_guard<std::mutex> lock(s.mutex);
+	s.calls.clear();
+	s.results.clear();
+}
+
+} // namespace vkmock
+
+extern "C" {
+{{ range .Commands }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+VKAPI_ATTR {{ .RetVkType }} VKAPI_CALL {{ .VkName }}(
+{{- range $i, $p := .Parameters }}{{ if $i }}, {{ end }}{{ .VkType }} {{ .Name }}{{ end -}}
+) {
+	{{ if eq .RetVkType "VkResult" }}VkResult result = {{ end }}vkmock::detail::call("{{ .VkName }}");
+{{- range .Parameters }}
+	(void){{ .Name }};
+{{- end }}
+{{- if eq .RetVkType "VkResult" }}
+	if (result < 0) {
+		return result;
+	}
+{{- end }}
+{{- range .Outputs }}
+	{{ . }}
+{{- end }}
+{{- with .Returns }}
+	return {{ . }};
+{{- end }}
+}
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{ end }}
+} // extern "C"
+
+PFN_vkVoidFunction vkmock::detail::lookup(const char *name) {
+	static const struct {
+		const char *name;
+		PFN_vkVoidFunction function;
+	} commands[] = {
+{{- range .Commands }}
+{{- with .Protect.Begin }}
+{{ . }}{{ end }}
+		{"{{ .VkName }}", (PFN_vkVoidFunction){{ .VkName }}},
+{{- with .Protect.End }}
+{{ . }}{{ end }}
+{{- end }}
+	};
+	for (const auto &c : commands) {
+		if (std::strcmp(c.name, name) == 0) {
+			return c.function;
+		}
+	}
+	return nullptr;
+}
+
+#endif // VKMOCK_API_ONLY
+{{ end }}
+
+{{ define "markdown" -}}
+{{ with .Banner }}<!--
+{{ range . }}{{ . }}
+{{ end }}-->
+
+{{ end -}}
+# Vulkan C++ API reference
+
+The types and functions of the {{ .Namespace }} namespace, each with the C
+entity it wraps and a link to its man page.
+{{ range .Sections }}
+- [{{ .Title }}](#{{ lower .Title }})
+{{- end }}
+{{ range .Sections }}
+## {{ .Title }}
+{{ range .Entities }}
+### {{ code .Name }}
+
+Wraps [{{ .VkName }}]({{ .Link }}).
+{{- with .Guard }} Only available if {{ code . }} is defined.{{ end }}
+{{- with .Signature }}
+
+    {{ . }}
+{{- end }}
+{{- range .Notes }}
+
+{{ . }}
+{{- end }}
+{{- if .Rows }}
+
+|{{ range .Columns }} {{ . }} |{{ end }}
+|{{ range .Columns }} --- |{{ end }}
+{{- range .Rows }}
+|{{ range . }} {{ . }} |{{ end }}
+{{- end }}
+{{- end }}
+{{ else }}
+None.
+{{ end }}
+{{- end }}
+{{- end }}
+
+{{ define "examplecmake" -}}
+{{ comment "#" .Banner -}}
+# Example project using the generated header, see main.cpp
+cmake_minimum_required(VERSION 3.7)
+project(vulkangen_example CXX)
+
+find_package(Vulkan REQUIRED)
+
+add_executable(example main.cpp)
+set_target_properties(example PROPERTIES CXX_STANDARD {{ .CppStd }} CXX_STANDARD_REQUIRED ON)
+target_include_directories(example PRIVATE ${CMAKE_CURRENT_SOURCE_DIR})
+target_link_libraries(example Vulkan::Vulkan)
+{{ end }}
+
+{{ define "examplemain" -}}
+{{ comment "//" .Banner -}}
+// Creates an instance, lists the physical devices and prints the properties
+// of the first one, using the wrappers of {{ .Header }}.
+#include <cstdio>
+#include <vector>
+#include "{{ .Header }}"
+
+static void printVersion(const char *name, uint32_t packed)
+{
+	vk::Version v(packed);
+	std::printf("  %-16s %u.%u.%u\n", name, v.major, v.minor, v.patch);
+}
+
+int main()
+{
+{{- if .Aggregates }}
+	vk::ApplicationInfo appInfo{
+		.pApplicationName = "vulkangen example",
+		.applicationVersion = vk::Version(1, 0, 0).packed(),
+		.apiVersion = vk::Version(1, 0, 0).packed(),
+	};
+
+	vk::InstanceCreateInfo instanceInfo{
+		.pApplicationInfo = &appInfo,
+	};
+{{- else }}
+	vk::ApplicationInfo appInfo;
+	appInfo.pApplicationName("vulkangen example")
+		.applicationVersion(vk::Version(1, 0, 0).packed())
+		.apiVersion(vk::Version(1, 0, 0).packed());
+
+	vk::InstanceCreateInfo instanceInfo;
+	instanceInfo.pApplicationInfo(&appInfo);
+{{- end }}
+
+	vk::Instance instance;
+	vk::Result result = vk::createInstance(&instanceInfo, nullptr, &instance);
+	if (result != vk::Result::eSuccess) {
+		std::fprintf(stderr, "createInstance: %s\n", vk::getEnumString(result));
+		return 1;
+	}
+{{- if .UniqueHandles }}
+	// destroys the instance when main returns
+	vk::UniqueInstance uniqueInstance(instance);
+{{- end }}
+
+	uint32_t count = 0;
+	result = vk::enumeratePhysicalDevices(instance, &count, nullptr);
+	std::vector<vk::PhysicalDevice> devices(count);
+	if (result == vk::Result::eSuccess && count > 0)
+		result = vk::enumeratePhysicalDevices(instance, &count, devices.data());
+	if (result != vk::Result::eSuccess && result != vk::Result::eIncomplete) {
+		std::fprintf(stderr, "enumeratePhysicalDevices: %s\n", vk::getEnumString(result));
+{{- if not .UniqueHandles }}
+		vk::destroyInstance(instance, nullptr);
+{{- end }}
+		return 1;
+	}
+	devices.resize(count);
+	std::printf("%u physical device(s)\n", count);
+	if (devices.empty()) {
+{{- if not .UniqueHandles }}
+		vk::destroyInstance(instance, nullptr);
+{{- end }}
+		return 1;
+	}
+
+	vk::PhysicalDeviceProperties props;
+	vk::getPhysicalDeviceProperties(devices[0], &props);
+{{- /* members are read directly from aggregates, through getters otherwise */}}
+{{- $get := "()" }}{{ if .Aggregates }}{{ $get = "" }}{{ end }}
+	std::printf("%s\n", props.deviceName{{ $get }});
+	std::printf("  %-16s %s\n", "type", vk::getEnumString(props.deviceType{{ $get }}));
+	printVersion("api version", props.apiVersion{{ $get }});
+	std::printf("  %-16s 0x%08x\n", "driver version", props.driverVersion{{ $get }});
+	std::printf("  %-16s 0x%04x\n", "vendor id", props.vendorID{{ $get }});
+	std::printf("  %-16s 0x%04x\n", "device id", props.deviceID{{ $get }});
+{{- if not .UniqueHandles }}
+
+	vk::destroyInstance(instance, nullptr);
+{{- end }}
+	return 0;
+}
+{{ end }}
+
+
+
+
+
+{{ define "body" }}
+{{- if .Sections.Enums }}{{ template "enumspart" . }}{{ end }}
+{{- if .Sections.Handles }}{{ template "handlespart" . }}{{ end }}
+{{- if .Sections.Structs }}{{ template "structspart" . }}{{ end }}
+{{- if .Sections.Commands }}{{ template "funcspart" . }}{{ end }}
+{{- end }}
+
+
+
+
+
+{{ define "partheader" }}
+{{- comment "//" .Banner -}}
+{{- .GuardBegin }}
+
+#include "{{ .Include }}"
+
+namespace {{ .Namespace }} {
+{{- with .VersionNamespace }}
+inline namespace {{ . }} {
+{{- end }}
+
+{{ end }}
+
+
+
+
+
+{{ define "enumspart" }}
+
+{{ range .Enums -}}
+{{ render "enum" . }}
+{{- end }}
+
+{{ range .BitMasks -}}
+{{ render "bitmask" . }}
+{{- end }}
+{{- end }}
+
+
+
+
+
+{{ define "handlespart" }}
+{{- if .HasMethods }}{{ template "forward" . }}{{ end }}
+
+{{ range .Handles -}}
+{{ render "handle" . }}
+{{- end }}
+
+{{ template "handleparents" .Handles }}
+
+{{ if .HasObjectTypes -}}
+{{ template "objecttypes" .Handles }}
+{{- end }}
+{{- template "handletraits" . }}
+{{- end }}
+
+
+
+
+
+{{ define "structspart" }}
+
+{{ range .Structs -}}
+{{ if .Aggregate }}{{ render "aggregate" . }}{{ else }}{{ render "struct" . }}{{ end }}
+{{- end }}
+{{- if .StructExtensions }}{{ template "structchain" . }}{{ end }}
+{{- template "structtraits" . }}
+{{- if .SafeStructs }}{{ template "safestructs" . }}{{ end }}
+{{- end }}
+
+
+
+
+
+{{ define "funcspart" }}
+{{- if .Exceptions }}{{ template "exceptions" . }}{{ end }}
+{{- if .HasResultValues }}{{ template "resultvalue" }}{{ end }}
+{{- if .Dispatch }}
+#ifndef VK_NO_PROTOTYPES
+{{- end }}
+
+{{ range .Commands -}}
+{{ render "command" . }}
+{{- end }}
+{{- range .Handles }}{{ if .Methods }}{{ render "methods" . }}{{ end }}{{ end }}
+{{- range .UniqueHandles }}{{ render "unique" . }}{{ end }}
+{{- if .Dispatch }}
+#endif // VK_NO_PROTOTYPES
+{{ template "dispatch" . }}
+{{- end }}
+{{- with .DebugName }}{{ template "debugname" . }}{{ end }}
+
+{{ if or .TypeAliases .CommandAliases -}}
+{{ template "aliases" . }}
+{{- end }}
+
+{{ if .Extensions -}}
+{{ template "extensions" . }}
+{{- end }}
+
+{{ if or .SpirvExtensions .SpirvCapabilities -}}
+{{ template "spirv" . }}
+{{- end }}
+
+{{ if .Serializers -}}
+{{ template "serialize" . }}
+{{- end }}
+
+{{ if .JSONStructs -}}
+{{ template "json" . }}
+{{- end }}
+
+{{ if not .Sync.Empty -}}
+{{ template "sync" .Sync }}
+{{- end }}
+{{- if .AffixedTypes }}{{ template "affixedtypes" . }}{{ end }}
+
+{{ end }}
+`))
@@ -0,0 +1,11 @@
+// emulate a 32-bit target after the system headers are in
+#include <stdint.h>
+#include <stddef.h>
+#include <array>
+#include <cstring>
+#include <string>
+#include <vector>
+#include <memory>
+#include <utility>
+#undef __LP64__
+#undef __x86_64__
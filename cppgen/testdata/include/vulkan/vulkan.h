@@ -0,0 +1,352 @@
+#pragma once
+#include <stdint.h>
+#include <stddef.h>
+#define VKAPI_PTR
+#define VK_NULL_HANDLE 0
+#define VK_DEFINE_HANDLE(object) typedef struct object##_T* object;
+#ifndef VK_USE_64_BIT_PTR_DEFINES
+#if defined(__LP64__) || defined(_WIN64) || defined(__x86_64__)
+#define VK_USE_64_BIT_PTR_DEFINES 1
+#else
+#define VK_USE_64_BIT_PTR_DEFINES 0
+#endif
+#endif
+#if (VK_USE_64_BIT_PTR_DEFINES==1)
+#define VK_DEFINE_NON_DISPATCHABLE_HANDLE(object) typedef struct object##_T *object;
+#else
+#define VK_DEFINE_NON_DISPATCHABLE_HANDLE(object) typedef uint64_t object;
+#endif
+#define VK_MAKE_API_VERSION(variant, major, minor, patch) ((((uint32_t)(variant)) << 29U) | (((uint32_t)(major)) << 22U) | (((uint32_t)(minor)) << 12U) | ((uint32_t)(patch)))
+#define VK_API_VERSION_MAJOR(version) (((uint32_t)(version) >> 22U) & 0x7FU)
+#define VK_API_VERSION_MINOR(version) (((uint32_t)(version) >> 12U) & 0x3FFU)
+#define VK_API_VERSION_PATCH(version) ((uint32_t)(version) & 0xFFFU)
+#define VK_HEADER_VERSION 280
+typedef struct Display Display; typedef unsigned long Window; typedef int StdVideoH264ProfileIdc;
+#define VK_MAX_PHYSICAL_DEVICE_NAME_SIZE 256
+#define VK_UUID_SIZE 16
+#define VK_LUID_SIZE 8
+#define VK_MAX_EXTENSION_NAME_SIZE 256
+#define VK_REMAINING_MIP_LEVELS (~0U)
+#define VK_WHOLE_SIZE (~0ULL)
+#define VK_LOD_CLAMP_NONE 1000.0F
+#define VK_MAX_DRIVER_NAME_SIZE 256
+#define VK_MAX_DRIVER_INFO_SIZE 256
+#define VK_TRUE 1
+#define VK_FALSE 0
+#define VK_LUID_SIZE_KHR VK_LUID_SIZE
+typedef uint32_t VkFlags;
+typedef uint64_t VkFlags64;
+typedef uint32_t VkBool32;
+typedef uint64_t VkDeviceSize;
+typedef VkFlags VkInstanceCreateFlags;
+typedef VkFlags VkBufferUsageFlags;
+typedef VkFlags VkBufferCreateFlags;
+typedef VkFlags VkQueueFlags;
+typedef VkFlags VkDeviceCreateFlags;
+typedef VkFlags VkPipelineStageFlags;
+typedef VkFlags VkAccessFlags;
+typedef VkFlags64 VkPipelineStageFlags2;
+typedef VkFlags64 VkAccessFlags2;
+typedef VkPipelineStageFlags2 VkPipelineStageFlags2KHR;
+typedef VkFlags VkXlibSurfaceCreateFlagsKHR;
+typedef VkFlags VkDebugUtilsMessageSeverityFlagsEXT;
+typedef VkFlags VkVideoSessionCreateFlagsKHR;
+VK_DEFINE_HANDLE(VkInstance);
+VK_DEFINE_HANDLE(VkPhysicalDevice);
+VK_DEFINE_HANDLE(VkDevice);
+VK_DEFINE_HANDLE(VkQueue);
+VK_DEFINE_HANDLE(VkCommandBuffer);
+VK_DEFINE_NON_DISPATCHABLE_HANDLE(VkBuffer);
+VK_DEFINE_NON_DISPATCHABLE_HANDLE(VkFence);
+VK_DEFINE_NON_DISPATCHABLE_HANDLE(VkCommandPool);
+VK_DEFINE_NON_DISPATCHABLE_HANDLE(VkSurfaceKHR);
+VK_DEFINE_NON_DISPATCHABLE_HANDLE(VkDebugUtilsMessengerEXT);
+VK_DEFINE_NON_DISPATCHABLE_HANDLE(VkVideoSessionKHR);
+typedef enum VkSystemAllocationScope { VK_SYSTEM_ALLOCATION_SCOPE_COMMAND = 0, VK_SYSTEM_ALLOCATION_SCOPE_OBJECT = 1 } VkSystemAllocationScope;
+typedef void* (VKAPI_PTR *PFN_vkAllocationFunction)(
+    void*                                       pUserData,
+    size_t                                      size,
+    size_t                                      alignment,
+    VkSystemAllocationScope                     allocationScope);
+typedef void (VKAPI_PTR *PFN_vkFreeFunction)(
+    void*                                       pUserData,
+    void*                                       pMemory);
+typedef void (VKAPI_PTR *PFN_vkVoidFunction)(void);
+typedef enum VkResult { VK_SUCCESS = 0, VK_NOT_READY = 1, VK_INCOMPLETE = 5, VK_ERROR_OUT_OF_HOST_MEMORY = -1, VK_ERROR_OUT_OF_DEVICE_MEMORY = -2, VK_ERROR_INITIALIZATION_FAILED = -3, VK_ERROR_DEVICE_LOST = -4, VK_ERROR_OUT_OF_POOL_MEMORY = -1000069000, VK_ERROR_SURFACE_LOST_KHR = -1000000000 } VkResult;
+typedef enum VkStructureType { VK_STRUCTURE_TYPE_APPLICATION_INFO = 0, VK_STRUCTURE_TYPE_INSTANCE_CREATE_INFO = 1, VK_STRUCTURE_TYPE_DEVICE_QUEUE_CREATE_INFO = 2, VK_STRUCTURE_TYPE_DEVICE_CREATE_INFO = 3, VK_STRUCTURE_TYPE_SUBMIT_INFO = 4, VK_STRUCTURE_TYPE_BUFFER_CREATE_INFO = 12, VK_STRUCTURE_TYPE_PHYSICAL_DEVICE_FEATURES_2 = 1000059000, VK_STRUCTURE_TYPE_PHYSICAL_DEVICE_PROPERTIES_2 = 1000059001, VK_STRUCTURE_TYPE_PHYSICAL_DEVICE_ID_PROPERTIES = 1000071002, VK_STRUCTURE_TYPE_PHYSICAL_DEVICE_DRIVER_PROPERTIES = 52, VK_STRUCTURE_TYPE_PHYSICAL_DEVICE_VULKAN_1_2_FEATURES = 51, VK_STRUCTURE_TYPE_MEMORY_BARRIER_2 = 1000314000, VK_STRUCTURE_TYPE_XLIB_SURFACE_CREATE_INFO_KHR = 1000004000, VK_STRUCTURE_TYPE_PHYSICAL_DEVICE_FEATURES_2_KHR = VK_STRUCTURE_TYPE_PHYSICAL_DEVICE_FEATURES_2, VK_STRUCTURE_TYPE_MEMORY_BARRIER_2_KHR = VK_STRUCTURE_TYPE_MEMORY_BARRIER_2, VK_STRUCTURE_TYPE_DEBUG_UTILS_OBJECT_NAME_INFO_EXT = 1000128000, VK_STRUCTURE_TYPE_VIDEO_SESSION_CREATE_INFO_KHR = 1000023000 } VkStructureType;
+typedef enum VkFormat { VK_FORMAT_UNDEFINED = 0, VK_FORMAT_R4G4_UNORM_PACK8 = 1, VK_FORMAT_R4G4B4A4_UNORM_PACK16 = 2, VK_FORMAT_R8G8B8A8_UNORM = 37, VK_FORMAT_R8G8B8A8_SRGB = 43 } VkFormat;
+typedef enum VkObjectType { VK_OBJECT_TYPE_UNKNOWN = 0, VK_OBJECT_TYPE_INSTANCE = 1, VK_OBJECT_TYPE_PHYSICAL_DEVICE = 2, VK_OBJECT_TYPE_DEVICE = 3, VK_OBJECT_TYPE_QUEUE = 4, VK_OBJECT_TYPE_COMMAND_BUFFER = 6, VK_OBJECT_TYPE_FENCE = 7, VK_OBJECT_TYPE_BUFFER = 9, VK_OBJECT_TYPE_COMMAND_POOL = 25, VK_OBJECT_TYPE_SURFACE_KHR = 1000000000, VK_OBJECT_TYPE_DEBUG_UTILS_MESSENGER_EXT = 1000128000, VK_OBJECT_TYPE_VIDEO_SESSION_KHR = 1000023000 } VkObjectType;
+typedef enum VkSharingMode { VK_SHARING_MODE_EXCLUSIVE = 0, VK_SHARING_MODE_CONCURRENT = 1 } VkSharingMode;
+typedef enum VkPhysicalDeviceType { VK_PHYSICAL_DEVICE_TYPE_OTHER = 0, VK_PHYSICAL_DEVICE_TYPE_INTEGRATED_GPU = 1, VK_PHYSICAL_DEVICE_TYPE_DISCRETE_GPU = 2 } VkPhysicalDeviceType;
+typedef enum VkPipelineCacheHeaderVersion { VK_PIPELINE_CACHE_HEADER_VERSION_ONE = 1 } VkPipelineCacheHeaderVersion;
+typedef enum VkDriverId { VK_DRIVER_ID_AMD_PROPRIETARY = 1, VK_DRIVER_ID_NVIDIA_PROPRIETARY = 4, VK_DRIVER_ID_INTEL_PROPRIETARY_WINDOWS = 5 } VkDriverId;
+typedef enum VkInstanceCreateFlagBits { VKINSTANCECREATEFLAGBITS_MAX_ENUM = 0x7FFFFFFF } VkInstanceCreateFlagBits;
+typedef enum VkBufferUsageFlagBits { VK_BUFFER_USAGE_TRANSFER_SRC_BIT = 0x1, VK_BUFFER_USAGE_TRANSFER_DST_BIT = 0x2, VK_BUFFER_USAGE_UNIFORM_BUFFER_BIT = 0x10, VK_BUFFER_USAGE_STORAGE_BUFFER_BIT = 0x20 } VkBufferUsageFlagBits;
+typedef enum VkBufferCreateFlagBits { VK_BUFFER_CREATE_SPARSE_BINDING_BIT = 0x1, VK_BUFFER_CREATE_PROTECTED_BIT = 0x8 } VkBufferCreateFlagBits;
+typedef enum VkQueueFlagBits { VK_QUEUE_GRAPHICS_BIT = 0x1, VK_QUEUE_COMPUTE_BIT = 0x2, VK_QUEUE_TRANSFER_BIT = 0x4 } VkQueueFlagBits;
+typedef enum VkPipelineStageFlagBits { VK_PIPELINE_STAGE_TOP_OF_PIPE_BIT = 0x1, VK_PIPELINE_STAGE_VERTEX_SHADER_BIT = 0x8, VK_PIPELINE_STAGE_FRAGMENT_SHADER_BIT = 0x80, VK_PIPELINE_STAGE_TRANSFER_BIT = 0x1000, VK_PIPELINE_STAGE_BOTTOM_OF_PIPE_BIT = 0x2000 } VkPipelineStageFlagBits;
+typedef enum VkAccessFlagBits { VK_ACCESS_SHADER_READ_BIT = 0x20, VK_ACCESS_SHADER_WRITE_BIT = 0x40, VK_ACCESS_TRANSFER_READ_BIT = 0x800, VK_ACCESS_TRANSFER_WRITE_BIT = 0x1000 } VkAccessFlagBits;
+typedef VkFlags64 VkPipelineStageFlagBits2;
+static const VkPipelineStageFlagBits2 VK_PIPELINE_STAGE_2_NONE = 0ULL;
+static const VkPipelineStageFlagBits2 VK_PIPELINE_STAGE_2_TOP_OF_PIPE_BIT = 0x1ULL;
+static const VkPipelineStageFlagBits2 VK_PIPELINE_STAGE_2_VERTEX_SHADER_BIT = 0x8ULL;
+static const VkPipelineStageFlagBits2 VK_PIPELINE_STAGE_2_FRAGMENT_SHADER_BIT = 0x80ULL;
+static const VkPipelineStageFlagBits2 VK_PIPELINE_STAGE_2_ALL_COMMANDS_BIT = 0x10000ULL;
+static const VkPipelineStageFlagBits2 VK_PIPELINE_STAGE_2_COPY_BIT = 0x100000000ULL;
+static const VkPipelineStageFlagBits2 VK_PIPELINE_STAGE_2_TOP_BIT = 0x8000000000000000ULL;
+typedef VkFlags64 VkAccessFlagBits2;
+static const VkAccessFlagBits2 VK_ACCESS_2_NONE = 0ULL;
+static const VkAccessFlagBits2 VK_ACCESS_2_SHADER_READ_BIT = 0x20ULL;
+static const VkAccessFlagBits2 VK_ACCESS_2_SHADER_WRITE_BIT = 0x40ULL;
+static const VkAccessFlagBits2 VK_ACCESS_2_TRANSFER_READ_BIT = 0x800ULL;
+static const VkAccessFlagBits2 VK_ACCESS_2_TRANSFER_WRITE_BIT = 0x1000ULL;
+static const VkAccessFlagBits2 VK_ACCESS_2_SHADER_SAMPLED_READ_BIT = 0x100000000ULL;
+typedef enum VkDebugUtilsMessageSeverityFlagBitsEXT { VK_DEBUG_UTILS_MESSAGE_SEVERITY_VERBOSE_BIT_EXT = 0x1, VK_DEBUG_UTILS_MESSAGE_SEVERITY_INFO_BIT_EXT = 0x10, VK_DEBUG_UTILS_MESSAGE_SEVERITY_WARNING_BIT_EXT = 0x100, VK_DEBUG_UTILS_MESSAGE_SEVERITY_ERROR_BIT_EXT = 0x1000 } VkDebugUtilsMessageSeverityFlagBitsEXT;
+typedef struct VkPipelineCacheHeaderVersionOne {
+    uint32_t                     headerSize;
+    VkPipelineCacheHeaderVersion headerVersion;
+    uint32_t                     vendorID;
+    uint32_t                     deviceID;
+    uint8_t                      pipelineCacheUUID[VK_UUID_SIZE];
+} VkPipelineCacheHeaderVersionOne;
+typedef struct VkConformanceVersion {
+    uint8_t                          major;
+    uint8_t                          minor;
+    uint8_t                          subminor;
+    uint8_t                          patch;
+} VkConformanceVersion;
+typedef struct VkPhysicalDeviceDriverProperties {
+    VkStructureType sType;
+    void*                            pNext;
+    VkDriverId                       driverID;
+    char                             driverName[VK_MAX_DRIVER_NAME_SIZE];
+    char                             driverInfo[VK_MAX_DRIVER_INFO_SIZE];
+    VkConformanceVersion             conformanceVersion;
+} VkPhysicalDeviceDriverProperties;
+typedef struct VkBaseOutStructure {
+    VkStructureType sType;
+    struct VkBaseOutStructure* pNext;
+} VkBaseOutStructure;
+typedef struct VkBaseInStructure {
+    VkStructureType sType;
+    const struct VkBaseInStructure* pNext;
+} VkBaseInStructure;
+typedef struct VkExtent2D {
+    uint32_t        width;
+    uint32_t        height;
+} VkExtent2D;
+typedef struct VkExtent3D {
+    uint32_t        width;
+    uint32_t        height;
+    uint32_t        depth;
+} VkExtent3D;
+typedef struct VkOffset2D {
+    int32_t        x;
+    int32_t        y;
+} VkOffset2D;
+typedef struct VkRect2D {
+    VkOffset2D     offset;
+    VkExtent2D     extent;
+} VkRect2D;
+typedef struct VkAllocationCallbacks {
+    void*           pUserData;
+    PFN_vkAllocationFunction   pfnAllocation;
+    PFN_vkFreeFunction         pfnFree;
+} VkAllocationCallbacks;
+typedef struct VkApplicationInfo {
+    VkStructureType sType;
+    const void*     pNext;
+    const char*     pApplicationName;
+    uint32_t        applicationVersion;
+    const char*     pEngineName;
+    uint32_t        engineVersion;
+    uint32_t        apiVersion;
+} VkApplicationInfo;
+typedef struct VkInstanceCreateInfo {
+    VkStructureType sType;
+    const void*     pNext;
+    VkInstanceCreateFlags  flags;
+    const VkApplicationInfo* pApplicationInfo;
+    uint32_t               enabledLayerCount;
+    const char* const*      ppEnabledLayerNames;
+    uint32_t               enabledExtensionCount;
+    const char* const*      ppEnabledExtensionNames;
+} VkInstanceCreateInfo;
+typedef struct VkBufferCreateInfo {
+    VkStructureType sType;
+    const void*            pNext;
+    VkBufferCreateFlags    flags;
+    VkDeviceSize           size;
+    VkBufferUsageFlags     usage;
+    VkSharingMode          sharingMode;
+    uint32_t               queueFamilyIndexCount;
+    const uint32_t*        pQueueFamilyIndices;
+} VkBufferCreateInfo;
+typedef struct VkDeviceQueueCreateInfo {
+    VkStructureType sType;
+    const void*     pNext;
+    uint32_t               queueFamilyIndex;
+    uint32_t               queueCount;
+    const float*    pQueuePriorities;
+} VkDeviceQueueCreateInfo;
+typedef struct VkPhysicalDeviceFeatures {
+    VkBool32               robustBufferAccess;
+    VkBool32               geometryShader;
+} VkPhysicalDeviceFeatures;
+typedef struct VkDeviceCreateInfo {
+    VkStructureType sType;
+    const void*     pNext;
+    VkDeviceCreateFlags    flags;
+    uint32_t        queueCreateInfoCount;
+    const VkDeviceQueueCreateInfo* pQueueCreateInfos;
+    uint32_t               enabledExtensionCount;
+    const char* const*      ppEnabledExtensionNames;
+    const VkPhysicalDeviceFeatures* pEnabledFeatures;
+} VkDeviceCreateInfo;
+typedef struct VkPhysicalDeviceSparseProperties {
+    VkBool32               residencyStandard2DBlockShape;
+} VkPhysicalDeviceSparseProperties;
+typedef struct VkPhysicalDeviceProperties {
+    uint32_t       apiVersion;
+    uint32_t       driverVersion;
+    uint32_t       vendorID;
+    uint32_t       deviceID;
+    VkPhysicalDeviceType deviceType;
+    char           deviceName[VK_MAX_PHYSICAL_DEVICE_NAME_SIZE];
+    uint8_t        pipelineCacheUUID[VK_UUID_SIZE];
+    VkPhysicalDeviceSparseProperties sparseProperties;
+} VkPhysicalDeviceProperties;
+typedef struct VkQueueFamilyProperties {
+    VkQueueFlags           queueFlags;
+    uint32_t               queueCount;
+    uint32_t               timestampValidBits;
+    VkExtent3D             minImageTransferGranularity;
+} VkQueueFamilyProperties;
+typedef struct VkPhysicalDeviceFeatures2 {
+    VkStructureType sType;
+    void*                            pNext;
+    VkPhysicalDeviceFeatures         features;
+} VkPhysicalDeviceFeatures2;
+typedef struct VkPhysicalDeviceVulkan12Features {
+    VkStructureType sType;
+    void*      pNext;
+    VkBool32                         samplerMirrorClampToEdge;
+    VkBool32                         shaderFloat16;
+} VkPhysicalDeviceVulkan12Features;
+typedef struct VkPhysicalDeviceIDProperties {
+    VkStructureType sType;
+    void*                            pNext;
+    uint8_t                          deviceUUID[VK_UUID_SIZE];
+    uint8_t                          driverUUID[VK_UUID_SIZE];
+    uint8_t                          deviceLUID[VK_LUID_SIZE];
+    uint32_t                         deviceNodeMask;
+    VkBool32                         deviceLUIDValid;
+} VkPhysicalDeviceIDProperties;
+typedef struct VkPhysicalDeviceProperties2 {
+    VkStructureType sType;
+    void*                            pNext;
+    VkPhysicalDeviceProperties       properties;
+} VkPhysicalDeviceProperties2;
+typedef struct VkSubmitInfo {
+    VkStructureType sType;
+    const void*     pNext;
+    uint32_t       waitSemaphoreCount;
+    const VkPipelineStageFlags*           pWaitDstStageMask;
+    uint32_t       commandBufferCount;
+    const VkCommandBuffer*     pCommandBuffers;
+} VkSubmitInfo;
+typedef struct VkDebugUtilsObjectNameInfoEXT {
+    VkStructureType sType;
+    const void*                            pNext;
+    VkObjectType                                           objectType;
+    uint64_t                                               objectHandle;
+    const char*      pObjectName;
+} VkDebugUtilsObjectNameInfoEXT;
+typedef struct VkXlibSurfaceCreateInfoKHR {
+    VkStructureType sType;
+    const void*                      pNext;
+    VkXlibSurfaceCreateFlagsKHR   flags;
+    Display*                                   dpy;
+    Window                                     window;
+} VkXlibSurfaceCreateInfoKHR;
+typedef struct VkVideoSessionCreateInfoKHR {
+    VkStructureType sType;
+    const void*                          pNext;
+    uint32_t                             queueFamilyIndex;
+    VkVideoSessionCreateFlagsKHR flags;
+    const StdVideoH264ProfileIdc*      pStdProfile;
+} VkVideoSessionCreateInfoKHR;
+typedef struct VkMemoryBarrier2 {
+    VkStructureType sType;
+    const void*                            pNext;
+    VkPipelineStageFlags2  srcStageMask;
+    VkAccessFlags2         srcAccessMask;
+} VkMemoryBarrier2;
+typedef VkMemoryBarrier2 VkMemoryBarrier2KHR;
+typedef struct VkTransformMatrixKHR {
+    float                  matrix[3][4];
+} VkTransformMatrixKHR;
+typedef struct VkAccelerationStructureInstanceKHR {
+    VkTransformMatrixKHR   transform;
+    uint32_t               instanceCustomIndex:24;
+    uint32_t               mask:8;
+    uint64_t               accelerationStructureReference;
+} VkAccelerationStructureInstanceKHR;
+typedef union VkClearColorValue {
+    float                  float32[4];
+    int32_t                int32[4];
+    uint32_t               uint32[4];
+} VkClearColorValue;
+VkResult vkCreateInstance(const VkInstanceCreateInfo* pCreateInfo, const VkAllocationCallbacks* pAllocator, VkInstance* pInstance);
+typedef VkResult (VKAPI_PTR *PFN_vkCreateInstance)(const VkInstanceCreateInfo* pCreateInfo, const VkAllocationCallbacks* pAllocator, VkInstance* pInstance);
+void vkDestroyInstance(VkInstance instance, const VkAllocationCallbacks* pAllocator);
+typedef void (VKAPI_PTR *PFN_vkDestroyInstance)(VkInstance instance, const VkAllocationCallbacks* pAllocator);
+VkResult vkEnumeratePhysicalDevices(VkInstance instance, uint32_t* pPhysicalDeviceCount, VkPhysicalDevice* pPhysicalDevices);
+typedef VkResult (VKAPI_PTR *PFN_vkEnumeratePhysicalDevices)(VkInstance instance, uint32_t* pPhysicalDeviceCount, VkPhysicalDevice* pPhysicalDevices);
+PFN_vkVoidFunction vkGetInstanceProcAddr(VkInstance instance, const char* pName);
+typedef PFN_vkVoidFunction (VKAPI_PTR *PFN_vkGetInstanceProcAddr)(VkInstance instance, const char* pName);
+PFN_vkVoidFunction vkGetDeviceProcAddr(VkDevice device, const char* pName);
+typedef PFN_vkVoidFunction (VKAPI_PTR *PFN_vkGetDeviceProcAddr)(VkDevice device, const char* pName);
+void vkGetPhysicalDeviceProperties(VkPhysicalDevice physicalDevice, VkPhysicalDeviceProperties* pProperties);
+typedef void (VKAPI_PTR *PFN_vkGetPhysicalDeviceProperties)(VkPhysicalDevice physicalDevice, VkPhysicalDeviceProperties* pProperties);
+void vkGetPhysicalDeviceProperties2(VkPhysicalDevice physicalDevice, VkPhysicalDeviceProperties2* pProperties);
+typedef void (VKAPI_PTR *PFN_vkGetPhysicalDeviceProperties2)(VkPhysicalDevice physicalDevice, VkPhysicalDeviceProperties2* pProperties);
+void vkGetPhysicalDeviceFeatures2(VkPhysicalDevice physicalDevice, VkPhysicalDeviceFeatures2* pFeatures);
+typedef void (VKAPI_PTR *PFN_vkGetPhysicalDeviceFeatures2)(VkPhysicalDevice physicalDevice, VkPhysicalDeviceFeatures2* pFeatures);
+void vkGetPhysicalDeviceQueueFamilyProperties(VkPhysicalDevice physicalDevice, uint32_t* pQueueFamilyPropertyCount, VkQueueFamilyProperties* pQueueFamilyProperties);
+typedef void (VKAPI_PTR *PFN_vkGetPhysicalDeviceQueueFamilyProperties)(VkPhysicalDevice physicalDevice, uint32_t* pQueueFamilyPropertyCount, VkQueueFamilyProperties* pQueueFamilyProperties);
+VkResult vkCreateDevice(VkPhysicalDevice physicalDevice, const VkDeviceCreateInfo* pCreateInfo, const VkAllocationCallbacks* pAllocator, VkDevice* pDevice);
+typedef VkResult (VKAPI_PTR *PFN_vkCreateDevice)(VkPhysicalDevice physicalDevice, const VkDeviceCreateInfo* pCreateInfo, const VkAllocationCallbacks* pAllocator, VkDevice* pDevice);
+void vkDestroyDevice(VkDevice device, const VkAllocationCallbacks* pAllocator);
+typedef void (VKAPI_PTR *PFN_vkDestroyDevice)(VkDevice device, const VkAllocationCallbacks* pAllocator);
+void vkGetDeviceQueue(VkDevice device, uint32_t queueFamilyIndex, uint32_t queueIndex, VkQueue* pQueue);
+typedef void (VKAPI_PTR *PFN_vkGetDeviceQueue)(VkDevice device, uint32_t queueFamilyIndex, uint32_t queueIndex, VkQueue* pQueue);
+VkResult vkQueueSubmit(VkQueue queue, uint32_t submitCount, const VkSubmitInfo* pSubmits, VkFence fence);
+typedef VkResult (VKAPI_PTR *PFN_vkQueueSubmit)(VkQueue queue, uint32_t submitCount, const VkSubmitInfo* pSubmits, VkFence fence);
+VkResult vkCreateBuffer(VkDevice device, const VkBufferCreateInfo* pCreateInfo, const VkAllocationCallbacks* pAllocator, VkBuffer* pBuffer);
+typedef VkResult (VKAPI_PTR *PFN_vkCreateBuffer)(VkDevice device, const VkBufferCreateInfo* pCreateInfo, const VkAllocationCallbacks* pAllocator, VkBuffer* pBuffer);
+void vkDestroyBuffer(VkDevice device, VkBuffer buffer, const VkAllocationCallbacks* pAllocator);
+typedef void (VKAPI_PTR *PFN_vkDestroyBuffer)(VkDevice device, VkBuffer buffer, const VkAllocationCallbacks* pAllocator);
+void vkDestroyFence(VkDevice device, VkFence fence, const VkAllocationCallbacks* pAllocator);
+typedef void (VKAPI_PTR *PFN_vkDestroyFence)(VkDevice device, VkFence fence, const VkAllocationCallbacks* pAllocator);
+VkResult vkWaitForFences(VkDevice device, uint32_t fenceCount, const VkFence* pFences, VkBool32 waitAll, uint64_t timeout);
+typedef VkResult (VKAPI_PTR *PFN_vkWaitForFences)(VkDevice device, uint32_t fenceCount, const VkFence* pFences, VkBool32 waitAll, uint64_t timeout);
+void vkFreeCommandBuffers(VkDevice device, VkCommandPool commandPool, uint32_t commandBufferCount, const VkCommandBuffer* pCommandBuffers);
+typedef void (VKAPI_PTR *PFN_vkFreeCommandBuffers)(VkDevice device, VkCommandPool commandPool, uint32_t commandBufferCount, const VkCommandBuffer* pCommandBuffers);
+void vkDestroyCommandPool(VkDevice device, VkCommandPool commandPool, const VkAllocationCallbacks* pAllocator);
+typedef void (VKAPI_PTR *PFN_vkDestroyCommandPool)(VkDevice device, VkCommandPool commandPool, const VkAllocationCallbacks* pAllocator);
+void vkCmdPipelineBarrier2(VkCommandBuffer commandBuffer, const VkMemoryBarrier2* pBarrier);
+typedef void (VKAPI_PTR *PFN_vkCmdPipelineBarrier2)(VkCommandBuffer commandBuffer, const VkMemoryBarrier2* pBarrier);
+#define vkCmdPipelineBarrier2KHR vkCmdPipelineBarrier2
+void vkDestroySurfaceKHR(VkInstance instance, VkSurfaceKHR surface, const VkAllocationCallbacks* pAllocator);
+typedef void (VKAPI_PTR *PFN_vkDestroySurfaceKHR)(VkInstance instance, VkSurfaceKHR surface, const VkAllocationCallbacks* pAllocator);
+VkResult vkCreateXlibSurfaceKHR(VkInstance instance, const VkXlibSurfaceCreateInfoKHR* pCreateInfo, const VkAllocationCallbacks* pAllocator, VkSurfaceKHR* pSurface);
+typedef VkResult (VKAPI_PTR *PFN_vkCreateXlibSurfaceKHR)(VkInstance instance, const VkXlibSurfaceCreateInfoKHR* pCreateInfo, const VkAllocationCallbacks* pAllocator, VkSurfaceKHR* pSurface);
+VkResult vkSetDebugUtilsObjectNameEXT(VkDevice device, const VkDebugUtilsObjectNameInfoEXT* pNameInfo);
+typedef VkResult (VKAPI_PTR *PFN_vkSetDebugUtilsObjectNameEXT)(VkDevice device, const VkDebugUtilsObjectNameInfoEXT* pNameInfo);
+void vkDestroyDebugUtilsMessengerEXT(VkInstance instance, VkDebugUtilsMessengerEXT messenger, const VkAllocationCallbacks* pAllocator);
+typedef void (VKAPI_PTR *PFN_vkDestroyDebugUtilsMessengerEXT)(VkInstance instance, VkDebugUtilsMessengerEXT messenger, const VkAllocationCallbacks* pAllocator);
+VkResult vkCreateVideoSessionKHR(VkDevice device, const VkVideoSessionCreateInfoKHR* pCreateInfo, const VkAllocationCallbacks* pAllocator, VkVideoSessionKHR* pVideoSession);
+typedef VkResult (VKAPI_PTR *PFN_vkCreateVideoSessionKHR)(VkDevice device, const VkVideoSessionCreateInfoKHR* pCreateInfo, const VkAllocationCallbacks* pAllocator, VkVideoSessionKHR* pVideoSession);
+void vkDestroyVideoSessionKHR(VkDevice device, VkVideoSessionKHR videoSession, const VkAllocationCallbacks* pAllocator);
+typedef void (VKAPI_PTR *PFN_vkDestroyVideoSessionKHR)(VkDevice device, VkVideoSessionKHR videoSession, const VkAllocationCallbacks* pAllocator);
@@ -0,0 +1,43 @@
+package cppgen
+
+// TypeListEntry is a type of a TypeList in the header, Protect guards it
+// together with the comma before it.
+type TypeListEntry struct {
+	Protect Protect
+	Name    string
+}
+
+// typeList puts the unguarded entries first, so that the first line of the
+// list, which has no comma, is always there if any is unguarded.
+func typeList(entries []TypeListEntry) []TypeListEntry {
+	out := make([]TypeListEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.Protect.Begin == "" {
+			out = append(out, e)
+		}
+	}
+	for _, e := range entries {
+		if e.Protect.Begin != "" {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// HandleList is vk::AllHandles.
+func (ctx *Context) HandleList() []TypeListEntry {
+	entries := make([]TypeListEntry, len(ctx.Handles))
+	for i, h := range ctx.Handles {
+		entries[i] = TypeListEntry{h.Protect, h.Name}
+	}
+	return typeList(entries)
+}
+
+// StructList is vk::AllStructs.
+func (ctx *Context) StructList() []TypeListEntry {
+	entries := make([]TypeListEntry, len(ctx.Structs))
+	for i, s := range ctx.Structs {
+		entries[i] = TypeListEntry{s.Protect, s.Name}
+	}
+	return typeList(entries)
+}
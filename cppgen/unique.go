@@ -0,0 +1,86 @@
+package cppgen
+
+import "strings"
+
+// UniqueHandle is a handle together with the command destroying it. Owners
+// are the handle parameters preceding the handle in the destroy command
+// (device, pool), captured by the wrapper. Array is set for commands freeing
+// an array of handles with a count (vkFreeCommandBuffers).
+type UniqueHandle struct {
+	Protect   Protect
+	Name      string
+	Handle    *Handle
+	Owners    []CommandParameter
+	Destroy   string
+	Allocator bool
+	Array     bool
+}
+
+// newUniqueHandles maps handles to their vkDestroy* or vkFree* commands,
+// handles without one (physical devices, queues) get no wrapper.
+func newUniqueHandles(ctx *Context) []UniqueHandle {
+	destroys := map[*Handle]UniqueHandle{}
+	for i := range ctx.Commands {
+		c := &ctx.Commands[i]
+		destroy := strings.HasPrefix(c.VkName, "vkDestroy")
+		if !destroy && !strings.HasPrefix(c.VkName, "vkFree") {
+			continue
+		}
+		u, ok := ctx.destroyCommand(c)
+		if !ok {
+			continue
+		}
+		// vkDestroy* wins over vkFree*
+		if _, ok := destroys[u.Handle]; ok && !destroy {
+			continue
+		}
+		destroys[u.Handle] = u
+	}
+	var out []UniqueHandle
+	for _, h := range ctx.Handles {
+		if u, ok := destroys[h]; ok {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// destroyCommand checks that the command takes owner handles followed by the
+// destroyed handle and an optional allocator, or owner handles followed by a
+// count and an array of handles.
+func (ctx *Context) destroyCommand(c *Command) (UniqueHandle, bool) {
+	u := UniqueHandle{Protect: c.Protect, Destroy: c.Name}
+	params := c.Parameters
+	if n := len(params); n > 0 && params[n-1].AnalyzedType.Type == "VkAllocationCallbacks" {
+		u.Allocator = true
+		params = params[:n-1]
+	}
+	n := len(params)
+	if n == 0 {
+		return u, false
+	}
+	last := params[n-1].AnalyzedType
+	u.Handle = ctx.Handle(last.Type)
+	if u.Handle == nil {
+		return u, false
+	}
+	switch {
+	case last.IsBlank:
+		u.Owners = params[:n-1]
+	case !u.Allocator && n >= 2 && last.IsPointer && !last.IsArray && params[n-2].AnalyzedType.IsBlank && params[n-2].AnalyzedType.Type == "uint32_t":
+		u.Array = true
+		u.Owners = params[:n-2]
+	default:
+		return u, false
+	}
+	for _, o := range u.Owners {
+		if !o.AnalyzedType.IsBlank || ctx.Handle(o.AnalyzedType.Type) == nil {
+			return u, false
+		}
+	}
+	if u.Protect.Begin == "" {
+		u.Protect = u.Handle.Protect
+	}
+	u.Name = "Unique" + u.Handle.Name
+	return u, true
+}
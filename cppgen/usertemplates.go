@@ -0,0 +1,34 @@
+package cppgen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// loadTemplates parses the *.tmpl files of dir into t, in name order.
+// Templates they define replace the built-in ones of the same name, those
+// with new names can be invoked by the ones they replace. The empty
+// headerextra, handleextra and structextra templates are there to be
+// replaced, they add to the preamble, the handle classes and the struct
+// classes.
+func loadTemplates(t *template.Template, dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("%s: no *.tmpl files", dir)
+	}
+	for _, f := range files {
+		text, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		if _, err := t.New(filepath.Base(f)).Parse(string(text)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
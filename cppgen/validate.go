@@ -0,0 +1,438 @@
+package cppgen
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// ValidationError describes a registry entity which breaks an assumption the
+// generator relies on. Entity is the name of the type, enum or command, Kind
+// is its kind ("struct", "command", etc.). Errors in the shape of the XML
+// have the Line of the element and its path as Kind, the name attribute of
+// the element is the Entity if there is one.
+type ValidationError struct {
+	Line   int
+	Kind   string
+	Entity string
+	Msg    string
+}
+
+func (e *ValidationError) Error() string {
+	s := e.Kind
+	if e.Entity != "" {
+		s += " " + e.Entity
+	}
+	s += ": " + e.Msg
+	if e.Line > 0 {
+		s = fmt.Sprintf("%d: %s", e.Line, s)
+	}
+	return s
+}
+
+type registryValidator struct {
+	errs []*ValidationError
+}
+
+func (v *registryValidator) errorf(kind, entity, format string, args ...interface{}) {
+	v.errs = append(v.errs, &ValidationError{
+		Kind:   kind,
+		Entity: entity,
+		Msg:    fmt.Sprintf(format, args...),
+	})
+}
+
+// validateSpec checks the registry XML read from r: the shape of its
+// elements against schemaRules, then the registry it decodes to as
+// validateRegistry does. Entities of other APIs than generatedAPI are left
+// out, as they are when generating.
+func validateSpec(r io.Reader) ([]*ValidationError, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var v registryValidator
+	if err := v.checkSchema(bytes.NewReader(data)); err != nil {
+		return v.errs, err
+	}
+	reg, err := registry.Parse(bytes.NewReader(data))
+	if err != nil {
+		return v.errs, err
+	}
+	selectAPI(reg)
+	v.checkRegistry(reg)
+	return v.errs, nil
+}
+
+// validateRegistry checks the structural invariants of vk.xml the generator
+// depends on. The generator would produce subtly wrong output if any of them
+// doesn't hold.
+func validateRegistry(reg *registry.Registry) []*ValidationError {
+	var v registryValidator
+	v.checkRegistry(reg)
+	return v.errs
+}
+
+func (v *registryValidator) checkRegistry(reg *registry.Registry) {
+	enums := map[string]bool{}
+	for _, e := range reg.Enums {
+		if e.External {
+			continue
+		}
+		if e.Name == "" {
+			v.errorf("enums", "<unnamed>", "missing name attribute")
+			continue
+		}
+		if enums[e.Name] {
+			v.errorf("enums", e.Name, "defined more than once")
+		}
+		enums[e.Name] = true
+		for i, ev := range e.Values {
+			if ev.Name == "" {
+				v.errorf("enums", e.Name, "value %d has no name", i)
+			}
+			if e.Type != "" && ev.Value != "" {
+				if _, err := registry.ParseEnumValue(ev.Value); err != nil {
+					v.errorf("enums", e.Name, "value %s: %s", memberLabel(i, ev.Name), err)
+				}
+			}
+		}
+	}
+
+	constants := apiConstants(reg)
+	types := map[string]bool{}
+	for _, t := range reg.Types.Type {
+		if t.External {
+			continue
+		}
+		if t.Alias != "" {
+			continue
+		}
+		switch t.Category {
+		case "handle":
+			if t.InnerName == "" {
+				v.errorf("handle", t.Name, "missing <name>")
+				continue
+			}
+			if t.InnerType != "VK_DEFINE_HANDLE" && t.InnerType != "VK_DEFINE_NON_DISPATCHABLE_HANDLE" {
+				v.errorf("handle", t.InnerName, "unknown handle definition macro %q", t.InnerType)
+			}
+			types[t.InnerName] = true
+		case "bitmask":
+			if t.InnerName == "" {
+				v.errorf("bitmask", t.Name, "missing <name>")
+				continue
+			}
+			if t.InnerType == "" {
+				v.errorf("bitmask", t.InnerName, "missing <type>")
+			}
+			if t.BitValues != "" && t.Requires != "" && t.BitValues != t.Requires {
+				v.errorf("bitmask", t.InnerName, "bitvalues %s conflicts with requires %s", t.BitValues, t.Requires)
+			}
+			types[t.InnerName] = true
+		case "struct", "union":
+			if t.Name == "" {
+				v.errorf(t.Category, "<unnamed>", "missing name attribute")
+				continue
+			}
+			if types[t.Name] {
+				v.errorf(t.Category, t.Name, "defined more than once")
+			}
+			types[t.Name] = true
+			if len(t.Members) == 0 {
+				v.errorf(t.Category, t.Name, "has no members")
+			}
+			for i, m := range t.Members {
+				if m.Name == "" {
+					v.errorf(t.Category, t.Name, "member %d has no <name>", i)
+				}
+				if m.Type == "" {
+					v.errorf(t.Category, t.Name, "member %s has no <type>", memberLabel(i, m.Name))
+				}
+				if strings.Count(m.Extra, "[") != strings.Count(m.Extra, "]") {
+					v.errorf(t.Category, t.Name, "member %s has unbalanced array brackets", memberLabel(i, m.Name))
+				}
+				if _, ok := constants[m.Enum]; m.Enum != "" && !ok {
+					v.errorf(t.Category, t.Name, "member %s has unknown array size %s", memberLabel(i, m.Name), m.Enum)
+				}
+			}
+		case "enum":
+			if t.Name == "" {
+				v.errorf("enum", "<unnamed>", "missing name attribute")
+			}
+		case "basetype", "funcpointer":
+			if t.InnerName == "" {
+				v.errorf(t.Category, t.Name, "missing <name>")
+			}
+		}
+	}
+
+	for _, e := range reg.Extensions.Extension {
+		if e.Depends == "" {
+			continue
+		}
+		if _, err := parseDepends(e.Depends); err != nil {
+			v.errorf("extension", e.Name, "depends: %s", err)
+		}
+	}
+
+	commands := map[string]bool{}
+	for i, c := range reg.Commands.Command {
+		if c.Alias != "" {
+			continue
+		}
+		name := c.Proto.Name
+		if name == "" {
+			v.errorf("command", fmt.Sprintf("#%d", i), "missing <proto> with <name>")
+			continue
+		}
+		if commands[name] {
+			v.errorf("command", name, "defined more than once")
+		}
+		commands[name] = true
+		if c.Proto.Type == "" {
+			v.errorf("command", name, "<proto> has no return <type>")
+		}
+		for j, p := range c.Params {
+			if p.Name == "" {
+				v.errorf("command", name, "parameter %d has no <name>", j)
+			}
+			if p.Type == "" {
+				v.errorf("command", name, "parameter %s has no <type>", memberLabel(j, p.Name))
+			}
+		}
+	}
+}
+
+func memberLabel(i int, name string) string {
+	if name == "" {
+		return fmt.Sprintf("%d", i)
+	}
+	return name
+}
+
+// schemaRule is a subset of the registry.rnc rules for one element, the ones
+// the generator depends on.
+type schemaRule struct {
+	required []string            // required attributes
+	values   map[string][]string // allowed attribute values
+	order    []string            // children that must appear in this order
+	children []string            // required children, unless aliased
+
+	// additional checks once the element is complete, return "" if fine
+	check func(attrs map[string]string, children map[string]bool) string
+}
+
+var typeCategories = []string{"", "basetype", "bitmask", "define", "enum", "funcpointer", "group", "handle", "include", "struct", "union"}
+
+// schemaRules are keyed by "<parent>/<element>".
+var schemaRules = map[string]*schemaRule{
+	"types/type": {
+		values: map[string][]string{"category": typeCategories},
+	},
+	"type/member": {
+		order: []string{"type", "name", "enum", "comment"},
+	},
+	"registry/enums": {
+		required: []string{"name"},
+		values: map[string][]string{
+			"type":     {"enum", "bitmask", "constants"},
+			"bitwidth": {"32", "64"},
+		},
+	},
+	"enums/enum": {
+		required: []string{"name"},
+		check: func(attrs map[string]string, children map[string]bool) string {
+			return exactlyOne(attrs, "value", "bitpos", "alias")
+		},
+	},
+	"require/enum": {
+		required: []string{"name"},
+		values:   map[string][]string{"dir": {"-"}},
+		check: func(attrs map[string]string, children map[string]bool) string {
+			if attrs["extends"] == "" {
+				return ""
+			}
+			return exactlyOne(attrs, "value", "bitpos", "offset", "alias")
+		},
+	},
+	"require/type":    {required: []string{"name"}},
+	"require/command": {required: []string{"name"}},
+	"commands/command": {
+		order: []string{"proto", "param"},
+		check: func(attrs map[string]string, children map[string]bool) string {
+			if attrs["alias"] != "" && attrs["name"] == "" {
+				return "alias without name attribute"
+			}
+			return ""
+		},
+	},
+	"command/proto": {
+		order: []string{"type", "name"},
+	},
+	"command/param": {
+		order: []string{"type", "name"},
+	},
+	"registry/feature": {
+		required: []string{"api", "name", "number"},
+	},
+	"extensions/extension": {
+		required: []string{"name", "number", "supported"},
+	},
+	"platforms/platform": {
+		required: []string{"name", "protect"},
+	},
+}
+
+func exactlyOne(attrs map[string]string, names ...string) string {
+	n := 0
+	for _, name := range names {
+		if _, ok := attrs[name]; ok {
+			n++
+		}
+	}
+	if n != 1 {
+		return fmt.Sprintf("needs exactly one of %s attributes", strings.Join(names, ", "))
+	}
+	return ""
+}
+
+type schemaFrame struct {
+	name     string
+	line     int
+	attrs    map[string]string
+	children map[string]bool
+	rank     int // highest order rank among children seen so far
+	last     string
+}
+
+// checkSchema checks the registry XML against schemaRules, reporting errors
+// with line numbers of the offending elements. Elements of other APIs than
+// generatedAPI are skipped.
+func (v *registryValidator) checkSchema(r io.Reader) error {
+	var stack []*schemaFrame
+	path := func() string {
+		names := make([]string, len(stack))
+		for i, f := range stack {
+			names[i] = f.name
+		}
+		return strings.Join(names, "/")
+	}
+	rule := func() *schemaRule {
+		if len(stack) < 2 {
+			return nil
+		}
+		return schemaRules[stack[len(stack)-2].name+"/"+stack[len(stack)-1].name]
+	}
+	errorf := func(line int, format string, args ...interface{}) {
+		v.errs = append(v.errs, &ValidationError{
+			Line:   line,
+			Kind:   path(),
+			Entity: stack[len(stack)-1].attrs["name"],
+			Msg:    fmt.Sprintf(format, args...),
+		})
+	}
+
+	d := xml.NewDecoder(r)
+	for {
+		line, _ := d.InputPos()
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if !registry.HasAPI(attrValue(t.Attr, "api"), generatedAPI) {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children[t.Name.Local] = true
+				if pr := rule(); pr != nil {
+					for rank, name := range pr.order {
+						if name != t.Name.Local {
+							continue
+						}
+						if rank < parent.rank {
+							errorf(line, "<%s> must come before <%s>", name, parent.last)
+						}
+						parent.rank, parent.last = rank, name
+					}
+				}
+			}
+			f := &schemaFrame{name: t.Name.Local, line: line, attrs: map[string]string{}, children: map[string]bool{}}
+			for _, a := range t.Attr {
+				f.attrs[a.Name.Local] = a.Value
+			}
+			stack = append(stack, f)
+			r := rule()
+			if r == nil {
+				continue
+			}
+			for _, name := range r.required {
+				if _, ok := f.attrs[name]; !ok {
+					errorf(line, "missing %s attribute", name)
+				}
+			}
+			for name, allowed := range r.values {
+				if v, ok := f.attrs[name]; ok && !listContains(allowed, v) {
+					errorf(line, "invalid %s %q, expected one of %s", name, v, quoteList(allowed))
+				}
+			}
+		case xml.EndElement:
+			f := stack[len(stack)-1]
+			if r := rule(); r != nil {
+				if f.attrs["alias"] == "" {
+					for _, c := range r.children {
+						if !f.children[c] {
+							errorf(f.line, "missing <%s>", c)
+						}
+					}
+				}
+				if r.check != nil {
+					if msg := r.check(f.attrs, f.children); msg != "" {
+						errorf(f.line, "%s", msg)
+					}
+				}
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+func quoteList(list []string) string {
+	quoted := make([]string, len(list))
+	for i, v := range list {
+		quoted[i] = strconv.Quote(v)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func listContains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func attrValue(attrs []xml.Attr, name string) string {
+	for _, a := range attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package cppgen
+
+import (
+	"strings"
+	"testing"
+)
+
+const validateTestSpec = `<registry>
+    <types>
+        <type category="struct" name="VkFoo" api="vulkan">
+            <member><type>uint32_t</type> <name>x</name></member>
+        </type>
+        <type category="struct" name="VkFoo" api="vulkansc">
+            <member><name>x</name><type>uint32_t</type></member>
+        </type>
+        <type category="struct" name="VkBar">
+            <member><type>uint32_t</type> <name>x</name></member>
+            <member api="vulkansc"><name>y</name><type>uint32_t</type></member>
+        </type>
+        <type category="struct" name="VkBar">
+            <member><type>uint32_t</type> <name>x</name></member>
+        </type>
+    </types>
+    <commands>
+        <command api="vulkan"><proto><type>void</type> <name>vkFoo</name></proto></command>
+        <command api="vulkansc"><proto><type>void</type> <name>vkFoo</name></proto></command>
+    </commands>
+    <feature name="VK_VERSION_1_0" number="1.0">
+    </feature>
+</registry>
+`
+
+// TestValidateSpec checks that the shape of the XML and the registry are
+// validated together, leaving out the variants of other APIs.
+func TestValidateSpec(t *testing.T) {
+	errs, err := validateSpec(strings.NewReader(validateTestSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Error())
+	}
+	want := []string{
+		"21: registry/feature VK_VERSION_1_0: missing api attribute",
+		"struct VkBar: defined more than once",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("errors:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
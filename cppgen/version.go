@@ -0,0 +1,48 @@
+package cppgen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// versionNamespace names the inline namespace the header is generated in
+// after the newest API version of the registry and its VK_HEADER_VERSION,
+// v1_3_280, so that code built against headers of different specs doesn't
+// link together silently. Returns "" if the registry lacks either.
+func versionNamespace(reg *registry.Registry) string {
+	major, minor := -1, -1
+	for _, f := range reg.Features {
+		var fmajor, fminor int
+		if _, err := fmt.Sscanf(f.Number, "%d.%d", &fmajor, &fminor); err != nil {
+			continue
+		}
+		if fmajor > major || (fmajor == major && fminor > minor) {
+			major, minor = fmajor, fminor
+		}
+	}
+	patch := headerVersion(reg)
+	if major < 0 || patch < 0 {
+		return ""
+	}
+	return fmt.Sprintf("v%d_%d_%d", major, minor, patch)
+}
+
+// headerVersion returns the VK_HEADER_VERSION of the registry, -1 if it
+// lacks one.
+func headerVersion(reg *registry.Registry) int {
+	for _, t := range reg.Types.Type {
+		if t.Category == "define" && t.InnerName == "VK_HEADER_VERSION" {
+			fields := strings.Fields(t.Text)
+			if len(fields) > 0 {
+				if v, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+					return v
+				}
+			}
+			break
+		}
+	}
+	return -1
+}
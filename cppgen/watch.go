@@ -0,0 +1,89 @@
+package cppgen
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// watchInterval is how often the watched files are checked for changes.
+const watchInterval = 500 * time.Millisecond
+
+// watch runs vulkangen with args, less -watch, now and each time one of
+// files changes, or a *.tmpl file in dirs is added, removed or changed.
+// Failures of a run are logged, watch only returns if vulkangen itself
+// can't be started.
+func watch(args, files, dirs []string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	// set rather than left out so that -config doesn't turn it on again
+	args = append([]string{"-watch=false"}, withoutWatch(args)...)
+	last := ""
+	for {
+		state := watchState(files, dirs)
+		if state == last {
+			time.Sleep(watchInterval)
+			continue
+		}
+		if last != "" {
+			// editors write files in several steps, wait until they're done
+			for time.Sleep(watchInterval); state != watchState(files, dirs); time.Sleep(watchInterval) {
+				state = watchState(files, dirs)
+			}
+		}
+		last = state
+		cmd := exec.Command(exe, args...)
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		start := time.Now()
+		err := cmd.Run()
+		if _, ok := err.(*exec.ExitError); err != nil && !ok {
+			return err
+		}
+		if err != nil {
+			log.Printf("-watch: generation failed (%v), waiting for changes", err)
+		} else {
+			log.Printf("-watch: generated in %v, waiting for changes", time.Since(start).Round(time.Millisecond))
+		}
+	}
+}
+
+// withoutWatch removes -watch from args, up to a "--" ending the flags.
+func withoutWatch(args []string) []string {
+	var out []string
+	for i, a := range args {
+		if a == "--" {
+			return append(out, args[i:]...)
+		}
+		if name := strings.TrimPrefix(strings.TrimPrefix(a, "-"), "-"); name == "watch" || strings.HasPrefix(name, "watch=") {
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// watchState describes the size and modification time of the files and of
+// the *.tmpl files in dirs, it changes whenever they do.
+func watchState(files, dirs []string) string {
+	var all []string
+	all = append(all, files...)
+	for _, dir := range dirs {
+		tmpls, _ := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+		all = append(all, tmpls...)
+	}
+	var b strings.Builder
+	for _, f := range all {
+		if fi, err := os.Stat(f); err == nil {
+			fmt.Fprintf(&b, "%s %v %d\n", f, fi.ModTime(), fi.Size())
+		} else {
+			fmt.Fprintf(&b, "%s missing\n", f)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,118 @@
+package cppgen
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nsf/vulkangen/registry"
+)
+
+// builtin C types, these don't have to be declared in the registry
+var cBuiltinTypes = map[string]bool{
+	"void":     true,
+	"char":     true,
+	"int":      true,
+	"float":    true,
+	"double":   true,
+	"size_t":   true,
+	"int8_t":   true,
+	"uint8_t":  true,
+	"int16_t":  true,
+	"uint16_t": true,
+	"int32_t":  true,
+	"uint32_t": true,
+	"int64_t":  true,
+	"uint64_t": true,
+}
+
+// typeReferences returns the types referenced by a struct/union (member
+// types) or a command (return and parameter types).
+func typeReferences(reg *registry.Registry) map[string][]string {
+	refs := map[string][]string{}
+	for _, t := range reg.Types.Type {
+		if t.Category != "struct" && t.Category != "union" {
+			continue
+		}
+		name := xmlTypeEntityName(&t)
+		for _, m := range t.Members {
+			refs[name] = append(refs[name], m.Type)
+		}
+	}
+	for _, c := range reg.Commands.Command {
+		name := xmlCommandEntityName(&c)
+		refs[name] = append(refs[name], c.Proto.Type)
+		for _, p := range c.Params {
+			refs[name] = append(refs[name], p.Type)
+		}
+	}
+	return refs
+}
+
+// crossReference makes sure the entities left after filtering don't
+// reference anything that was filtered out (filtered maps entity name to
+// its exclusion). With pullIn the transitive closure of referenced types is
+// put back into the registry, otherwise the referencing structs and commands
+// are excluded as well. References to types the registry doesn't declare at
+// all can't be pulled in and always cause exclusion, as skipped. Filtered
+// entities are removed from the registry. Returns what was pulled in, "X:
+// referenced by Y", and what was removed, sorted.
+func crossReference(reg *registry.Registry, filtered map[string]Exclusion, pullIn bool) (pulled []string, excluded []Exclusion) {
+	refs := typeReferences(reg)
+	known := map[string]bool{}
+	for _, t := range reg.Types.Type {
+		if name := xmlTypeEntityName(&t); name != "" {
+			known[name] = true
+		}
+	}
+
+	if pullIn {
+		// kept entities in registry order, pulled in ones are appended
+		var queue []string
+		for _, t := range reg.Types.Type {
+			name := xmlTypeEntityName(&t)
+			if _, ok := filtered[name]; !ok {
+				queue = append(queue, name)
+			}
+		}
+		for _, c := range reg.Commands.Command {
+			name := xmlCommandEntityName(&c)
+			if _, ok := filtered[name]; !ok {
+				queue = append(queue, name)
+			}
+		}
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			for _, ref := range refs[name] {
+				if _, ok := filtered[ref]; !ok {
+					continue
+				}
+				delete(filtered, ref)
+				pulled = append(pulled, fmt.Sprintf("%s: referenced by %s", ref, name))
+				queue = append(queue, ref)
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(refs) {
+		for _, ref := range refs[name] {
+			if ref == "" || known[ref] || cBuiltinTypes[ref] {
+				continue
+			}
+			if _, ok := filtered[ref]; !ok {
+				filtered[ref] = Exclusion{Entity: ref, Kind: "skipped", Reason: fmt.Sprintf("%s: not declared in the registry", ref)}
+			}
+		}
+	}
+	sort.Strings(pulled)
+	return pulled, excludeEntities(reg, filtered)
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
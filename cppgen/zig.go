@@ -0,0 +1,364 @@
+package cppgen
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ZigModule is the Zig file of -lang zig: extern structs and unions,
+// non-exhaustive enums, bitmasks as packed structs of bools, the function
+// pointer types and extern declarations of the commands and a loader table.
+// It targets Zig 0.14. Names follow the C++ header, with Zig casing: types
+// without the Vk prefix, snake_case members and values, camelCase
+// functions.
+type ZigModule struct {
+	Banner    []string
+	Constants []RustConstant
+	Handles   []RustHandle
+	Enums     []ZigEnum
+	BitMasks  []ZigBitMask
+	Structs   []ZigStruct
+	Aliases   []Alias
+	Commands  []ZigCommand
+
+	// C types of the registry which aren't generated, replaced with
+	// anyopaque behind pointers and leaving out what uses them by value
+	Missing []string
+}
+
+// ZigEnum is an enum, Aliases are the values numbered like one before them,
+// which a Zig enum can't have as fields.
+type ZigEnum struct {
+	Name    string
+	Values  []RustConstant
+	Aliases []RustConstant
+}
+
+// ZigBitMask is a bitmask packed in Repr, Bits has a name for each of its
+// 32 or 64 bits, those not defined by the registry are reserved. Values are
+// the other values of its enum of bits, those of several bits or none, as
+// numbers.
+type ZigBitMask struct {
+	Name     string
+	Repr     string
+	FlagBits string
+	Bits     []string
+	Values   []RustConstant
+}
+
+type ZigStruct struct {
+	Name   string
+	Union  bool
+	Fields []ZigField
+}
+
+// ZigField is a struct member or command parameter, Default is the value
+// of a struct member if it's left out, "" for none.
+type ZigField struct {
+	Name    string
+	Type    string
+	Default string
+}
+
+type ZigCommand struct {
+	VkName string
+	Name   string
+	Type   string
+	Params []ZigField
+	Ret    string
+}
+
+// zigKeywords are the keywords and primitive values of Zig, identifiers
+// named like them are written as @"name".
+var zigKeywords = map[string]bool{
+	"addrspace": true, "align": true, "allowzero": true, "and": true,
+	"anyframe": true, "anytype": true, "asm": true, "break": true,
+	"callconv": true, "catch": true, "comptime": true, "const": true,
+	"continue": true, "defer": true, "else": true, "enum": true,
+	"errdefer": true, "error": true, "export": true, "extern": true,
+	"fn": true, "for": true, "if": true, "inline": true,
+	"linksection": true, "noalias": true, "noinline": true,
+	"nosuspend": true, "opaque": true, "or": true, "orelse": true,
+	"packed": true, "pub": true, "resume": true, "return": true,
+	"struct": true, "suspend": true, "switch": true, "test": true,
+	"threadlocal": true, "try": true, "union": true, "unreachable": true,
+	"usingnamespace": true, "var": true, "volatile": true, "while": true,
+	"null": true, "undefined": true, "true": true, "false": true,
+	"type": true, "void": true, "bool": true, "noreturn": true,
+	"anyerror": true, "anyopaque": true,
+}
+
+// zigIntType matches the names of the integer and float types, which are
+// primitives too.
+var zigIntType = regexp.MustCompile(`^[iuf][0-9]+$`)
+
+// zigCTypes are the C types vk.xml uses and their Zig equivalents.
+var zigCTypes = map[string]string{
+	"void":     "anyopaque",
+	"char":     "u8",
+	"int":      "c_int",
+	"float":    "f32",
+	"double":   "f64",
+	"size_t":   "usize",
+	"int8_t":   "i8",
+	"uint8_t":  "u8",
+	"int16_t":  "i16",
+	"uint16_t": "u16",
+	"int32_t":  "i32",
+	"uint32_t": "u32",
+	"int64_t":  "i64",
+	"uint64_t": "u64",
+
+	// the basetypes of vk.xml
+	"VkBool32":        "Bool32",
+	"VkFlags":         "Flags",
+	"VkFlags64":       "Flags64",
+	"VkDeviceSize":    "DeviceSize",
+	"VkDeviceAddress": "DeviceAddress",
+	"VkSampleMask":    "SampleMask",
+
+	"PFN_vkVoidFunction": "PfnVoidFunction",
+}
+
+// zigIdent makes an identifier of name, quoting it if it's a keyword.
+func zigIdent(name string) string {
+	if zigKeywords[name] || zigIntType.MatchString(name) {
+		return `@"` + name + `"`
+	}
+	return name
+}
+
+// zigTypes maps the C types to the Zig types generated for them.
+type zigTypes struct {
+	names   map[string]string
+	missing map[string]bool
+}
+
+// zigType returns the Zig type of a member or parameter of C type ct.
+// lenAttr is the len attribute telling pointers to many elements and
+// strings from pointers to one, const char * without one are strings too.
+// Arrays of parameters are passed as pointers. ok is false if its type is
+// used by value but isn't generated, or it's a bit-field, which extern
+// structs can't have.
+func (zt *zigTypes) zigType(ct CType, lenAttr string, param bool) (string, bool) {
+	base, ok := zt.names[ct.Type]
+	switch {
+	case !ok && strings.HasPrefix(ct.Type, "PFN_"):
+		// all function pointers are passed the same way, their signatures
+		// aren't generated
+		base, ok = "PfnVoidFunction", true
+	case !ok:
+		zt.missing[ct.Type] = true
+		base = "anyopaque"
+	}
+	if ct.BitWidth > 0 {
+		return "", false
+	}
+	t := ""
+	for _, d := range ct.Dims {
+		t += "[" + strings.TrimPrefix(d, "VK_") + "]"
+	}
+	levels := ct.Pointers
+	if t != "" && param {
+		// decayed to a pointer in C
+		if ct.Const {
+			t = "*const " + t
+		} else {
+			t = "*" + t
+		}
+		levels = nil
+	}
+	t += base
+	// len lists the lengths of the pointers outermost first
+	var lens []string
+	if lenAttr != "" {
+		lens = strings.Split(lenAttr, ",")
+	}
+	for i, isConst := range levels {
+		kind := "*"
+		if j := len(levels) - 1 - i; j < len(lens) && t != "anyopaque" {
+			kind = "[*]"
+			if lens[j] == "null-terminated" {
+				kind = "[*:0]"
+			}
+		} else if ct.Type == "char" && isConst && i == 0 {
+			kind = "[*:0]"
+		}
+		if isConst {
+			t = "?" + kind + "const " + t
+		} else {
+			t = "?" + kind + t
+		}
+	}
+	return t, ok || len(levels) > 0
+}
+
+// zigEnumValues converts the values of an enum, those without a number are
+// left out.
+func zigEnumValues(e *Enum, flagBits bool) []RustConstant {
+	var out []RustConstant
+	for i, v := range rustEnumValues(e, flagBits) {
+		if v.Name == "" {
+			continue
+		}
+		out = append(out, RustConstant{Name: zigIdent(strings.ToLower(v.Name)), Value: fmt.Sprint(e.Values[i].Number)})
+	}
+	return out
+}
+
+func newZigModule(params *HeaderParams, ctx *Context) *ZigModule {
+	m := &ZigModule{Banner: params.Banner}
+	zt := &zigTypes{names: map[string]string{}, missing: map[string]bool{}}
+	for k, v := range zigCTypes {
+		zt.names[k] = v
+	}
+	for _, c := range ctx.Constants {
+		if rc, ok := rustConstant(c.Name, c.Value); ok {
+			if strings.HasPrefix(rc.Value, "!") {
+				rc.Value = fmt.Sprintf("~@as(%s, %s)", rc.Type, rc.Value[1:])
+			}
+			m.Constants = append(m.Constants, rc)
+		}
+	}
+	for _, h := range ctx.Handles {
+		name := strings.TrimPrefix(h.VkName, "Vk")
+		zt.names[h.VkName] = name
+		m.Handles = append(m.Handles, RustHandle{Name: name, Dispatchable: h.TypeSafe})
+	}
+	bitEnums, bitMasks := map[string]bool{}, map[string]bool{}
+	for _, bm := range ctx.BitMasks {
+		name := strings.TrimPrefix(bm.VkName, "Vk")
+		zt.names[bm.VkName] = name
+		bitMasks[bm.VkName] = true
+		b := ZigBitMask{Name: name, Repr: "Flags", Bits: make([]string, bm.BitWidth)}
+		if bm.BitWidth == 64 {
+			b.Repr = "Flags64"
+		}
+		if bm.Enum != nil && bm.Enum.VkName != "" {
+			bitEnums[bm.Enum.VkName] = true
+			b.FlagBits = strings.TrimPrefix(bm.Enum.VkName, "Vk")
+			zt.names[bm.Enum.VkName] = b.FlagBits
+			for _, v := range zigEnumValues(bm.Enum, true) {
+				n, _ := strconv.ParseInt(v.Value, 10, 64)
+				if bit := bits.TrailingZeros64(uint64(n)); bits.OnesCount64(uint64(n)) == 1 && bit < len(b.Bits) && b.Bits[bit] == "" {
+					b.Bits[bit] = v.Name
+				} else {
+					b.Values = append(b.Values, RustConstant{Name: v.Name, Value: fmt.Sprintf("0x%x", uint64(n))})
+				}
+			}
+		}
+		for i := range b.Bits {
+			if b.Bits[i] == "" {
+				b.Bits[i] = fmt.Sprintf("_reserved_bit_%d", i)
+			}
+		}
+		m.BitMasks = append(m.BitMasks, b)
+	}
+	for i := range ctx.Enums {
+		e := &ctx.Enums[i]
+		if bitEnums[e.VkName] {
+			continue
+		}
+		name := strings.TrimPrefix(e.VkName, "Vk")
+		zt.names[e.VkName] = name
+		ze := ZigEnum{Name: name}
+		seen := map[string]string{}
+		for _, v := range zigEnumValues(e, false) {
+			if first, ok := seen[v.Value]; ok {
+				ze.Aliases = append(ze.Aliases, RustConstant{Name: v.Name, Value: first})
+				continue
+			}
+			seen[v.Value] = v.Name
+			ze.Values = append(ze.Values, v)
+		}
+		m.Enums = append(m.Enums, ze)
+	}
+	// C++ name -> Zig name of the StructureType values
+	sTypes := map[string]string{}
+	for i := range ctx.Enums {
+		if e := &ctx.Enums[i]; e.VkName == "VkStructureType" {
+			for j, v := range rustEnumValues(e, false) {
+				sTypes[e.Values[j].Name] = zigIdent(strings.ToLower(v.Name))
+			}
+		}
+	}
+	// those using a struct left out by value are left out too
+	for _, s := range ctx.Structs {
+		zs := ZigStruct{Name: strings.TrimPrefix(s.VkName, "Vk"), Union: s.Union}
+		ok := true
+		for _, mem := range s.Members {
+			t, known := zt.zigType(mem.AnalyzedType.CType, mem.Len, false)
+			if !known {
+				ok = false
+				break
+			}
+			f := ZigField{Name: zigIdent(toLowerSnakeCase(mem.Name)), Type: t}
+			switch {
+			case s.Union:
+				// only one member of a union can have a default
+			case mem.Name == "sType" && s.HasSType && sTypes[s.SType] != "":
+				f.Default = "." + sTypes[s.SType]
+			case strings.HasPrefix(t, "?"):
+				f.Default = "null"
+			case bitMasks[mem.AnalyzedType.Type] && !mem.AnalyzedType.IsPointer && !mem.AnalyzedType.IsArray:
+				f.Default = ".{}"
+			}
+			zs.Fields = append(zs.Fields, f)
+		}
+		if !ok {
+			continue
+		}
+		zt.names[s.VkName] = zs.Name
+		m.Structs = append(m.Structs, zs)
+	}
+	for _, a := range ctx.TypeAliases {
+		if vk := "Vk" + a.Target; zt.names[vk] != "" {
+			zt.names["Vk"+a.Name] = a.Name
+			m.Aliases = append(m.Aliases, Alias{Name: a.Name, Target: a.Target})
+		}
+	}
+	for _, c := range ctx.Commands {
+		name := strings.TrimPrefix(c.VkName, "vk")
+		zc := ZigCommand{VkName: c.VkName, Name: convertCommandName(c.VkName), Type: "Pfn" + name, Ret: "void"}
+		ok := true
+		for _, p := range c.Parameters {
+			t, known := zt.zigType(p.AnalyzedType.CType, p.Len, true)
+			if !known {
+				ok = false
+				break
+			}
+			zc.Params = append(zc.Params, ZigField{Name: zigIdent(toLowerSnakeCase(p.Name)), Type: t})
+		}
+		if c.RetVkType != "void" {
+			t, known := zt.zigType(CType{Type: c.RetVkType}, "", false)
+			ok = ok && known
+			zc.Ret = t
+		}
+		if ok {
+			m.Commands = append(m.Commands, zc)
+		}
+	}
+	for t := range zt.missing {
+		m.Missing = append(m.Missing, t)
+	}
+	sort.Strings(m.Missing)
+	return m
+}
+
+// zigBackend is -lang zig.
+type zigBackend struct{}
+
+func init() {
+	RegisterBackend(zigBackend{})
+}
+
+func (zigBackend) Name() string { return "zig" }
+
+func (zigBackend) Generate(w io.Writer, ctx *Context, opts Options) error {
+	return ctx.execute(w, "zig", newZigModule(ctx.Params, ctx))
+}
@@ -0,0 +1,3 @@
+module github.com/nsf/vulkangen
+
+go 1.20
@@ -0,0 +1,9 @@
+// Command vk_cpp_generator converts the Vulkan XML registry into a C++
+// header, see the cppgen package.
+package main
+
+import "github.com/nsf/vulkangen/cppgen"
+
+func main() {
+	cppgen.Main()
+}
@@ -0,0 +1,177 @@
+package registry
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Parse decodes the registry from the spec text read from r.
+func Parse(r io.Reader) (*Registry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parse(data, "line ")
+}
+
+// ReadFile decodes the registry from the spec file. The file is memory
+// mapped where possible and tokenized in place, only the decoded values are
+// copied out of it.
+func ReadFile(filename string) (*Registry, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, release, err := mapFile(f)
+	if err != nil {
+		return nil, err
+	}
+	registry, err := parse(data, filename+":")
+	if rerr := release(); err == nil {
+		err = rerr
+	}
+	return registry, err
+}
+
+// parse decodes the registry from the spec text, errors are prefixed with
+// where followed by the line.
+func parse(data []byte, where string) (*Registry, error) {
+	t := newSpecTokenizer(data)
+	registry, err := decode(t)
+	if serr, ok := err.(*xml.SyntaxError); ok {
+		// the decoder doesn't know lines of a token reader
+		err = fmt.Errorf("%s%d: XML syntax error: %s", where, t.line(), serr.Msg)
+	} else if err != nil {
+		err = fmt.Errorf("%s%d: %v", where, t.line(), err)
+	}
+	return registry, err
+}
+
+// readFile is mapFile for files which can't be mapped
+func readFile(f *os.File) ([]byte, func() error, error) {
+	data, err := io.ReadAll(f)
+	return data, func() error { return nil }, err
+}
+
+// decode reads the registry from r element by element, so that no
+// DOM of the spec is held in memory. Only
+// the elements Registry describes are decoded, everything else is skipped
+// without allocating.
+func decode(r xml.TokenReader) (*Registry, error) {
+	var registry Registry
+	d := xml.NewTokenDecoder(r)
+
+	// decodeChildren decodes each <name> child of the current element with
+	// decode, skipping other children, until the element ends
+	decodeChildren := func(name string, decode func(start *xml.StartElement) error) error {
+		for {
+			tok, err := d.Token()
+			if err != nil {
+				return err
+			}
+			switch t := tok.(type) {
+			case xml.StartElement:
+				if t.Name.Local != name {
+					if err := d.Skip(); err != nil {
+						return err
+					}
+					continue
+				}
+				if err := decode(&t); err != nil {
+					return err
+				}
+			case xml.EndElement:
+				return nil
+			}
+		}
+	}
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return &registry, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "registry":
+			// descend
+		case "types":
+			err = decodeChildren("type", func(s *xml.StartElement) error {
+				var t Type
+				if err := d.DecodeElement(&t, s); err != nil {
+					return err
+				}
+				registry.Types.Type = append(registry.Types.Type, t)
+				return nil
+			})
+		case "enums":
+			var e Enums
+			err = d.DecodeElement(&e, &start)
+			registry.Enums = append(registry.Enums, e)
+		case "feature":
+			var f Feature
+			err = d.DecodeElement(&f, &start)
+			registry.Features = append(registry.Features, f)
+		case "commands":
+			err = decodeChildren("command", func(s *xml.StartElement) error {
+				var c Command
+				if err := d.DecodeElement(&c, s); err != nil {
+					return err
+				}
+				registry.Commands.Command = append(registry.Commands.Command, c)
+				return nil
+			})
+		case "extensions":
+			err = decodeChildren("extension", func(s *xml.StartElement) error {
+				var e Extension
+				if err := d.DecodeElement(&e, s); err != nil {
+					return err
+				}
+				registry.Extensions.Extension = append(registry.Extensions.Extension, e)
+				return nil
+			})
+		case "spirvextensions":
+			err = decodeChildren("spirvextension", func(s *xml.StartElement) error {
+				var e SpirvEntry
+				if err := d.DecodeElement(&e, s); err != nil {
+					return err
+				}
+				registry.SpirvExtensions.SpirvExtension = append(registry.SpirvExtensions.SpirvExtension, e)
+				return nil
+			})
+		case "spirvcapabilities":
+			err = decodeChildren("spirvcapability", func(s *xml.StartElement) error {
+				var e SpirvEntry
+				if err := d.DecodeElement(&e, s); err != nil {
+					return err
+				}
+				registry.SpirvCapabilities.SpirvCapability = append(registry.SpirvCapabilities.SpirvCapability, e)
+				return nil
+			})
+		case "sync":
+			err = d.DecodeElement(&registry.Sync, &start)
+		case "platforms":
+			err = d.DecodeElement(&registry.Platforms, &start)
+		case "comment":
+			var comment string
+			err = d.DecodeElement(&comment, &start)
+			if registry.Comment == "" {
+				registry.Comment = comment
+			}
+		default:
+			err = d.Skip()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testRegistry = `<?xml version="1.0" encoding="UTF-8"?>
+<registry>
+    <comment>Copyright 2024</comment>
+    <comment>not the copyright</comment>
+    <platforms>
+        <platform name="xlib" protect="VK_USE_PLATFORM_XLIB_KHR"/>
+    </platforms>
+    <tags><tag name="KHR"/></tags>
+    <types>
+        <type category="define">#define <name>VK_HEADER_VERSION</name> 280</type>
+        <type requires="VkFooFlagBits" category="bitmask">typedef <type>VkFlags</type> <name>VkFooFlags</name>;</type>
+        <type category="struct" name="VkFoo" structextends="VkBar">
+            <member values="VK_STRUCTURE_TYPE_FOO"><type>VkStructureType</type> <name>sType</name></member>
+            <member>const <type>void</type>* <name>pNext</name></member>
+            <member len="count"><type>float</type> <name>values</name>[<enum>VK_MAX</enum>]</member>
+        </type>
+        <type category="struct" name="VkFoo2" alias="VkFoo"/>
+        <unknown><type name="skipped"/></unknown>
+    </types>
+    <enums name="VkFooFlagBits" type="bitmask" bitwidth="64">
+        <enum bitpos="63" name="VK_FOO_LAST_BIT"/>
+        <enum value="0x2" name="VK_FOO_SECOND_BIT"/>
+    </enums>
+    <commands>
+        <command successcodes="VK_SUCCESS">
+            <proto><type>VkResult</type> <name>vkFoo</name></proto>
+            <param>const <type>VkFoo</type>* <name>pFoo</name></param>
+        </command>
+        <command name="vkFooKHR" alias="vkFoo"/>
+    </commands>
+    <feature name="VK_VERSION_1_0" number="1.0">
+        <require><type name="VkFoo"/><command name="vkFoo"/></require>
+    </feature>
+    <extensions>
+        <extension name="VK_KHR_foo" number="2" supported="vulkan" depends="VK_VERSION_1_1">
+            <require>
+                <enum offset="1" extends="VkStructureType" dir="-" name="VK_STRUCTURE_TYPE_FOO"/>
+                <command name="vkFooKHR"/>
+            </require>
+            <require api="vulkansc">
+                <type name="VkFooSC"/>
+                <enum value="1" name="VK_FOO_SC" api="vulkan,vulkansc"/>
+            </require>
+        </extension>
+    </extensions>
+</registry>
+`
+
+func TestParse(t *testing.T) {
+	reg, err := Parse(strings.NewReader(testRegistry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reg.Comment != "Copyright 2024" {
+		t.Errorf("Comment = %q, want the first comment", reg.Comment)
+	}
+	if p := reg.Platforms.Platform; len(p) != 1 || p[0].Protect != "VK_USE_PLATFORM_XLIB_KHR" {
+		t.Errorf("Platforms = %+v", p)
+	}
+
+	types := reg.Types.Type
+	if len(types) != 4 {
+		t.Fatalf("got %d types, want 4 without the unknown element's", len(types))
+	}
+	if d := types[0]; d.InnerName != "VK_HEADER_VERSION" || !strings.Contains(d.Text, "280") {
+		t.Errorf("define = %+v", d)
+	}
+	if b := types[1]; b.InnerName != "VkFooFlags" || b.InnerType != "VkFlags" || b.Requires != "VkFooFlagBits" {
+		t.Errorf("bitmask = %+v", b)
+	}
+	s := types[2]
+	if s.Name != "VkFoo" || s.StructExtends != "VkBar" || len(s.Members) != 3 {
+		t.Fatalf("struct = %+v", s)
+	}
+	wantMembers := []TypeName{
+		{Type: "VkStructureType", Name: "sType", Extra: " ", Values: "VK_STRUCTURE_TYPE_FOO"},
+		{Type: "void", Name: "pNext", Extra: "const * "},
+		{Type: "float", Name: "values", Enum: "VK_MAX", Len: "count", Extra: " []"},
+	}
+	if !reflect.DeepEqual(s.Members, wantMembers) {
+		t.Errorf("members =\n%+v\nwant\n%+v", s.Members, wantMembers)
+	}
+	if a := types[3]; a.Alias != "VkFoo" {
+		t.Errorf("alias = %+v", a)
+	}
+
+	if len(reg.Enums) != 1 || reg.Enums[0].BitWidth != 64 || len(reg.Enums[0].Values) != 2 {
+		t.Fatalf("Enums = %+v", reg.Enums)
+	}
+	if n, ok := EnumValueNumber(reg.Enums[0].Values[0].Value, reg.Enums[0].Values[0].BitPos); !ok || uint64(n) != 1<<63 {
+		t.Errorf("value of bit 63 = %#x, %v", uint64(n), ok)
+	}
+
+	cmds := reg.Commands.Command
+	if len(cmds) != 2 || cmds[0].Proto.Name != "vkFoo" || cmds[0].SuccessCodes != "VK_SUCCESS" || len(cmds[0].Params) != 1 || cmds[1].Alias != "vkFoo" {
+		t.Errorf("Commands = %+v", cmds)
+	}
+	if f := reg.Features; len(f) != 1 || len(f[0].Require.Types) != 1 || len(f[0].Require.Commands) != 1 {
+		t.Errorf("Features = %+v", f)
+	}
+
+	exts := reg.Extensions.Extension
+	if len(exts) != 1 || exts[0].Number != 2 || exts[0].Depends != "VK_VERSION_1_1" {
+		t.Fatalf("Extensions = %+v", exts)
+	}
+	req := exts[0].Require
+	if len(req.Types) != 1 || req.Types[0].API != "vulkansc" || len(req.Enums) != 2 || req.Enums[0].API != "" || req.Enums[1].API != "vulkan,vulkansc" || req.Commands[0].API != "" {
+		t.Errorf("the api of the require blocks of %s = %+v", exts[0].Name, req)
+	}
+	re := req.Enums[0]
+	if n, ok := re.Number(exts[0].Number); !ok || n != -1000001001 {
+		t.Errorf("value of %s = %d, %v, want -1000001001", re.Name, n, ok)
+	}
+}
+
+// TestReadFile checks that the mapped file decodes as the text read, the
+// strings of the registry must not point into the released mapping.
+func TestReadFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "vk.xml")
+	if err := os.WriteFile(name, []byte(testRegistry), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := Parse(strings.NewReader(testRegistry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadFile = %+v\nwant %+v", got, want)
+	}
+
+	if err := os.WriteFile(name, []byte("<registry>\n<types>\n<type name=x/>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ReadFile(name)
+	if want := name + ":3: XML syntax error"; err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("ReadFile of a broken file = %v, want %s...", err, want)
+	}
+}
+
+func TestParseEnumValue(t *testing.T) {
+	for _, c := range []struct {
+		in   string
+		want int64
+	}{
+		{"0", 0},
+		{"42", 42},
+		{"-3", -3},
+		{"0x7FFFFFFF", 0x7FFFFFFF},
+		{"256U", 256},
+		{"(~0U)", 0xFFFFFFFF},
+		{"(~1U)", 0xFFFFFFFE},
+		{"(~0ULL)", -1},
+		{" 16 ", 16},
+	} {
+		if got, err := ParseEnumValue(c.in); err != nil || got != c.want {
+			t.Errorf("ParseEnumValue(%q) = %d, %v, want %d", c.in, got, err, c.want)
+		}
+	}
+	for _, in := range []string{"", "1000.0F", "VK_MAX_EXTENSION_NAME_SIZE", "0x"} {
+		if got, err := ParseEnumValue(in); err == nil {
+			t.Errorf("ParseEnumValue(%q) = %d, want an error", in, got)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package registry
+
+import "os"
+
+// mapFile reads the file, there is no mmap here.
+func mapFile(f *os.File) (data []byte, release func() error, err error) {
+	return readFile(f)
+}
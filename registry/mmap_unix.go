@@ -0,0 +1,26 @@
+//go:build unix
+
+package registry
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile maps the file into memory read-only, release unmaps it.
+func mapFile(f *os.File) (data []byte, release func() error, err error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	size := fi.Size()
+	if size == 0 || int64(int(size)) != size {
+		return readFile(f)
+	}
+	data, err = syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		// not mappable (a pipe, some special file), read it instead
+		return readFile(f)
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
@@ -0,0 +1,44 @@
+package registry
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Paths returns the paths of the elements and attributes the structs of
+// this package decode, as in "registry/feature/require/enum@extends".
+// Attributes are appended to their element with '@'.
+func Paths() []string {
+	var paths []string
+	var walk func(path string, t reflect.Type)
+	walk = func(path string, t reflect.Type) {
+		if t == reflect.TypeOf(Require{}) {
+			// decoded by blocks, see Require.UnmarshalXML
+			t = reflect.TypeOf(requireBlock{})
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("xml")
+			name, flags, _ := strings.Cut(tag, ",")
+			switch {
+			case f.Name == "XMLName":
+			case f.Anonymous && tag == "":
+				walk(path, f.Type)
+			case flags == "attr":
+				paths = append(paths, path+"@"+name)
+			case name != "" && name != "-" && flags == "":
+				child := path + "/" + name
+				paths = append(paths, child)
+				ft := f.Type
+				if ft.Kind() == reflect.Slice {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					walk(child, ft)
+				}
+			}
+		}
+	}
+	walk("registry", reflect.TypeOf(Registry{}))
+	return paths
+}
@@ -0,0 +1,346 @@
+// Package registry decodes the Vulkan API registry, vk.xml and companion
+// specs like video.xml, into the structs of this package. Only the parts
+// the generator uses are decoded.
+package registry
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type Registry struct {
+	XMLName string `xml:"registry"`
+	// the first top-level comment, the copyright notice
+	Comment string `xml:"comment"`
+	Types   struct {
+		Type []Type `xml:"type"`
+	} `xml:"types"`
+	Enums    []Enums   `xml:"enums"`
+	Features []Feature `xml:"feature"`
+	Commands struct {
+		Command []Command `xml:"command"`
+	} `xml:"commands"`
+	Extensions struct {
+		Extension []Extension `xml:"extension"`
+	} `xml:"extensions"`
+	SpirvExtensions struct {
+		SpirvExtension []SpirvEntry `xml:"spirvextension"`
+	} `xml:"spirvextensions"`
+	SpirvCapabilities struct {
+		SpirvCapability []SpirvEntry `xml:"spirvcapability"`
+	} `xml:"spirvcapabilities"`
+	Sync      Sync `xml:"sync"`
+	Platforms struct {
+		Platform []Platform `xml:"platform"`
+	} `xml:"platforms"`
+}
+
+type Extension struct {
+	Name        string  `xml:"name,attr"`
+	Number      int     `xml:"number,attr"`
+	Protect     string  `xml:"protect,attr"`
+	Platform    string  `xml:"platform,attr"`
+	Supported   string  `xml:"supported,attr"`
+	Depends     string  `xml:"depends,attr"`
+	Provisional bool    `xml:"provisional,attr"`
+	Require     Require `xml:"require"`
+
+	// set when the generated header includes the platform header directly,
+	// the protect macro isn't needed then
+	Unguarded bool `xml:"-"`
+}
+
+type Feature struct {
+	Name    string  `xml:"name,attr"`
+	Number  string  `xml:"number,attr"`
+	API     string  `xml:"api,attr"`
+	Require Require `xml:"require"`
+}
+
+// Require accumulates all <require> blocks of a feature or extension. The
+// api of a block is given to the entries in it which have none of their own.
+type Require struct {
+	Types    []RequireName `xml:"type"`
+	Commands []RequireName `xml:"command"`
+	Enums    []RequireEnum `xml:"enum"`
+}
+
+// RequireName is a type or command required by a feature or extension.
+type RequireName struct {
+	Name string `xml:"name,attr"`
+	API  string `xml:"api,attr"`
+}
+
+// requireBlock is a single <require> block, requireEntries drops the
+// UnmarshalXML method of Require decoding it.
+type requireBlock struct {
+	API string `xml:"api,attr"`
+	requireEntries
+}
+
+type requireEntries Require
+
+func (r *Require) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var b requireBlock
+	if err := d.DecodeElement(&b, &start); err != nil {
+		return err
+	}
+	for i := range b.Types {
+		b.Types[i].API = blockAPI(b.Types[i].API, b.API)
+	}
+	for i := range b.Commands {
+		b.Commands[i].API = blockAPI(b.Commands[i].API, b.API)
+	}
+	for i := range b.Enums {
+		b.Enums[i].API = blockAPI(b.Enums[i].API, b.API)
+	}
+	r.Types = append(r.Types, b.Types...)
+	r.Commands = append(r.Commands, b.Commands...)
+	r.Enums = append(r.Enums, b.Enums...)
+	return nil
+}
+
+func blockAPI(api, block string) string {
+	if api == "" {
+		return block
+	}
+	return api
+}
+
+// HasAPI reports whether the comma separated api list of an entity includes
+// api. Entities without a list are part of every API.
+func HasAPI(list, api string) bool {
+	if list == "" {
+		return true
+	}
+	for _, a := range strings.Split(list, ",") {
+		if a == api {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireEnum is either a constant defined by an extension or a value
+// added to an existing enum (when Extends is set)
+type RequireEnum struct {
+	Name      string `xml:"name,attr"`
+	Extends   string `xml:"extends,attr"`
+	Alias     string `xml:"alias,attr"`
+	Value     string `xml:"value,attr"`
+	BitPos    string `xml:"bitpos,attr"`
+	Offset    string `xml:"offset,attr"`
+	ExtNumber string `xml:"extnumber,attr"`
+	Dir       string `xml:"dir,attr"`
+	API       string `xml:"api,attr"`
+}
+
+// Number computes the value of an enumerant added by a feature or extension,
+// ext is the number of the extension it's in (0 for features). ok is false if
+// the value can't be computed.
+func (re *RequireEnum) Number(ext int) (n int64, ok bool) {
+	if re.Offset == "" {
+		return EnumValueNumber(re.Value, re.BitPos)
+	}
+	offset, err := strconv.ParseInt(re.Offset, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	if re.ExtNumber != "" {
+		if ext, err = strconv.Atoi(re.ExtNumber); err != nil {
+			return 0, false
+		}
+	}
+	if ext == 0 {
+		return 0, false
+	}
+	// the extension enumerant value formula from the registry documentation
+	n = 1000000000 + int64(ext-1)*1000 + offset
+	if re.Dir == "-" {
+		n = -n
+	}
+	return n, true
+}
+
+type Command struct {
+	Name         string     `xml:"name,attr"`
+	Alias        string     `xml:"alias,attr"`
+	SuccessCodes string     `xml:"successcodes,attr"`
+	API          string     `xml:"api,attr"`
+	Proto        TypeName   `xml:"proto"`
+	Params       []TypeName `xml:"param"`
+}
+
+type Type struct {
+	Name          string     `xml:"name,attr"`
+	Requires      string     `xml:"requires,attr"`
+	BitValues     string     `xml:"bitvalues,attr"`
+	Category      string     `xml:"category,attr"`
+	Parent        string     `xml:"parent,attr"`
+	ObjTypeEnum   string     `xml:"objtypeenum,attr"`
+	Alias         string     `xml:"alias,attr"`
+	ReturnedOnly  bool       `xml:"returnedonly,attr"`
+	StructExtends string     `xml:"structextends,attr"`
+	API           string     `xml:"api,attr"`
+	Members       []TypeName `xml:"member"`
+	InnerName     string     `xml:"name"`
+	InnerType     string     `xml:"type"`
+
+	// text around the inner elements, the value of defines
+	Text string `xml:",chardata"`
+
+	// declared by a companion registry merged into this one, never set by
+	// decoding
+	External bool `xml:"-"`
+}
+
+type TypeName struct {
+	Type  string `xml:"type"`
+	Name  string `xml:"name"`
+	Enum  string `xml:"enum"`
+	Len   string `xml:"len,attr"`
+	Extra string `xml:",chardata"`
+
+	// C expression of len where it's written in latexmath
+	AltLen string `xml:"altlen,attr"`
+
+	// the values a member may have, the sType of a struct
+	Values string `xml:"values,attr"`
+
+	API string `xml:"api,attr"`
+}
+
+type Enums struct {
+	Name     string `xml:"name,attr"`
+	Type     string `xml:"type,attr"`
+	BitWidth int    `xml:"bitwidth,attr"`
+	Expand   string `xml:"expand,attr"`
+	Values   []Enum `xml:"enum"`
+
+	// declared by a companion registry merged into this one, never set by
+	// decoding
+	External bool `xml:"-"`
+}
+
+type Enum struct {
+	Name   string `xml:"name,attr"`
+	Alias  string `xml:"alias,attr"`
+	Value  string `xml:"value,attr"`
+	BitPos string `xml:"bitpos,attr"`
+	API    string `xml:"api,attr"`
+}
+
+// EnumValueNumber computes the value of an enumerant given by value or bitpos
+// attribute, ok is false if neither is a usable integer.
+func EnumValueNumber(value, bitpos string) (int64, bool) {
+	if bitpos != "" {
+		n, err := strconv.ParseUint(bitpos, 10, 6)
+		if err != nil {
+			return 0, false
+		}
+		return int64(1) << n, true
+	}
+	n, err := ParseEnumValue(value)
+	return n, err == nil
+}
+
+type Platform struct {
+	Name    string `xml:"name,attr"`
+	Protect string `xml:"protect,attr"`
+}
+
+type SpirvEntry struct {
+	Name    string        `xml:"name,attr"`
+	Enables []SpirvEnable `xml:"enable"`
+}
+
+type SpirvEnable struct {
+	Version   string `xml:"version,attr"`
+	Extension string `xml:"extension,attr"`
+	Struct    string `xml:"struct,attr"`
+	Feature   string `xml:"feature,attr"`
+	Property  string `xml:"property,attr"`
+	Member    string `xml:"member,attr"`
+	Value     string `xml:"value,attr"`
+	Requires  string `xml:"requires,attr"`
+}
+
+type Sync struct {
+	Stages    []SyncStage    `xml:"syncstage"`
+	Accesses  []SyncAccess   `xml:"syncaccess"`
+	Pipelines []SyncPipeline `xml:"syncpipeline"`
+}
+
+type SyncStage struct {
+	Name    string `xml:"name,attr"`
+	Support struct {
+		Queues string `xml:"queues,attr"`
+	} `xml:"syncsupport"`
+	Equivalent struct {
+		Stage string `xml:"stage,attr"`
+	} `xml:"syncequivalent"`
+}
+
+type SyncAccess struct {
+	Name    string `xml:"name,attr"`
+	Support struct {
+		Stage string `xml:"stage,attr"`
+	} `xml:"syncsupport"`
+	Equivalent struct {
+		Access string `xml:"access,attr"`
+	} `xml:"syncequivalent"`
+}
+
+type SyncPipeline struct {
+	Name   string `xml:"name,attr"`
+	Stages []struct {
+		Name string `xml:",chardata"`
+	} `xml:"syncpipelinestage"`
+}
+
+// ParseEnumValue interprets an enum value literal as written in the
+// registry: decimal, hex, negative, with C integer suffixes and the (~0U)
+// style complements used by API constants. Complements are computed in the
+// width given by the suffix, (~0ULL) wraps to -1.
+func ParseEnumValue(s string) (int64, error) {
+	lit := strings.TrimSpace(s)
+	if strings.HasPrefix(lit, "(") && strings.HasSuffix(lit, ")") {
+		lit = strings.TrimSpace(lit[1 : len(lit)-1])
+	}
+	complement := strings.HasPrefix(lit, "~")
+	lit = strings.TrimPrefix(lit, "~")
+	wide := false
+	for len(lit) > 0 {
+		c := lit[len(lit)-1]
+		if c == 'L' || c == 'l' {
+			wide = true
+		} else if c != 'U' && c != 'u' {
+			break
+		}
+		lit = lit[:len(lit)-1]
+	}
+	negative := strings.HasPrefix(lit, "-")
+	lit = strings.TrimPrefix(lit, "-")
+	base := 10
+	if strings.HasPrefix(lit, "0x") || strings.HasPrefix(lit, "0X") {
+		base = 16
+		lit = lit[2:]
+	}
+	u, err := strconv.ParseUint(lit, base, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid enum value %q", s)
+	}
+	n := int64(u)
+	if negative {
+		n = -n
+	}
+	if complement {
+		if wide {
+			return ^n, nil
+		}
+		return int64(^uint32(n)), nil
+	}
+	return n, nil
+}
@@ -0,0 +1,273 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"unsafe"
+)
+
+// specTokenizer splits the spec text into XML tokens for xml.NewTokenDecoder,
+// without copying it. Character data is returned as a slice of the text
+// unless it contains entity references, attribute values are strings sharing
+// the text's memory, element and attribute names are interned. The decoder
+// copies whatever it keeps, the text has to stay valid (mapped) only until
+// decoding is done.
+//
+// Comments, processing instructions and the doctype are dropped, the
+// registry decoder doesn't use them.
+type specTokenizer struct {
+	data  []byte
+	pos   int
+	names map[string]string
+
+	// end element to return after a self-closing start element
+	pendingEnd *xml.EndElement
+
+	attrs []xml.Attr
+	buf   []byte
+}
+
+func newSpecTokenizer(data []byte) *specTokenizer {
+	return &specTokenizer{data: data, names: map[string]string{}}
+}
+
+func (t *specTokenizer) Token() (xml.Token, error) {
+	if t.pendingEnd != nil {
+		end := *t.pendingEnd
+		t.pendingEnd = nil
+		return end, nil
+	}
+	for t.pos < len(t.data) {
+		rest := t.data[t.pos:]
+		if rest[0] != '<' {
+			return t.charData()
+		}
+		switch {
+		case bytes.HasPrefix(rest, []byte("<!--")):
+			if err := t.skipPast("-->"); err != nil {
+				return nil, err
+			}
+		case bytes.HasPrefix(rest, []byte("<![CDATA[")):
+			start := t.pos + len("<![CDATA[")
+			t.pos = start
+			if err := t.skipPast("]]>"); err != nil {
+				return nil, err
+			}
+			return xml.CharData(t.data[start : t.pos-len("]]>")]), nil
+		case bytes.HasPrefix(rest, []byte("<?")):
+			if err := t.skipPast("?>"); err != nil {
+				return nil, err
+			}
+		case bytes.HasPrefix(rest, []byte("<!")):
+			if err := t.skipPast(">"); err != nil {
+				return nil, err
+			}
+		case bytes.HasPrefix(rest, []byte("</")):
+			t.pos += 2
+			name := t.name()
+			t.skipSpace()
+			if t.pos >= len(t.data) || t.data[t.pos] != '>' {
+				return nil, t.errorf("expected > after </%s", name)
+			}
+			t.pos++
+			return xml.EndElement{Name: xml.Name{Local: name}}, nil
+		default:
+			return t.startElement()
+		}
+	}
+	return nil, io.EOF
+}
+
+func (t *specTokenizer) startElement() (xml.Token, error) {
+	t.pos++
+	start := xml.StartElement{Name: xml.Name{Local: t.name()}}
+	if start.Name.Local == "" {
+		return nil, t.errorf("expected element name")
+	}
+	t.attrs = t.attrs[:0]
+	for {
+		t.skipSpace()
+		if t.pos >= len(t.data) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		switch t.data[t.pos] {
+		case '>':
+			t.pos++
+			start.Attr = t.copyAttrs()
+			return start, nil
+		case '/':
+			if t.pos+1 >= len(t.data) || t.data[t.pos+1] != '>' {
+				return nil, t.errorf("expected /> in <%s>", start.Name.Local)
+			}
+			t.pos += 2
+			start.Attr = t.copyAttrs()
+			t.pendingEnd = &xml.EndElement{Name: start.Name}
+			return start, nil
+		}
+		attr := xml.Attr{Name: xml.Name{Local: t.name()}}
+		if attr.Name.Local == "" {
+			return nil, t.errorf("expected attribute name in <%s>", start.Name.Local)
+		}
+		t.skipSpace()
+		if t.pos >= len(t.data) || t.data[t.pos] != '=' {
+			return nil, t.errorf("expected = after attribute %s", attr.Name.Local)
+		}
+		t.pos++
+		t.skipSpace()
+		if t.pos >= len(t.data) || t.data[t.pos] != '"' && t.data[t.pos] != '\'' {
+			return nil, t.errorf("expected quoted value of attribute %s", attr.Name.Local)
+		}
+		quote := t.data[t.pos]
+		t.pos++
+		end := bytes.IndexByte(t.data[t.pos:], quote)
+		if end < 0 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		value, err := t.unescape(t.data[t.pos : t.pos+end])
+		if err != nil {
+			return nil, err
+		}
+		attr.Value = value
+		t.pos += end + 1
+		t.attrs = append(t.attrs, attr)
+	}
+}
+
+// copyAttrs returns the collected attributes, the decoder keeps start
+// elements around, they can't share the attrs buffer
+func (t *specTokenizer) copyAttrs() []xml.Attr {
+	if len(t.attrs) == 0 {
+		return nil
+	}
+	return append([]xml.Attr(nil), t.attrs...)
+}
+
+func (t *specTokenizer) charData() (xml.Token, error) {
+	end := bytes.IndexByte(t.data[t.pos:], '<')
+	if end < 0 {
+		end = len(t.data) - t.pos
+	}
+	text := t.data[t.pos : t.pos+end]
+	t.pos += end
+	if bytes.IndexByte(text, '&') < 0 {
+		return xml.CharData(text), nil
+	}
+	s, err := t.unescape(text)
+	if err != nil {
+		return nil, err
+	}
+	return xml.CharData(s), nil
+}
+
+// unescape replaces entity references in s, the result shares the text's
+// memory if there are none
+func (t *specTokenizer) unescape(s []byte) (string, error) {
+	if bytes.IndexByte(s, '&') < 0 {
+		return unsafeString(s), nil
+	}
+	t.buf = t.buf[:0]
+	for len(s) > 0 {
+		i := bytes.IndexByte(s, '&')
+		if i < 0 {
+			t.buf = append(t.buf, s...)
+			break
+		}
+		t.buf = append(t.buf, s[:i]...)
+		s = s[i:]
+		semi := bytes.IndexByte(s, ';')
+		if semi < 0 {
+			return "", t.errorf("unterminated entity reference")
+		}
+		ent := string(s[1:semi])
+		switch ent {
+		case "lt":
+			t.buf = append(t.buf, '<')
+		case "gt":
+			t.buf = append(t.buf, '>')
+		case "amp":
+			t.buf = append(t.buf, '&')
+		case "quot":
+			t.buf = append(t.buf, '"')
+		case "apos":
+			t.buf = append(t.buf, '\'')
+		default:
+			var n uint64
+			var err error
+			if len(ent) > 2 && ent[0] == '#' && (ent[1] == 'x' || ent[1] == 'X') {
+				n, err = strconv.ParseUint(ent[2:], 16, 32)
+			} else if len(ent) > 1 && ent[0] == '#' {
+				n, err = strconv.ParseUint(ent[1:], 10, 32)
+			} else {
+				err = fmt.Errorf("unknown entity")
+			}
+			if err != nil {
+				return "", t.errorf("invalid entity reference &%s;", ent)
+			}
+			t.buf = append(t.buf, string(rune(n))...)
+		}
+		s = s[semi+1:]
+	}
+	return string(t.buf), nil
+}
+
+func (t *specTokenizer) name() string {
+	start := t.pos
+	for t.pos < len(t.data) && isNameByte(t.data[t.pos]) {
+		t.pos++
+	}
+	b := t.data[start:t.pos]
+	// map lookups with string(b) don't allocate
+	if name, ok := t.names[string(b)]; ok {
+		return name
+	}
+	name := string(b)
+	t.names[name] = name
+	return name
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || c == '-' || c == '.' || c == ':' ||
+		c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+func (t *specTokenizer) skipSpace() {
+	for t.pos < len(t.data) {
+		switch t.data[t.pos] {
+		case ' ', '\t', '\n', '\r':
+			t.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (t *specTokenizer) skipPast(end string) error {
+	i := bytes.Index(t.data[t.pos:], []byte(end))
+	if i < 0 {
+		return io.ErrUnexpectedEOF
+	}
+	t.pos += i + len(end)
+	return nil
+}
+
+func (t *specTokenizer) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("XML syntax error: "+format, args...)
+}
+
+// line returns the line of the current position, decoding errors don't
+// carry one with a token reader
+func (t *specTokenizer) line() int {
+	return 1 + bytes.Count(t.data[:t.pos], []byte("\n"))
+}
+
+// unsafeString returns b as a string without copying, b must not change
+// while the string is in use
+func unsafeString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return unsafe.String(&b[0], len(b))
+}
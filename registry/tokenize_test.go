@@ -0,0 +1,105 @@
+package registry
+
+import (
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// tokens returns the tokens of r as xml.Decoder returns them, copied, with
+// the comments, processing instructions and directives specTokenizer drops
+// left out.
+func tokens(t *testing.T, r xml.TokenReader) []xml.Token {
+	t.Helper()
+	d := xml.NewTokenDecoder(r)
+	var toks []xml.Token
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return toks
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch tok.(type) {
+		case xml.Comment, xml.ProcInst, xml.Directive:
+			continue
+		}
+		toks = append(toks, xml.CopyToken(tok))
+	}
+}
+
+func TestSpecTokenizer(t *testing.T) {
+	const spec = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE registry>
+<registry>
+    <!-- Copyright <c> 2024 -->
+    <comment>A &lt;comment&gt; &amp; more</comment>
+    <types comment='single quoted'>
+        <type category="struct" name="VkFoo" structextends="VkBar,VkBaz">
+            <member values="VK_STRUCTURE_TYPE_FOO"><type>VkStructureType</type> <name>sType</name></member>
+            <member optional="true"><type>uint32_t</type>* <name>pValues</name>[<enum>VK_MAX</enum>]</member>
+        </type>
+        <type name="empty"/>
+        <type name = "spaced" ><![CDATA[a < b && c]]></type>
+    </types>
+    <enums name="API Constants"><enum value="(~0U)" name="VK_REMAINING&#x5F;MIP_LEVELS"/></enums>
+</registry>
+`
+	got := tokens(t, newSpecTokenizer([]byte(spec)))
+	want := tokens(t, xml.NewDecoder(strings.NewReader(spec)))
+	if len(want) == 0 {
+		t.Fatal("encoding/xml returned no tokens")
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokens differ from encoding/xml:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestSpecTokenizerInternsNames(t *testing.T) {
+	tok := newSpecTokenizer([]byte(`<type name="a"/><type name="b"/>`))
+	var names []string
+	for {
+		t1, err := tok.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s, ok := t1.(xml.StartElement); ok {
+			names = append(names, s.Name.Local, s.Attr[0].Name.Local)
+		}
+	}
+	if len(names) != 4 {
+		t.Fatalf("got names %q, want 4", names)
+	}
+	// the same names share their memory
+	if unsafe.StringData(names[0]) != unsafe.StringData(names[2]) {
+		t.Errorf("element names %q and %q aren't interned", names[0], names[2])
+	}
+	if unsafe.StringData(names[1]) != unsafe.StringData(names[3]) {
+		t.Errorf("attribute names %q and %q aren't interned", names[1], names[3])
+	}
+}
+
+func TestSpecTokenizerErrors(t *testing.T) {
+	for _, c := range []struct {
+		spec, err string
+	}{
+		{"<registry>\n<types>\n</types <", "line 3: XML syntax error: expected > after </types"},
+		{"<registry>\n<type name=foo/>", "line 2: XML syntax error: expected quoted value of attribute name"},
+		{"<registry>\n\n<type name>", "line 3: XML syntax error: expected = after attribute name"},
+		{"<registry><enum value=\"&bogus;\"/>", "line 1: XML syntax error: invalid entity reference &bogus;"},
+		{"<registry>\n<comment>a &amp b</comment>", "line 2: XML syntax error: unterminated entity reference"},
+		{"<registry>\n<types>\n<type name=\"a\">", "line 3: XML syntax error: unexpected EOF"},
+	} {
+		_, err := Parse(strings.NewReader(c.spec))
+		if err == nil || err.Error() != c.err {
+			t.Errorf("Parse(%q) = %v, want %s", c.spec, err, c.err)
+		}
+	}
+}